@@ -8,6 +8,8 @@ import (
 	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/internal/cli"
 	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/internal/logic/history"
+	"github.com/ct-zh/englishLearn/internal/logic/review"
 	"github.com/ct-zh/englishLearn/internal/logic/sections"
 )
 
@@ -84,7 +86,17 @@ func createApp(configArgs []string) (*cli.App, error) {
 	// 创建业务逻辑服务
 	service := sections.ProvideService(sectionDAO)
 	
+	// 创建练习会话日志DAO
+	sessionDAO := dao.ProvideSessionDAO(daoFactory)
+	
+	// 创建复习服务
+	reviewService := review.ProvideService(sectionDAO, sessionDAO)
+	
+	// 创建修改历史DAO和服务
+	historyDAO := dao.ProvideHistoryDAO(daoFactory)
+	historyService := history.ProvideService(historyDAO)
+	
 	// 创建CLI应用
-	app := cli.ProvideApp(cfg, service, daoFactory)
+	app := cli.ProvideApp(cfg, service, reviewService, historyService, daoFactory)
 	return app, nil
 }
\ No newline at end of file
@@ -8,6 +8,8 @@ import (
 	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/internal/cli"
 	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/internal/logic/history"
+	"github.com/ct-zh/englishLearn/internal/logic/review"
 	"github.com/ct-zh/englishLearn/internal/logic/sections"
 )
 
@@ -20,9 +22,13 @@ func wireApp() (*cli.App, error) {
 		// DAO层
 		dao.ProvideDAOFactory,
 		dao.ProvideSectionDAO,
+		dao.ProvideSessionDAO,
+		dao.ProvideHistoryDAO,
 		
 		// Logic层
 		sections.ProvideService,
+		review.ProvideService,
+		history.ProvideService,
 		
 		// CLI层
 		cli.ProvideApp,
@@ -40,9 +46,13 @@ func wireAppWithArgs(args []string) (*cli.App, error) {
 		// DAO层
 		dao.ProvideDAOFactory,
 		dao.ProvideSectionDAO,
+		dao.ProvideSessionDAO,
+		dao.ProvideHistoryDAO,
 		
 		// Logic层
 		sections.ProvideService,
+		review.ProvideService,
+		history.ProvideService,
 		
 		// CLI层
 		cli.ProvideApp,
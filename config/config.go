@@ -1,16 +1,19 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/ct-zh/englishLearn/model"
 )
 
 // Config 应用配置结构体
 type Config struct {
-	DataFilePath string // JSON数据文件路径
+	DataFilePath string // JSON数据文件路径、每个章节一个文件的词库目录，或.vlog日志存储文件
 	previousPath string // 上一个文件路径，用于回滚
 }
 
@@ -28,8 +31,8 @@ func LoadConfigWithArgs(args []string) (*Config, error) {
 	
 	// 定义命令行参数
 	var dataFile string
-	fs.StringVar(&dataFile, "f", "", "指定JSON数据文件路径")
-	fs.StringVar(&dataFile, "file", "", "指定JSON数据文件路径")
+	fs.StringVar(&dataFile, "f", "", "指定JSON数据文件路径或词库目录")
+	fs.StringVar(&dataFile, "file", "", "指定JSON数据文件路径或词库目录")
 	
 	// 添加帮助信息处理
 	var showHelp bool
@@ -42,12 +45,14 @@ func LoadConfigWithArgs(args []string) (*Config, error) {
 		fmt.Fprintf(os.Stderr, "使用方法:\n")
 		fmt.Fprintf(os.Stderr, "  %s [选项]\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "选项:\n")
-		fmt.Fprintf(os.Stderr, "  -f, --file <文件路径>    指定JSON数据文件路径\n")
+		fmt.Fprintf(os.Stderr, "  -f, --file <文件路径>    指定JSON数据文件路径，以/结尾时为词库目录\n")
 		fmt.Fprintf(os.Stderr, "  -h, --help              显示此帮助信息\n\n")
 		fmt.Fprintf(os.Stderr, "示例:\n")
 		fmt.Fprintf(os.Stderr, "  %s                      使用默认数据文件\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -f custom.json       使用自定义数据文件\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --file /path/to/data.json  使用绝对路径数据文件\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -f vocab/            使用词库目录，每个章节保存为单独的文件\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -f vocab.vlog        使用日志存储，适合很大的词库\n", os.Args[0])
 	}
 	
 	// 解析参数
@@ -78,9 +83,14 @@ func LoadConfigWithArgs(args []string) (*Config, error) {
 			}
 			config.DataFilePath = filepath.Join(wd, dataFile)
 		}
+		cleanup, err := prepareDataPath(dataFile, config.DataFilePath)
+		if err != nil {
+			return nil, err
+		}
 		
 		// 验证指定的文件
 		if err := ValidateDataFile(config.DataFilePath); err != nil {
+			cleanup()
 			// 文件验证失败，强制用户输入有效的JSON文件路径
 			reason := fmt.Sprintf("指定的文件 '%s' 验证失败: %v", config.DataFilePath, err)
 			validPath, promptErr := promptForValidJSONFile(reason)
@@ -148,9 +158,14 @@ func (c *Config) UpdateDataFilePath(newPath string) error {
 		}
 		fullPath = filepath.Join(wd, newPath)
 	}
+	cleanup, err := prepareDataPath(newPath, fullPath)
+	if err != nil {
+		return err
+	}
 	
 	// 验证新文件
 	if err := ValidateDataFile(fullPath); err != nil {
+		cleanup()
 		return fmt.Errorf("新数据文件验证失败: %w", err)
 	}
 	
@@ -202,6 +217,24 @@ func (c *Config) GetFileInfo() (map[string]interface{}, error) {
 	info["exists"] = true
 	info["size"] = fileInfo.Size()
 	info["modified"] = fileInfo.ModTime().Format("2006-01-02 15:04:05")
+	info["storage"] = "file"
+	if fileInfo.IsDir() {
+		fillLibraryInfo(info, c.DataFilePath)
+		return info, nil
+	}
+	if filepath.Ext(c.DataFilePath) == model.LogStoreExt {
+		// 日志存储的章节数需要回放日志才能得到，这里只检查文件头
+		info["storage"] = "log"
+		info["readable"] = true
+		if err := validateLogStore(c.DataFilePath); err != nil {
+			info["error"] = err.Error()
+			return info, nil
+		}
+		info["valid_json"] = true
+		info["schema_version"] = model.CurrentSchemaVersion
+		info["needs_migration"] = false
+		return info, nil
+	}
 	
 	// 尝试读取和解析文件
 	file, err := os.Open(c.DataFilePath)
@@ -222,7 +255,20 @@ func (c *Config) GetFileInfo() (map[string]interface{}, error) {
 	}
 	
 	info["valid_json"] = true
-	info["sections_count"] = len(data)
+	
+	// 版本2起根对象包含version字段，章节保存在sections数组中；版本1的根对象即章节映射
+	version := 1
+	sectionsCount := len(data)
+	if v, ok := data["version"].(float64); ok {
+		version = int(v)
+		sectionsCount = 0
+		if sections, ok := data["sections"].([]interface{}); ok {
+			sectionsCount = len(sections)
+		}
+	}
+	info["schema_version"] = version
+	info["needs_migration"] = version < model.CurrentSchemaVersion
+	info["sections_count"] = sectionsCount
 	
 	return info, nil
 }
@@ -240,6 +286,7 @@ func promptForValidJSONFile(reason string) (string, error) {
 	fmt.Println("  2. 文件扩展名必须是 .json")
 	fmt.Println("  3. 文件内容必须是有效的JSON格式")
 	fmt.Println("  4. JSON根元素必须是对象类型")
+	fmt.Println("也可以输入一个词库目录（包含 index.json 的目录或空目录）。")
 	fmt.Println("")
 	fmt.Println("支持相对路径和绝对路径。")
 	fmt.Println("输入 'quit' 或 'exit' 退出程序。")
@@ -327,13 +374,16 @@ func ValidateDataFile(filePath string) error {
 		return fmt.Errorf("无法访问文件: %w", err)
 	}
 	
-	// 检查是否是文件而不是目录
+	// 目录按词库目录验证
 	if fileInfo.IsDir() {
-		return fmt.Errorf("指定的路径是目录而不是文件: %s", filePath)
+		return validateLibraryDir(filePath)
 	}
 	
 	// 检查文件扩展名
 	ext := filepath.Ext(filePath)
+	if ext == model.LogStoreExt {
+		return validateLogStore(filePath)
+	}
 	if ext != ".json" {
 		return fmt.Errorf("文件必须是JSON格式 (.json)，当前文件: %s", filePath)
 	}
@@ -358,4 +408,132 @@ func ValidateDataFile(filePath string) error {
 	}
 	
 	return nil
-}
\ No newline at end of file
+}
+
+// IsLibraryDir 判断路径是否为词库目录
+func IsLibraryDir(path string) bool {
+	fileInfo, err := os.Stat(path)
+	return err == nil && fileInfo.IsDir()
+}
+
+// prepareDataPath 指定的路径不存在时，按需创建空的存储：
+// 以路径分隔符结尾的路径创建空的词库目录，.vlog文件创建空的日志存储文件
+// 返回的cleanup删除本次创建的文件和目录，验证失败时调用，不会在磁盘上留下空的存储
+func prepareDataPath(input, fullPath string) (cleanup func(), err error) {
+	cleanup = func() {}
+	if _, err := os.Stat(fullPath); !os.IsNotExist(err) {
+		return cleanup, nil
+	}
+
+	isDir := input != "" && (input[len(input)-1] == '/' || input[len(input)-1] == filepath.Separator)
+	if !isDir && filepath.Ext(fullPath) != model.LogStoreExt {
+		return cleanup, nil
+	}
+
+	created := missingAncestor(fullPath)
+	remove := func() { os.RemoveAll(created) }
+	if isDir {
+		if err := os.MkdirAll(fullPath, 0755); err != nil {
+			remove()
+			return cleanup, fmt.Errorf("创建词库目录失败: %w", err)
+		}
+		return remove, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		remove()
+		return cleanup, fmt.Errorf("创建目录失败: %w", err)
+	}
+	if err := os.WriteFile(fullPath, nil, 0644); err != nil {
+		remove()
+		return cleanup, fmt.Errorf("创建数据文件失败: %w", err)
+	}
+	return remove, nil
+}
+
+// missingAncestor 返回path及其上级目录中不存在的最上层路径，即创建path时新建的第一个文件或目录
+func missingAncestor(path string) string {
+	missing := filepath.Clean(path)
+	for {
+		parent := filepath.Dir(missing)
+		if parent == missing {
+			return missing
+		}
+		if _, err := os.Stat(parent); !os.IsNotExist(err) {
+			return missing
+		}
+		missing = parent
+	}
+}
+
+// validateLogStore 验证日志存储文件：为空（新建）或第一行是日志存储的文件头
+func validateLogStore(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("无法打开文件: %w", err)
+	}
+	defer file.Close()
+
+	head := make([]byte, 256)
+	n, _ := file.Read(head)
+	if n == 0 {
+		return nil
+	}
+	if !bytes.Contains(head[:n], []byte(`"format":"`+model.LogStoreFormat+`"`)) {
+		return fmt.Errorf("文件不是有效的%s日志存储文件", model.LogStoreExt)
+	}
+	return nil
+}
+
+// validateLibraryDir 验证词库目录：有索引文件时索引必须是有效的JSON对象；
+// 没有索引文件时目录中不能有其他JSON文件，避免误把普通目录当成词库
+func validateLibraryDir(dir string) error {
+	indexPath := filepath.Join(dir, model.LibraryIndexFileName)
+	raw, err := os.ReadFile(indexPath)
+	if os.IsNotExist(err) {
+		if matches, _ := filepath.Glob(filepath.Join(dir, "*.json")); len(matches) > 0 {
+			return fmt.Errorf("目录 %s 中没有索引文件 %s，不是词库目录", dir, model.LibraryIndexFileName)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("无法读取索引文件: %w", err)
+	}
+
+	var index map[string]interface{}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return fmt.Errorf("索引文件不是有效的JSON对象: %w", err)
+	}
+	return nil
+}
+
+// fillLibraryInfo 填充词库目录的信息
+func fillLibraryInfo(info map[string]interface{}, dir string) {
+	info["storage"] = "directory"
+	info["readable"] = true
+	delete(info, "size") // 目录本身的大小没有意义
+
+	raw, err := os.ReadFile(filepath.Join(dir, model.LibraryIndexFileName))
+	if os.IsNotExist(err) {
+		// 新建的空词库
+		info["valid_json"] = true
+		info["schema_version"] = model.CurrentSchemaVersion
+		info["needs_migration"] = false
+		return
+	}
+	if err != nil {
+		info["readable"] = false
+		info["error"] = fmt.Sprintf("无法读取索引文件: %v", err)
+		return
+	}
+
+	var index model.LibraryIndexDAO
+	if err := json.Unmarshal(raw, &index); err != nil {
+		info["error"] = fmt.Sprintf("索引文件格式错误: %v", err)
+		return
+	}
+	info["valid_json"] = true
+	info["schema_version"] = index.Version
+	info["needs_migration"] = false
+	info["sections_count"] = len(index.Sections)
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/internal/dao"
+	historyLogic "github.com/ct-zh/englishLearn/internal/logic/history"
+	reviewLogic "github.com/ct-zh/englishLearn/internal/logic/review"
 	sectionsLogic "github.com/ct-zh/englishLearn/internal/logic/sections"
 )
 
@@ -37,8 +39,8 @@ func NewApp() *App {
 }
 
 // NewAppWithService 创建带有service的CLI应用 (用于Wire)
-func NewAppWithService(cfg *config.Config, service *sectionsLogic.Service, daoFactory *dao.DAOFactory) *App {
-	builder := NewMenuTreeBuilderWithService(service, daoFactory)
+func NewAppWithService(cfg *config.Config, service *sectionsLogic.Service, reviewService *reviewLogic.Service, historyService *historyLogic.Service, daoFactory *dao.DAOFactory) *App {
+	builder := NewMenuTreeBuilderWithService(service, reviewService, historyService, daoFactory)
 	root := builder.BuildDefaultTree()
 	
 	// 验证菜单树
@@ -58,8 +60,8 @@ func NewAppWithService(cfg *config.Config, service *sectionsLogic.Service, daoFa
 }
 
 // ProvideApp 提供CLI应用实例 (Wire Provider)
-func ProvideApp(cfg *config.Config, service *sectionsLogic.Service, daoFactory *dao.DAOFactory) *App {
-	return NewAppWithService(cfg, service, daoFactory)
+func ProvideApp(cfg *config.Config, service *sectionsLogic.Service, reviewService *reviewLogic.Service, historyService *historyLogic.Service, daoFactory *dao.DAOFactory) *App {
+	return NewAppWithService(cfg, service, reviewService, historyService, daoFactory)
 }
 
 // Run 运行CLI应用
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/ct-zh/englishLearn/internal/cli/commands"
 	"github.com/ct-zh/englishLearn/internal/dao"
+	historyLogic "github.com/ct-zh/englishLearn/internal/logic/history"
+	reviewLogic "github.com/ct-zh/englishLearn/internal/logic/review"
 	sectionsLogic "github.com/ct-zh/englishLearn/internal/logic/sections"
 	"github.com/ct-zh/englishLearn/model"
 )
@@ -22,9 +24,9 @@ func NewMenuTreeBuilder() *MenuTreeBuilder {
 }
 
 // NewMenuTreeBuilderWithService 创建带service的菜单树构建器 (用于Wire)
-func NewMenuTreeBuilderWithService(service *sectionsLogic.Service, daoFactory *dao.DAOFactory) *MenuTreeBuilder {
+func NewMenuTreeBuilderWithService(service *sectionsLogic.Service, reviewService *reviewLogic.Service, historyService *historyLogic.Service, daoFactory *dao.DAOFactory) *MenuTreeBuilder {
 	return &MenuTreeBuilder{
-		router: commands.NewMenuRouterWithService(service, daoFactory),
+		router: commands.NewMenuRouterWithService(service, reviewService, historyService, daoFactory),
 	}
 }
 
@@ -47,7 +49,7 @@ func (b *MenuTreeBuilder) validateNode(node model.MenuNode, path []string) error
 	
 	// 检查当前节点下的子节点命令是否冲突
 	commands := make(map[string]string)
-	for _, child := range node.GetChildren() {
+	for _, child := range model.SortedChildren(node) {
 		cmd := child.GetCommand()
 		if cmd == "" {
 			continue // 跳过空命令（如根节点）
@@ -61,7 +63,7 @@ func (b *MenuTreeBuilder) validateNode(node model.MenuNode, path []string) error
 	}
 
 	// 递归验证子节点
-	for _, child := range node.GetChildren() {
+	for _, child := range model.SortedChildren(node) {
 		if err := b.validateNode(child, currentPath); err != nil {
 			return err
 		}
@@ -83,7 +85,7 @@ func (b *MenuTreeBuilder) PrintTree(node model.MenuNode, indent string) {
 	
 	fmt.Printf("%s%s (%s) [命令: %s]\n", indent, node.GetName(), node.GetID(), node.GetCommand())
 	
-	for _, child := range node.GetChildren() {
+	for _, child := range model.SortedChildren(node) {
 		b.PrintTree(child, indent+"  ")
 	}
 }
\ No newline at end of file
@@ -0,0 +1,74 @@
+package history
+
+import (
+	"fmt"
+
+	"github.com/ct-zh/englishLearn/internal/logic/history"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// timeLayout 修改时间的显示格式
+const timeLayout = "2006-01-02 15:04:05"
+
+// HistoryNode 修改历史节点
+// 命令行用法: history [--limit 条数]
+type HistoryNode struct {
+	*model.BaseMenuNode
+	service *history.Service
+}
+
+// NewHistory 创建修改历史节点
+func NewHistory(service *history.Service) *HistoryNode {
+	node := &HistoryNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "history",
+			Name:     "修改历史",
+			Command:  "4",
+			Order:    4,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+
+	node.Handler = node.handleHistory
+	return node
+}
+
+// handleHistory 列出最近的修改
+// 命令行参数:
+// - limit: 最多列出多少条，默认为20
+func (n *HistoryNode) handleHistory(ctx *model.MenuContext) error {
+	req := &model.ListHistoryRequest{Limit: history.DefaultListLimit}
+	if ctx.Args != nil {
+		if limit, ok := ctx.Args["limit"].(int); ok && limit > 0 {
+			req.Limit = limit
+		}
+	}
+
+	resp, err := n.service.ListHistory(req)
+	if err != nil {
+		return fmt.Errorf("查看修改历史失败: %w", err)
+	}
+
+	fmt.Printf("\n=== 修改历史 ===\n")
+	if resp.Total == 0 {
+		fmt.Println("还没有修改记录")
+		return nil
+	}
+	fmt.Printf("共保存 %d 次修改，最近的 %d 次：\n", resp.Total, len(resp.Entries))
+	printEntries(resp.Entries)
+	fmt.Println("\n使用 undo N 撤销第 N 条修改，之后的修改保持不变；undo N --all 恢复到第 N 条修改之前的状态，第 1-N 条修改会全部撤销")
+	return nil
+}
+
+// printEntries 按序号列出修改记录，序号1为最近一次修改
+func printEntries(entries []model.HistoryEntry) {
+	for i, entry := range entries {
+		printEntry(i+1, entry)
+	}
+}
+
+// printEntry 显示一条修改记录
+func printEntry(number int, entry model.HistoryEntry) {
+	fmt.Printf("%2d. %s  %s\n", number, entry.Time.Local().Format(timeLayout), entry.Action)
+}
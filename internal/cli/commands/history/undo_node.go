@@ -0,0 +1,137 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/internal/logic/history"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// undoListLimit 交互模式下列出供选择的修改条数
+const undoListLimit = 10
+
+// UndoNode 撤销修改节点
+// 命令行用法: undo [N] [--all] [--yes]，N为history列出的序号，默认为1（最近一次修改）
+type UndoNode struct {
+	*model.BaseMenuNode
+	service *history.Service
+}
+
+// NewUndo 创建撤销修改节点
+func NewUndo(service *history.Service) *UndoNode {
+	node := &UndoNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "undo",
+			Name:     "撤销修改",
+			Command:  "5",
+			Order:    5,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+
+	node.Handler = node.handleUndo
+	return node
+}
+
+// handleUndo 撤销修改：命令行模式按参数撤销，交互模式列出最近的修改供选择
+// 命令行参数:
+// - index: 撤销第几条修改，默认为1
+// - all: 同时撤销之后的全部修改，恢复到这次修改之前的状态；默认只撤销这一条
+// - yes: 不再确认
+func (n *UndoNode) handleUndo(ctx *model.MenuContext) error {
+	resp, err := n.service.ListHistory(&model.ListHistoryRequest{})
+	if err != nil {
+		return fmt.Errorf("查看修改历史失败: %w", err)
+	}
+	if resp.Total == 0 {
+		fmt.Println("还没有可以撤销的修改")
+		return nil
+	}
+
+	index := 1
+	yes, all := false, false
+	if ctx.Args != nil {
+		if value, ok := ctx.Args["index"].(int); ok {
+			index = value
+		}
+		yes, _ = ctx.Args["yes"].(bool)
+		all, _ = ctx.Args["all"].(bool)
+	} else {
+		selected, ok := selectEntry(resp.Entries)
+		if !ok {
+			fmt.Println("已取消撤销")
+			return nil
+		}
+		index = selected
+	}
+	if index < 1 || index > resp.Total {
+		return fmt.Errorf("修改序号应在 1-%d 之间", resp.Total)
+	}
+
+	if !yes && !ConfirmUndo(resp.Entries[:index], all) {
+		fmt.Println("已取消撤销")
+		return nil
+	}
+	return Undo(n.service, index, all)
+}
+
+// selectEntry 列出最近的修改，让用户选择撤销哪一条，直接回车选择最近一次
+func selectEntry(entries []model.HistoryEntry) (int, bool) {
+	if len(entries) > undoListLimit {
+		entries = entries[:undoListLimit]
+	}
+	fmt.Printf("\n=== 撤销修改 ===\n")
+	printEntries(entries)
+
+	for {
+		input, err := utils.Prompt(fmt.Sprintf("撤销第几条修改，之后的修改保持不变 (1-%d，回车为1，b取消): ", len(entries)))
+		if err != nil || strings.ToLower(input) == "b" {
+			return 0, false
+		}
+		if input == "" {
+			return 1, true
+		}
+		if index, err := strconv.Atoi(input); err == nil && index >= 1 && index <= len(entries) {
+			return index, true
+		}
+		fmt.Println("无效的序号，请重新输入")
+	}
+}
+
+// ConfirmUndo 列出将被撤销的修改并向用户确认，entries为第1条到要撤销的一条
+// all为false时只撤销最后一条，否则全部撤销
+func ConfirmUndo(entries []model.HistoryEntry, all bool) bool {
+	target := entries[len(entries)-1]
+	if all {
+		fmt.Printf("将撤销以下 %d 次修改，恢复到 %s 之前的状态：\n", len(entries), target.Time.Local().Format(timeLayout))
+		printEntries(entries)
+	} else {
+		fmt.Printf("将撤销第 %d 条修改，之后的修改保持不变：\n", len(entries))
+		printEntry(len(entries), target)
+	}
+	input, _ := utils.Prompt("单词的复习进度不受影响，撤销后可以再次撤销。确认撤销? (y/N): ")
+	return strings.ToLower(input) == "y"
+}
+
+// Undo 撤销第index条修改并显示结果，all为true时同时撤销之后的全部修改
+func Undo(service *history.Service, index int, all bool) error {
+	resp, err := service.Undo(&model.UndoRequest{Index: index, All: all})
+	if err != nil {
+		if !all && errors.Is(err, dao.ErrHistoryConflict) {
+			return fmt.Errorf("%w\n可以使用 undo %d --all 连同之后的修改一起撤销", err, index)
+		}
+		return err
+	}
+	if all {
+		fmt.Printf("✓ 已撤销 %d 次修改，恢复到“%s”之前的状态\n", resp.Reverted, resp.Entry.Action)
+	} else {
+		fmt.Printf("✓ 已撤销第 %d 条修改“%s”\n", index, resp.Entry.Action)
+	}
+	return nil
+}
@@ -1,31 +1,36 @@
 package commands
 
 import (
-	"bufio"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
+	"github.com/ct-zh/englishLearn/internal/cli/commands/history"
 	"github.com/ct-zh/englishLearn/internal/dao"
+	historyLogic "github.com/ct-zh/englishLearn/internal/logic/history"
 	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
 )
 
 // FileManagerNode 文件管理节点
 type FileManagerNode struct {
 	*model.BaseMenuNode
-	daoFactory *dao.DAOFactory
+	daoFactory     *dao.DAOFactory
+	historyService *historyLogic.Service
 }
 
 // NewFileManager 创建文件管理节点
-func NewFileManager(daoFactory *dao.DAOFactory) *FileManagerNode {
+func NewFileManager(daoFactory *dao.DAOFactory, historyService *historyLogic.Service) *FileManagerNode {
 	node := &FileManagerNode{
 		BaseMenuNode: &model.BaseMenuNode{
 			ID:       "fileManager",
 			Name:     "切换数据文件",
 			Command:  "f",
+			Order:    9,
 			Children: make(map[string]model.MenuNode),
 		},
-		daoFactory: daoFactory,
+		daoFactory:     daoFactory,
+		historyService: historyService,
 	}
 	
 	node.Handler = node.handleFileManager
@@ -51,14 +56,14 @@ func (n *FileManagerNode) handleFileManager(ctx *model.MenuContext) error {
 		fmt.Println("1. 输入新的文件路径")
 		fmt.Println("2. 查看文件详细信息")
 		fmt.Println("3. 回滚到上一个文件")
+		fmt.Println("4. 恢复到之前的状态")
 		fmt.Println("b. 返回主菜单")
 		fmt.Print("请输入选择: ")
 		
-		// 读取用户输入
-		var choice string
-		if _, err := fmt.Scanln(&choice); err != nil {
-			fmt.Printf("输入错误: %v\n", err)
-			continue
+		// 读取用户输入，与其他节点共用标准输入读取器，输入结束时返回
+		choice, err := utils.ReadLine()
+		if err != nil {
+			return err
 		}
 		
 		switch strings.ToLower(choice) {
@@ -66,12 +71,12 @@ func (n *FileManagerNode) handleFileManager(ctx *model.MenuContext) error {
 			if err := n.handleChangeFile(); err != nil {
 				fmt.Printf("切换文件失败: %v\n", err)
 				fmt.Println("按回车键继续...")
-				_, _ = fmt.Scanln()
+				_, _ = utils.ReadLine()
 			}
 		case "2":
 			n.displayDetailedFileInfo(fileInfo)
 			fmt.Println("按回车键继续...")
-			_, _ = fmt.Scanln()
+			_, _ = utils.ReadLine()
 		case "3":
 			if err := n.handleRollbackFile(); err != nil {
 				fmt.Printf("回滚失败: %v\n", err)
@@ -79,13 +84,19 @@ func (n *FileManagerNode) handleFileManager(ctx *model.MenuContext) error {
 				fmt.Println("✓ 文件回滚成功")
 			}
 			fmt.Println("按回车键继续...")
-			_, _ = fmt.Scanln()
+			_, _ = utils.ReadLine()
+		case "4":
+			if err := n.handleRestoreSnapshot(); err != nil {
+				fmt.Printf("恢复失败: %v\n", err)
+			}
+			fmt.Println("按回车键继续...")
+			_, _ = utils.ReadLine()
 		case "b":
 			return model.ErrBack
 		default:
 			fmt.Println("无效的选择，请重新输入")
 			fmt.Println("按回车键继续...")
-			_, _ = fmt.Scanln()
+			_, _ = utils.ReadLine()
 		}
 	}
 }
@@ -121,6 +132,12 @@ func (n *FileManagerNode) displayDetailedFileInfo(fileInfo map[string]interface{
 	fmt.Printf("路径: %v\n", fileInfo["path"])
 	
 	if exists, ok := fileInfo["exists"].(bool); ok && exists {
+		switch fileInfo["storage"] {
+		case "directory":
+			fmt.Println("存储方式: 词库目录（每个章节一个文件）")
+		case "log":
+			fmt.Println("存储方式: 日志存储（内存索引+追加写入）")
+		}
 		if size, ok := fileInfo["size"].(int64); ok {
 			fmt.Printf("大小: %d 字节\n", size)
 		}
@@ -133,6 +150,12 @@ func (n *FileManagerNode) displayDetailedFileInfo(fileInfo map[string]interface{
 		if validJSON, ok := fileInfo["valid_json"].(bool); ok {
 			fmt.Printf("JSON格式: %v\n", validJSON)
 		}
+		if version, ok := fileInfo["schema_version"].(int); ok {
+			fmt.Printf("格式版本: v%d\n", version)
+			if needsMigration, ok := fileInfo["needs_migration"].(bool); ok && needsMigration {
+				fmt.Println("  (旧版本格式，加载时会自动升级，原文件备份为 .bak)")
+			}
+		}
 		if sectionsCount, ok := fileInfo["sections_count"].(int); ok {
 			fmt.Printf("章节数量: %d\n", sectionsCount)
 		}
@@ -149,18 +172,14 @@ func (n *FileManagerNode) displayDetailedFileInfo(fileInfo map[string]interface{
 func (n *FileManagerNode) handleChangeFile() error {
 	fmt.Println("\n=== 切换数据文件 ===")
 	fmt.Println("请输入新的文件路径（支持相对路径和绝对路径）:")
-	fmt.Println("提示: 文件必须是有效的JSON格式")
+	fmt.Println("提示: 文件必须是有效的JSON格式；以/结尾的路径表示词库目录")
 	fmt.Print("文件路径: ")
 	
-	// 使用bufio.Reader来读取可能包含空格的路径
-	reader := bufio.NewReader(os.Stdin)
-	newPath, err := reader.ReadString('\n')
+	// 读取一整行，路径可能包含空格
+	newPath, err := utils.ReadLine()
 	if err != nil {
 		return fmt.Errorf("读取输入失败: %w", err)
 	}
-	
-	// 清理输入（去除换行符和前后空格）
-	newPath = strings.TrimSpace(newPath)
 	if newPath == "" {
 		return fmt.Errorf("文件路径不能为空")
 	}
@@ -182,7 +201,7 @@ func (n *FileManagerNode) handleChangeFile() error {
 	}
 	
 	fmt.Println("按回车键继续...")
-	_, _ = fmt.Scanln()
+	_, _ = utils.ReadLine()
 	return nil
 }
 
@@ -191,9 +210,9 @@ func (n *FileManagerNode) handleRollbackFile() error {
 	fmt.Println("\n=== 回滚数据文件 ===")
 	fmt.Print("确认要回滚到上一个文件吗？(y/N): ")
 	
-	var confirm string
-	if _, err := fmt.Scanln(&confirm); err != nil {
-		// 输入错误时默认为取消
+	// 输入错误时默认为取消
+	confirm, err := utils.ReadLine()
+	if err != nil {
 		confirm = "n"
 	}
 	
@@ -203,4 +222,39 @@ func (n *FileManagerNode) handleRollbackFile() error {
 	}
 	
 	return n.daoFactory.RollbackDataFile()
-}
\ No newline at end of file
+}
+
+// handleRestoreSnapshot 列出当前数据文件的修改历史，选择一次修改恢复到它之前的状态
+// 从最近一次修改开始依次撤销，这次修改及之后的全部修改都会被撤销
+func (n *FileManagerNode) handleRestoreSnapshot() error {
+	fmt.Println("\n=== 恢复到之前的状态 ===")
+	resp, err := n.historyService.ListHistory(&model.ListHistoryRequest{})
+	if err != nil {
+		return err
+	}
+	if resp.Total == 0 {
+		fmt.Println("当前数据文件还没有修改记录")
+		return nil
+	}
+
+	fmt.Printf("共 %d 条修改记录，恢复到某次修改之前会撤销这次修改及之后的全部修改：\n", resp.Total)
+	for i, entry := range resp.Entries {
+		fmt.Printf("%2d. %s  %s\n", i+1, entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Action)
+	}
+
+	input, err := utils.Prompt("请输入要恢复到哪次修改之前 (b取消): ")
+	if err != nil || strings.ToLower(input) == "b" || input == "" {
+		fmt.Println("取消恢复操作")
+		return nil
+	}
+	index, err := strconv.Atoi(input)
+	if err != nil || index < 1 || index > resp.Total {
+		return fmt.Errorf("无效的修改序号: %s", input)
+	}
+
+	if !history.ConfirmUndo(resp.Entries[:index], true) {
+		fmt.Println("取消恢复操作")
+		return nil
+	}
+	return history.Undo(n.historyService, index, true)
+}
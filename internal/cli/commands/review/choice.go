@@ -0,0 +1,90 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/logic/practice"
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// optionLabels 选项标号
+const optionLabels = "ABCD"
+
+// RunChoiceQuiz 逐题进行四选一选择题练习，结束时打印得分
+// total为参与出题的单词数，缺少释义或题库不足的单词不会出题
+func RunChoiceQuiz(session *review.Session, questions []practice.ChoiceQuestion, total int) error {
+	if skipped := total - len(questions); skipped > 0 {
+		fmt.Printf("有 %d 个单词缺少释义或找不到足够的干扰项，已跳过\n", skipped)
+	}
+	if len(questions) == 0 {
+		fmt.Println("没有可以练习的选择题")
+		return nil
+	}
+
+	for i, question := range questions {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(questions), question.Prompt)
+		for j, option := range question.Options {
+			fmt.Printf("  %c. %s\n", optionLabels[j], option)
+		}
+
+		start := time.Now()
+		choice, ok := promptOption(len(question.Options))
+		elapsed := time.Since(start)
+		if !ok {
+			break
+		}
+
+		correct, quality := question.Check(choice)
+		if correct {
+			fmt.Println("✓ 正确")
+		} else {
+			fmt.Printf("✗ 正确答案: %c. %s\n", optionLabels[question.Answer], question.Options[question.Answer])
+		}
+		if question.Direction == practice.DirectionCnToEn {
+			fmt.Printf("  %s - %s\n", question.Word.W, question.Word.C)
+		}
+
+		if _, err := session.Answer(question.Section, question.Word.W, quality, elapsed); err != nil {
+			fmt.Printf("记录作答结果失败: %v\n", err)
+		}
+	}
+
+	answers := session.Answered()
+	correct := 0
+	for _, answer := range answers {
+		if answer.Correct {
+			correct++
+		}
+	}
+	if len(answers) > 0 {
+		fmt.Printf("\n得分: %d/%d (%.0f%%)\n", correct, len(answers), float64(correct)*100/float64(len(answers)))
+	}
+	printSummary(answers, len(questions))
+	return session.Finish()
+}
+
+// promptOption 读取用户选择的选项（字母或数字），返回false表示结束练习
+func promptOption(count int) (int, bool) {
+	for {
+		input, err := utils.Prompt(fmt.Sprintf("请选择 (A-%c, q结束): ", optionLabels[count-1]))
+		if err != nil {
+			return 0, false
+		}
+		input = strings.ToUpper(input)
+		if input == "Q" {
+			return 0, false
+		}
+		if len(input) == 1 {
+			if i := strings.IndexByte(optionLabels[:count], input[0]); i >= 0 {
+				return i, true
+			}
+			if input[0] >= '1' && int(input[0]-'1') < count {
+				return int(input[0] - '1'), true
+			}
+		}
+		fmt.Println("无效的选项，请重新输入")
+	}
+}
@@ -0,0 +1,61 @@
+package review
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/logic/practice"
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// RunCloze 以完形填空方式练习单词：挖去例句中的目标单词（含词形变化），由用户填写
+// 例句中找不到目标单词的条目会在开始前列出并跳过
+func RunCloze(session *review.Session, items []model.ReviewQueueItem) error {
+	exercises, skipped := practice.BuildClozeExercises(items)
+	if len(skipped) > 0 {
+		fmt.Printf("以下 %d 个单词无法生成填空题，已跳过:\n", len(skipped))
+		for _, skip := range skipped {
+			fmt.Printf("  - %s [%s]: %s\n", skip.Word, skip.Section, skip.Reason)
+		}
+	}
+	if len(exercises) == 0 {
+		fmt.Println("没有可以练习的填空题")
+		return nil
+	}
+
+	for i, exercise := range exercises {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(exercises), exercise.Prompt)
+		if exercise.Word.C != "" {
+			fmt.Printf("提示: %s\n", exercise.Word.C)
+		}
+
+		start := time.Now()
+		answer, err := utils.Prompt("请填空 (直接回车表示不会, :q结束): ")
+		elapsed := time.Since(start)
+		if err != nil || answer == ":q" {
+			break
+		}
+
+		result := exercise.Check(answer)
+		switch {
+		case result.Correct:
+			fmt.Println("✓ 正确")
+		case result.WrongForm:
+			fmt.Printf("△ 单词正确，但这里应为 %s\n", exercise.Answer)
+		default:
+			fmt.Printf("✗ 正确答案: %s\n", exercise.Answer)
+			if result.Spelling.Actual != "" {
+				fmt.Printf("  错误位置: %s (相差 %d 处)\n", result.Spelling.Hint(), result.Spelling.Distance)
+			}
+		}
+
+		if _, err := session.Answer(exercise.Section, exercise.Word.W, result.Quality, elapsed); err != nil {
+			fmt.Printf("记录作答结果失败: %v\n", err)
+		}
+	}
+
+	printSummary(session.Answered(), len(exercises))
+	return session.Finish()
+}
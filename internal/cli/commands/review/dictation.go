@@ -0,0 +1,54 @@
+package review
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/logic/practice"
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// RunDictation 以听写方式练习单词：显示中文释义（可选显示遮盖后的例句），由用户输入英文
+// 答错时标出拼错的位置，结果按拼写距离换算为评分并更新复习状态
+func RunDictation(session *review.Session, items []model.ReviewQueueItem, showPhrase bool) error {
+	for i, item := range items {
+		masked, hasPhrase := practice.MaskWord(item.Word.Phrase, item.Word.W)
+		if item.Word.C == "" && !hasPhrase {
+			fmt.Printf("\n[%d/%d] 跳过 %s: 缺少释义和例句，无法出题\n", i+1, len(items), item.Word.W)
+			continue
+		}
+
+		fmt.Printf("\n[%d/%d] 释义: %s\n", i+1, len(items), item.Word.C)
+		// 没有释义时总是显示例句作为提示
+		if hasPhrase && (showPhrase || item.Word.C == "") {
+			fmt.Printf("例句: %s\n", masked)
+		}
+
+		// 整行读取，支持 "cleared out" 这类多词条目
+		start := time.Now()
+		answer, err := utils.Prompt("请输入英文 (直接回车表示不会, :q结束): ")
+		elapsed := time.Since(start)
+		if err != nil || answer == ":q" {
+			break
+		}
+
+		result := practice.CheckSpelling(item.Word.W, answer)
+		if result.Correct {
+			fmt.Println("✓ 正确")
+		} else {
+			fmt.Printf("✗ 正确答案: %s\n", item.Word.W)
+			if result.Actual != "" {
+				fmt.Printf("  错误位置: %s (相差 %d 处)\n", result.Hint(), result.Distance)
+			}
+		}
+
+		if _, err := session.Answer(item.Section, item.Word.W, practice.SpellingQuality(result), elapsed); err != nil {
+			fmt.Printf("记录作答结果失败: %v\n", err)
+		}
+	}
+
+	printSummary(session.Answered(), len(items))
+	return session.Finish()
+}
@@ -0,0 +1,74 @@
+package review
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// RunFlashcards 以闪卡方式逐个练习单词：先显示单词，回车后显示释义和例句，再由用户自评
+// 每次自评都会更新复习状态，结束时写入会话日志
+func RunFlashcards(session *review.Session, items []model.ReviewQueueItem) error {
+	for i, item := range items {
+		fmt.Printf("\n[%d/%d] %s  (%s · %s)\n", i+1, len(items), item.Word.W, item.Section, describeItem(item))
+
+		start := time.Now()
+		input, err := utils.Prompt("按回车显示释义 (q结束): ")
+		elapsed := time.Since(start)
+		if err != nil {
+			break
+		}
+		if strings.ToLower(input) == "q" {
+			break
+		}
+
+		fmt.Printf("释义: %s\n", item.Word.C)
+		if item.Word.Phrase != "" {
+			fmt.Printf("例句: %s\n", item.Word.Phrase)
+		}
+
+		quality, ok := promptGrade()
+		if !ok {
+			break
+		}
+
+		state, err := session.Answer(item.Section, item.Word.W, quality, elapsed)
+		if err != nil {
+			fmt.Printf("记录作答结果失败: %v\n", err)
+			continue
+		}
+		fmt.Printf("下次复习: %s (间隔 %d 天)\n", state.Due.Format("2006-01-02"), state.Interval)
+	}
+
+	printSummary(session.Answered(), len(items))
+	return session.Finish()
+}
+
+// promptGrade 读取用户自评，返回false表示用户结束练习
+func promptGrade() (int, bool) {
+	for {
+		input, err := utils.Prompt("自评 (1=忘记 2=困难 3=良好 4=简单, q结束): ")
+		if err != nil || strings.ToLower(input) == "q" {
+			return 0, false
+		}
+		if quality, ok := review.ParseSelfGrade(input); ok {
+			return quality, true
+		}
+		fmt.Println("无效的评分，请重新输入")
+	}
+}
+
+// printSummary 打印本次练习的统计
+func printSummary(answers []model.AnswerRecord, total int) {
+	correct := 0
+	for _, answer := range answers {
+		if answer.Correct {
+			correct++
+		}
+	}
+	fmt.Printf("\n本次练习完成 %d/%d 个单词，答对 %d 个\n", len(answers), total, correct)
+}
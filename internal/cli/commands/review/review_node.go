@@ -0,0 +1,83 @@
+package review
+
+import (
+	"fmt"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// ReviewNode 今日复习节点
+type ReviewNode struct {
+	*model.BaseMenuNode
+	service *review.Service
+}
+
+// NewReview 创建今日复习节点
+func NewReview(service *review.Service) *ReviewNode {
+	node := &ReviewNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "review",
+			Name:     "今日复习",
+			Command:  "2",
+			Order:    2,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+
+	node.Handler = node.handleReview
+	return node
+}
+
+// handleReview 处理今日复习的逻辑
+// 命令行参数:
+// - new: 每日新词上限，默认为20
+// - list: 只列出复习队列，不进入复习
+func (n *ReviewNode) handleReview(ctx *model.MenuContext) error {
+	req := &model.ReviewQueueRequest{
+		NewLimit: review.DefaultNewLimit,
+	}
+	listOnly := false
+	if ctx.Args != nil {
+		if newLimit, ok := ctx.Args["new"].(int); ok && newLimit >= 0 {
+			req.NewLimit = newLimit
+		}
+		if list, ok := ctx.Args["list"].(bool); ok {
+			listOnly = list
+		}
+	}
+
+	resp, err := n.service.BuildQueue(req)
+	if err != nil {
+		return fmt.Errorf("构建复习队列失败: %w", err)
+	}
+
+	fmt.Printf("\n=== 今日复习 ===\n")
+	fmt.Printf("到期复习: %d 个，新词: %d 个\n", resp.DueCount, resp.NewCount)
+	if len(resp.Items) == 0 {
+		fmt.Println("今天没有需要复习的单词")
+		return nil
+	}
+
+	if listOnly {
+		for i, item := range resp.Items {
+			fmt.Printf("%d. %s - %s [%s] %s\n", i+1, item.Word.W, item.Word.C, item.Section, describeItem(item))
+		}
+		return nil
+	}
+
+	session := n.service.StartSession("", model.SessionModeReview)
+	return RunFlashcards(session, resp.Items)
+}
+
+// describeItem 描述队列项的状态
+func describeItem(item model.ReviewQueueItem) string {
+	if item.IsNew {
+		return "新词"
+	}
+	if item.OverdueDays > 0 {
+		return fmt.Sprintf("逾期%d天", item.OverdueDays)
+	}
+	return "今日到期"
+}
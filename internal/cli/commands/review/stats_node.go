@@ -0,0 +1,97 @@
+package review
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// StatsNode 学习统计节点
+type StatsNode struct {
+	*model.BaseMenuNode
+	service *review.Service
+}
+
+// NewStats 创建学习统计节点
+func NewStats(service *review.Service) *StatsNode {
+	node := &StatsNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "stats",
+			Name:     "学习统计",
+			Command:  "3",
+			Order:    3,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+
+	node.Handler = node.handleStats
+	return node
+}
+
+// handleStats 处理学习统计的逻辑
+// 命令行参数:
+// - days: 按天统计最近多少天，默认为7
+// - top: 列出最难的多少个单词，默认为20
+func (n *StatsNode) handleStats(ctx *model.MenuContext) error {
+	req := &model.StatsRequest{
+		Days: review.DefaultStatsDays,
+		Top:  review.DefaultHardestWords,
+	}
+	if ctx.Args != nil {
+		if days, ok := ctx.Args["days"].(int); ok && days > 0 {
+			req.Days = days
+		}
+		if top, ok := ctx.Args["top"].(int); ok && top > 0 {
+			req.Top = top
+		}
+	}
+
+	resp, err := n.service.Stats(req)
+	if err != nil {
+		return fmt.Errorf("统计学习记录失败: %w", err)
+	}
+
+	fmt.Printf("\n=== 学习统计 ===\n")
+	if resp.Answers == 0 {
+		fmt.Println("还没有练习记录，完成一次练习后再来看看吧")
+		return nil
+	}
+	fmt.Printf("累计练习 %d 次，作答 %d 次，正确率 %s\n", resp.Sessions, resp.Answers, percent(resp.Correct, resp.Answers))
+	fmt.Printf("连续学习 %d 天\n", resp.Streak)
+
+	fmt.Printf("\n--- 各章节正确率 ---\n")
+	for _, sec := range resp.Sections {
+		fmt.Printf("%s: %d/%d (%s)\n", sec.Section, sec.Correct, sec.Answers, percent(sec.Correct, sec.Answers))
+	}
+
+	fmt.Printf("\n--- 最难的 %d 个单词 ---\n", len(resp.HardestWords))
+	for i, word := range resp.HardestWords {
+		line := fmt.Sprintf("%d. %s [%s] 答错 %d/%d 次 (%s)",
+			i+1, word.Word, word.Section, word.Wrong, word.Answers, percent(word.Wrong, word.Answers))
+		if word.AvgResponse > 0 {
+			line += fmt.Sprintf("，平均用时 %.1f 秒", (time.Duration(word.AvgResponse) * time.Millisecond).Seconds())
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Printf("\n--- 最近 %d 天 ---\n", len(resp.Daily))
+	for _, day := range resp.Daily {
+		if day.Answers == 0 {
+			fmt.Printf("%s  -\n", day.Date)
+			continue
+		}
+		fmt.Printf("%s  单词 %d 个，作答 %d 次，正确率 %s\n", day.Date, day.Words, day.Answers, percent(day.Correct, day.Answers))
+	}
+	return nil
+}
+
+// percent 格式化百分比
+func percent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.0f%%", float64(part)*100/float64(total))
+}
@@ -1,17 +1,24 @@
 package commands
 
 import (
+	"github.com/ct-zh/englishLearn/internal/cli/commands/history"
+	"github.com/ct-zh/englishLearn/internal/cli/commands/review"
 	"github.com/ct-zh/englishLearn/internal/cli/commands/sections"
+	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/internal/dao"
+	historyLogic "github.com/ct-zh/englishLearn/internal/logic/history"
+	reviewLogic "github.com/ct-zh/englishLearn/internal/logic/review"
 	sectionsLogic "github.com/ct-zh/englishLearn/internal/logic/sections"
 	"github.com/ct-zh/englishLearn/model"
 )
 
 // MenuRouter 菜单路由器
 type MenuRouter struct {
-	root       model.MenuNode
-	service    *sectionsLogic.Service
-	daoFactory *dao.DAOFactory
+	root           model.MenuNode
+	service        *sectionsLogic.Service
+	reviewService  *reviewLogic.Service
+	historyService *historyLogic.Service
+	daoFactory     *dao.DAOFactory
 }
 
 // NewMenuRouter 创建菜单路由器
@@ -20,10 +27,12 @@ func NewMenuRouter() *MenuRouter {
 }
 
 // NewMenuRouterWithService 创建带service的菜单路由器 (用于Wire)
-func NewMenuRouterWithService(service *sectionsLogic.Service, daoFactory *dao.DAOFactory) *MenuRouter {
+func NewMenuRouterWithService(service *sectionsLogic.Service, reviewService *reviewLogic.Service, historyService *historyLogic.Service, daoFactory *dao.DAOFactory) *MenuRouter {
 	return &MenuRouter{
-		service:    service,
-		daoFactory: daoFactory,
+		service:        service,
+		reviewService:  reviewService,
+		historyService: historyService,
+		daoFactory:     daoFactory,
 	}
 }
 
@@ -48,11 +57,21 @@ func (r *MenuRouter) BuildDefaultTree() model.MenuNode {
 
 	// 使用注入的service或创建默认service
 	service := r.service
-	if service == nil {
+	reviewService := r.reviewService
+	historyService := r.historyService
+	if service == nil || reviewService == nil || historyService == nil {
 		// 兼容旧的方式，用于非Wire场景
-		daoFactory := dao.NewDAOFactory("../../data")
+		daoFactory := dao.NewDAOFactory(config.DefaultConfig().DataFilePath)
 		sectionDAO := daoFactory.GetSectionDAO()
-		service = sectionsLogic.NewService(sectionDAO)
+		if service == nil {
+			service = sectionsLogic.NewService(sectionDAO)
+		}
+		if reviewService == nil {
+			reviewService = reviewLogic.NewService(sectionDAO, daoFactory.GetSessionDAO())
+		}
+		if historyService == nil {
+			historyService = historyLogic.NewService(daoFactory.GetHistoryDAO())
+		}
 	}
 
 	// 创建sections节点并挂载到根节点
@@ -60,21 +79,40 @@ func (r *MenuRouter) BuildDefaultTree() model.MenuNode {
 	root.Menu(sectionsNode)
 
 	// 创建createSection节点并挂载到sections下（第一个选项）
-	createSection := sections.NewCreateSection(service)
+	createSection := sections.NewCreateSection(service, reviewService)
 	sectionsNode.Menu(createSection)
 
 	// 创建selectSection节点并挂载到sections下
-	selectSection := sections.NewSelectSection(service)
+	selectSection := sections.NewSelectSection(service, reviewService)
 	sectionsNode.Menu(selectSection)
 
 	// 创建单词操作节点并挂载到selectSection下
 	selectSection.Menu(sections.NewAddWord(service))
 	selectSection.Menu(sections.NewListWords(service))
 	selectSection.Menu(sections.NewRandomWords(service))
+	selectSection.Menu(sections.NewWord(service))
+	selectSection.Menu(sections.NewSection(service))
+
+	// 创建今日复习节点并挂载到根节点
+	root.Menu(review.NewReview(reviewService))
+
+	// 创建学习统计节点并挂载到根节点
+	root.Menu(review.NewStats(reviewService))
+
+	// 创建导入、导出节点并挂载到根节点
+	root.Menu(sections.NewImport(service))
+	root.Menu(sections.NewExport(service))
+
+	// 创建合并数据文件节点并挂载到根节点
+	root.Menu(sections.NewMergeFile(service))
+
+	// 创建修改历史和撤销节点并挂载到根节点
+	root.Menu(history.NewHistory(historyService))
+	root.Menu(history.NewUndo(historyService))
 
 	// 创建文件管理节点并挂载到根节点
 	if r.daoFactory != nil {
-		fileManager := NewFileManager(r.daoFactory)
+		fileManager := NewFileManager(r.daoFactory, historyService)
 		root.Menu(fileManager)
 	}
 
@@ -20,6 +20,7 @@ func NewAddWord(service *sections.Service) *AddWordNode {
 			ID:       "addWord",
 			Name:     "添加单词",
 			Command:  "a",
+			Order:    1,
 			Children: make(map[string]model.MenuNode),
 			Handler: func(ctx *model.MenuContext) error {
 				fmt.Println("开始添加单词...")
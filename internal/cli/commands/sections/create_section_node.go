@@ -1,31 +1,33 @@
 package sections
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"strings"
 
+	reviewLogic "github.com/ct-zh/englishLearn/internal/logic/review"
 	"github.com/ct-zh/englishLearn/internal/logic/sections"
 	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
 )
 
 // CreateSectionNode 创建章节节点
 type CreateSectionNode struct {
 	*model.BaseMenuNode
-	service *sections.Service
+	service       *sections.Service
+	reviewService *reviewLogic.Service
 }
 
 // NewCreateSection 创建新章节节点
-func NewCreateSection(service *sections.Service) *CreateSectionNode {
+func NewCreateSection(service *sections.Service, reviewService *reviewLogic.Service) *CreateSectionNode {
 	node := &CreateSectionNode{
 		BaseMenuNode: &model.BaseMenuNode{
 			ID:       "createSection",
 			Name:     "创建新章节",
 			Command:  "1",
+			Order:    1,
 			Children: make(map[string]model.MenuNode),
 		},
-		service: service,
+		service:       service,
+		reviewService: reviewService,
 	}
 
 	node.Handler = node.handleCreateSection
@@ -35,14 +37,10 @@ func NewCreateSection(service *sections.Service) *CreateSectionNode {
 // handleCreateSection 处理创建章节的逻辑
 func (n *CreateSectionNode) handleCreateSection(ctx *model.MenuContext) error {
 	for {
-		fmt.Print("请输入新章节名称: ")
-		reader := bufio.NewReader(os.Stdin)
-		sectionName, err := reader.ReadString('\n')
+		sectionName, err := utils.Prompt("请输入新章节名称: ")
 		if err != nil {
-			fmt.Printf("输入错误: %v\n", err)
-			continue
+			return fmt.Errorf("输入错误: %w", err)
 		}
-		sectionName = strings.TrimSpace(sectionName)
 
 		// 检查输入是否为空
 		if sectionName == "" {
@@ -85,8 +83,12 @@ func (n *CreateSectionNode) handleCreateSection(ctx *model.MenuContext) error {
 			fmt.Printf("已自动选择章节: %s\n", selectResp.Selected.Name)
 
 			// 创建一个临时的SelectSectionNode来复用章节操作菜单逻辑
-			selectNode := NewSelectSection(n.service)
-			return selectNode.showSectionMenu(ctx, &selectResp.Selected)
+			selectNode := NewSelectSection(n.service, n.reviewService)
+			if err := selectNode.showSectionMenu(ctx, &selectResp.Selected); err != errReselect {
+				return err
+			}
+			// 章节被删除、合并或需要重新选择时进入章节列表
+			return selectNode.handleSelectSection(ctx)
 		}
 
 		return nil
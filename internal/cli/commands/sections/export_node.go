@@ -0,0 +1,97 @@
+package sections
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/sections"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// ExportNode 导出单词节点
+// 命令行用法: export [文件] [--section 章节1,章节2] [--columns word,meaning,phrase] [--format csv|tsv|anki|html|md] [--no-header] [--bom]
+// 导出为可打印的练习纸: export 文件.html [--layout worksheet|cards] [--hide-meanings]
+type ExportNode struct {
+	*model.BaseMenuNode
+	service *sections.Service
+}
+
+// NewExport 创建导出单词节点
+func NewExport(service *sections.Service) *ExportNode {
+	node := &ExportNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "export",
+			Name:     "导出单词",
+			Command:  "7",
+			Order:    7,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+	node.Handler = node.handleExport
+	return node
+}
+
+// handleExport 将章节导出为CSV/TSV文件或Anki笔记文本
+// 命令行参数:
+// - out: 导出的文件，也可以作为第一个位置参数；不指定时输出到标准输出（--file 用于指定数据文件）
+// - section: 要导出的章节，以逗号分隔，默认为全部章节
+// - columns: 导出的列，默认为 word,meaning,phrase，导出多个章节时加上section
+// - format: csv、tsv、anki、html或md，默认按扩展名判断
+// - no-header: 不写表头
+// - bom: 写入UTF-8 BOM，用Excel打开时中文不会乱码
+// - layout: html、md格式的排版，worksheet为练习纸（默认），cards为双面打印的单词卡片
+// - hide-meanings: 练习纸中不显示释义，答案放在单独的一页
+func (n *ExportNode) handleExport(ctx *model.MenuContext) error {
+	req := &model.ExportWordsRequest{}
+	if ctx.Args == nil {
+		fmt.Printf("\n=== 导出单词 ===\n")
+		req.Path, _ = utils.Prompt("导出到文件 (.csv、.tsv、.html 或 .md): ")
+		if req.Path == "" {
+			fmt.Println("已取消导出")
+			return nil
+		}
+		input, _ := utils.Prompt("格式 csv/tsv/anki/html/md (回车按扩展名判断): ")
+		req.Format = model.ExchangeFormat(strings.ToLower(input))
+		input, _ = utils.Prompt("要导出的章节，以逗号分隔 (回车导出全部): ")
+		req.Sections = splitList(input)
+		req.BOM = true
+
+		format, err := sections.DetectFormat(req.Format, req.Path)
+		if err != nil {
+			return err
+		}
+		if format == model.FormatHTML || format == model.FormatMD {
+			input, _ = utils.Prompt("排版 1.练习纸 2.单词卡片 (回车为练习纸): ")
+			if input == "2" {
+				req.Layout = model.LayoutCards
+			} else {
+				input, _ = utils.Prompt("隐藏释义并把答案放在单独一页? (y/N): ")
+				req.HideMeanings = strings.ToLower(input) == "y"
+			}
+		}
+	} else {
+		req.Path = stringArg(ctx.Args, "out")
+		req.Format = model.ExchangeFormat(stringArg(ctx.Args, "format"))
+		req.Sections = splitList(stringArg(ctx.Args, "section"))
+		columns, err := sections.ParseColumns(stringArg(ctx.Args, "columns"))
+		if err != nil {
+			return err
+		}
+		req.Columns = columns
+		req.NoHeader, _ = ctx.Args["no-header"].(bool)
+		req.BOM, _ = ctx.Args["bom"].(bool)
+		req.Layout = model.WorksheetLayout(stringArg(ctx.Args, "layout"))
+		req.HideMeanings, _ = ctx.Args["hide-meanings"].(bool)
+	}
+
+	resp, err := n.service.ExportWords(req)
+	if err != nil {
+		return err
+	}
+	if req.Path != "" {
+		fmt.Printf("✓ 已导出 %d 个章节、%d 个单词到 %s\n", resp.Sections, resp.Words, req.Path)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package sections
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/sections"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// previewLimit 预览时每类最多列出的条数
+const previewLimit = 20
+
+// ImportNode 导入单词节点
+// 命令行用法: import 文件 [--section 章节] [--columns word,meaning,phrase,section,tags] [--header] [--format csv|tsv|anki|text] [--dry-run]
+type ImportNode struct {
+	*model.BaseMenuNode
+	service *sections.Service
+}
+
+// NewImport 创建导入单词节点
+func NewImport(service *sections.Service) *ImportNode {
+	node := &ImportNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "import",
+			Name:     "导入单词",
+			Command:  "6",
+			Order:    6,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+	node.Handler = node.handleImport
+	return node
+}
+
+// handleImport 从CSV/TSV文件或Anki笔记文本导入单词：命令行模式按参数导入，交互模式先预览再确认
+// 命令行参数:
+// - in: 导入的文件，也可以作为第一个位置参数（--file 用于指定数据文件）
+// - section: 没有章节列或章节列为空时导入到的章节
+// - columns: 各列对应的字段，以逗号分隔，"-"表示忽略该列
+// - header: 第一行为表头（由列名组成的表头会自动识别）
+// - format: csv、tsv、anki或text，默认按文件头和扩展名判断，不是每行都有制表符的 .txt 文件为单词列表（text）
+// - dry-run: 只预览，不写入
+func (n *ImportNode) handleImport(ctx *model.MenuContext) error {
+	if ctx.Args == nil {
+		return n.importInteractive()
+	}
+
+	req := &model.ImportWordsRequest{
+		Path:    stringArg(ctx.Args, "in"),
+		Format:  model.ExchangeFormat(stringArg(ctx.Args, "format")),
+		Section: stringArg(ctx.Args, "section"),
+	}
+	if req.Path == "" {
+		return fmt.Errorf("请指定要导入的文件，如: import words.csv")
+	}
+	columns, err := sections.ParseColumns(stringArg(ctx.Args, "columns"))
+	if err != nil {
+		return err
+	}
+	req.Columns = columns
+	req.Header, _ = ctx.Args["header"].(bool)
+	req.DryRun, _ = ctx.Args["dry-run"].(bool)
+
+	resp, err := n.service.ImportWords(req)
+	if resp != nil {
+		printImportPreview(resp)
+	}
+	if err != nil {
+		return err
+	}
+	printImportResult(resp, req.DryRun)
+	return nil
+}
+
+// importInteractive 交互式导入：输入文件和章节，预览后确认导入
+// 不输入文件时粘贴单词列表；自由格式的单词列表可以在导入前逐行修改
+func (n *ImportNode) importInteractive() error {
+	fmt.Printf("\n=== 导入单词 ===\n")
+	fmt.Println("支持CSV和TSV文件，第一行可以是表头（word、meaning、phrase、section、tags 或 单词、释义、例句、章节、标签）")
+	fmt.Println("也支持从Anki导出的笔记文本（Notes in Plain Text），牌组作为章节")
+	fmt.Println("以及每行一个单词的文本列表，如 \"dam 水坝\"、\"palatable - 可口的 - 例句\"")
+	path, err := utils.Prompt("文件路径 (直接回车粘贴单词列表): ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %w", err)
+	}
+	if path == "" {
+		text, err := readPastedText()
+		if err != nil {
+			return err
+		}
+		return editWordList(n.service, sections.ParseWordList(text), "", pasteSource)
+	}
+
+	format, err := sections.DetectFileFormat(path)
+	if err != nil {
+		return err
+	}
+	if format == model.FormatText {
+		list, err := sections.ReadWordList(path)
+		if err != nil {
+			return err
+		}
+		return editWordList(n.service, list, "", filepath.Base(path))
+	}
+
+	section, _ := utils.Prompt("导入到章节 (表格中有章节列时可直接回车): ")
+
+	req := &model.ImportWordsRequest{Path: path, Format: format, Section: section, DryRun: true}
+	resp, err := n.service.ImportWords(req)
+	if err != nil {
+		return err
+	}
+	printImportPreview(resp)
+	return confirmImport(resp, func() (*model.ImportWordsResponse, error) {
+		req.DryRun = false
+		return n.service.ImportWords(req)
+	})
+}
+
+// confirmImport 预览后向用户确认，确认后执行导入并显示结果
+func confirmImport(preview *model.ImportWordsResponse, commit func() (*model.ImportWordsResponse, error)) error {
+	if len(preview.New) == 0 {
+		fmt.Println("没有可以导入的新单词")
+		return nil
+	}
+	input, _ := utils.Prompt(fmt.Sprintf("确认导入 %d 个新单词? (y/N): ", len(preview.New)))
+	if strings.ToLower(input) != "y" {
+		fmt.Println("已取消导入")
+		return nil
+	}
+	resp, err := commit()
+	if err != nil {
+		return err
+	}
+	printImportResult(resp, false)
+	return nil
+}
+
+// printImportPreview 显示导入预览：各类单词的数量，以及冲突、无法导入的行和部分新单词
+func printImportPreview(resp *model.ImportWordsResponse) {
+	fmt.Printf("\n共 %d 行：新单词 %d 个，重复 %d 个，冲突 %d 个，无法导入 %d 行\n",
+		resp.Total, len(resp.New), len(resp.Duplicates), len(resp.Conflicts), len(resp.Invalid))
+	if len(resp.NewSections) > 0 {
+		fmt.Printf("将新建章节: %s\n", strings.Join(resp.NewSections, ", "))
+	}
+
+	if len(resp.New) > 0 {
+		fmt.Printf("\n--- 新单词 ---\n")
+		for i, item := range resp.New {
+			if i == previewLimit {
+				fmt.Printf("... 还有 %d 个\n", len(resp.New)-previewLimit)
+				break
+			}
+			fmt.Printf("第%d行 [%s] %s - %s\n", item.Line, item.Section, item.Word.W, item.Word.C)
+		}
+	}
+	if len(resp.Conflicts) > 0 {
+		fmt.Printf("\n--- 冲突（已有同名单词但释义或例句不同，不会导入）---\n")
+		for i, conflict := range resp.Conflicts {
+			if i == previewLimit {
+				fmt.Printf("... 还有 %d 个\n", len(resp.Conflicts)-previewLimit)
+				break
+			}
+			fmt.Printf("第%d行 [%s] %s: 已有 %s，导入 %s\n", conflict.Line, conflict.Section, conflict.Word.W,
+				describeImportWord(&conflict.Existing), describeImportWord(&conflict.Word))
+		}
+	}
+	if len(resp.Invalid) > 0 {
+		fmt.Printf("\n--- 无法导入 ---\n")
+		for i, issue := range resp.Invalid {
+			if i == previewLimit {
+				fmt.Printf("... 还有 %d 行\n", len(resp.Invalid)-previewLimit)
+				break
+			}
+			fmt.Printf("第%d行: %s\n", issue.Line, issue.Reason)
+		}
+	}
+}
+
+// printImportResult 显示导入结果
+func printImportResult(resp *model.ImportWordsResponse, dryRun bool) {
+	if dryRun {
+		fmt.Println("\n预览模式，没有写入任何单词")
+		return
+	}
+	if resp.Added == 0 {
+		fmt.Println("\n没有导入新单词")
+		return
+	}
+	fmt.Printf("\n✓ 已导入 %d 个单词，可以使用 undo 撤销本次导入\n", resp.Added)
+}
+
+// describeImportWord 显示单词的释义和例句，用于比较冲突
+func describeImportWord(word *model.WordEntity) string {
+	if word.Phrase == "" {
+		return fmt.Sprintf("“%s”", word.C)
+	}
+	return fmt.Sprintf("“%s / %s”", word.C, word.Phrase)
+}
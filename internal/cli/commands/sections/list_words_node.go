@@ -18,6 +18,7 @@ func NewListWords(service *sections.Service) *ListWordsNode {
 			ID:       "listWords",
 			Name:     "查看单词",
 			Command:  "3",
+			Order:    2,
 			Children: make(map[string]model.MenuNode),
 			Handler: func(ctx *model.MenuContext) error {
 				req := &model.ListWordsRequest{
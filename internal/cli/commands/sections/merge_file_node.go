@@ -0,0 +1,184 @@
+package sections
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/sections"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// conflictPolicyNames 冲突处理方式的名称
+var conflictPolicyNames = map[model.ConflictPolicy]string{
+	model.ConflictKeepOurs:   "保留当前",
+	model.ConflictKeepTheirs: "使用对方",
+	model.ConflictCombine:    "合并释义",
+}
+
+// MergeFileNode 合并数据文件节点
+// 命令行用法: merge 文件 [--policy keep-ours|keep-theirs|combine|ask] [--dry-run]
+type MergeFileNode struct {
+	*model.BaseMenuNode
+	service *sections.Service
+}
+
+// NewMergeFile 创建合并数据文件节点
+func NewMergeFile(service *sections.Service) *MergeFileNode {
+	node := &MergeFileNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "merge",
+			Name:     "合并数据文件",
+			Command:  "8",
+			Order:    8,
+			Children: make(map[string]model.MenuNode),
+		},
+		service: service,
+	}
+	node.Handler = node.handleMerge
+	return node
+}
+
+// handleMerge 把另一个数据文件合并到当前词库，最后显示合并报告
+// 命令行参数:
+// - in: 要合并的JSON数据文件、词库目录或日志存储文件，也可以作为第一个位置参数
+// - policy: 同名单词释义或例句不同时的处理方式，默认为keep-ours
+// - dry-run: 只预览，不写入
+func (n *MergeFileNode) handleMerge(ctx *model.MenuContext) error {
+	if ctx.Args == nil {
+		return n.mergeInteractive()
+	}
+
+	req := &model.MergeFileRequest{
+		Path:   stringArg(ctx.Args, "in"),
+		Policy: model.ConflictPolicy(stringArg(ctx.Args, "policy")),
+		Decide: askConflict,
+	}
+	if req.Path == "" {
+		return fmt.Errorf("请指定要合并的文件，如: merge other.json")
+	}
+	req.DryRun, _ = ctx.Args["dry-run"].(bool)
+
+	resp, err := n.service.MergeDataFile(req)
+	if resp != nil {
+		printMergeReport(resp, req.DryRun)
+	}
+	return err
+}
+
+// mergeInteractive 交互式合并：选择文件和冲突处理方式，预览后确认
+func (n *MergeFileNode) mergeInteractive() error {
+	fmt.Printf("\n=== 合并数据文件 ===\n")
+	fmt.Println("把另一个数据文件中的章节和单词合并到当前词库，章节按名称、单词按拼写匹配")
+	path, err := utils.Prompt("要合并的文件路径: ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %w", err)
+	}
+	if path == "" {
+		fmt.Println("已取消合并")
+		return nil
+	}
+
+	req := &model.MergeFileRequest{Path: path, Policy: model.ConflictAsk, Decide: askConflict, DryRun: true}
+	preview, err := n.service.MergeDataFile(req)
+	if err != nil {
+		return err
+	}
+	printMergeReport(preview, true)
+	if preview.Added == 0 && len(preview.Conflicts) == 0 {
+		fmt.Println("没有需要合并的内容")
+		return nil
+	}
+
+	if len(preview.Conflicts) > 0 {
+		fmt.Println("\n冲突的处理方式:")
+		fmt.Println("1. 保留当前词库中的内容")
+		fmt.Println("2. 使用对方文件中的内容")
+		fmt.Println("3. 合并两边的释义")
+		fmt.Println("4. 逐个询问")
+		switch input, _ := utils.Prompt("请选择 (回车为逐个询问): "); input {
+		case "1":
+			req.Policy = model.ConflictKeepOurs
+		case "2":
+			req.Policy = model.ConflictKeepTheirs
+		case "3":
+			req.Policy = model.ConflictCombine
+		}
+	}
+	input, _ := utils.Prompt("确认合并? (y/N): ")
+	if strings.ToLower(input) != "y" {
+		fmt.Println("已取消合并")
+		return nil
+	}
+
+	req.DryRun = false
+	resp, err := n.service.MergeDataFile(req)
+	if resp != nil {
+		printMergeReport(resp, false)
+	}
+	return err
+}
+
+// askConflict 显示两边的内容，询问冲突的处理方式
+func askConflict(conflict *model.MergeConflict) model.ConflictPolicy {
+	fmt.Printf("\n[%s] %s 的内容不同:\n", conflict.Section, conflict.Ours.W)
+	fmt.Printf("  当前: %s\n", describeImportWord(&conflict.Ours))
+	fmt.Printf("  对方: %s\n", describeImportWord(&conflict.Theirs))
+	for {
+		input, err := utils.Prompt("1. 保留当前  2. 使用对方  3. 合并释义 (回车保留当前): ")
+		if err != nil {
+			return model.ConflictKeepOurs
+		}
+		switch input {
+		case "", "1":
+			return model.ConflictKeepOurs
+		case "2":
+			return model.ConflictKeepTheirs
+		case "3":
+			return model.ConflictCombine
+		default:
+			fmt.Println("无效的选择，请重新输入")
+		}
+	}
+}
+
+// printMergeReport 显示合并报告
+func printMergeReport(resp *model.MergeFileResponse, dryRun bool) {
+	fmt.Printf("\n=== 合并报告: %s ===\n", resp.Source)
+	fmt.Printf("对方文件共 %d 个章节、%d 个单词\n", resp.Sections, resp.Words)
+	if len(resp.NewSections) > 0 {
+		fmt.Printf("新建章节 %d 个: %s\n", len(resp.NewSections), strings.Join(resp.NewSections, ", "))
+	}
+	fmt.Printf("新增单词 %d 个，内容相同 %d 个，冲突 %d 个\n", resp.Added, resp.Identical, len(resp.Conflicts))
+	if resp.KeptOurs+resp.TookTheirs+resp.Combined > 0 {
+		fmt.Printf("冲突处理: 保留当前 %d 个，使用对方 %d 个，合并释义 %d 个\n", resp.KeptOurs, resp.TookTheirs, resp.Combined)
+	}
+	if len(resp.SkippedSections) > 0 {
+		fmt.Printf("跳过由程序自动维护的章节: %s\n", strings.Join(resp.SkippedSections, ", "))
+	}
+
+	if len(resp.Conflicts) > 0 {
+		fmt.Printf("\n--- 冲突 ---\n")
+		for i, conflict := range resp.Conflicts {
+			if i == previewLimit {
+				fmt.Printf("... 还有 %d 个\n", len(resp.Conflicts)-previewLimit)
+				break
+			}
+			fmt.Printf("[%s] %s: 当前 %s，对方 %s", conflict.Section, conflict.Ours.W,
+				describeImportWord(&conflict.Ours), describeImportWord(&conflict.Theirs))
+			if name, ok := conflictPolicyNames[conflict.Resolution]; ok {
+				fmt.Printf(" → %s", name)
+			}
+			fmt.Println()
+		}
+	}
+
+	switch {
+	case dryRun:
+		fmt.Println("\n预览模式，没有写入任何修改")
+	case resp.Added+resp.TookTheirs+resp.Combined > 0:
+		fmt.Println("\n✓ 合并完成，可以使用 undo 撤销本次合并")
+	default:
+		fmt.Println("\n没有需要写入的修改")
+	}
+}
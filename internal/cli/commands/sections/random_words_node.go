@@ -1,6 +1,8 @@
 package sections
 
 import (
+	"fmt"
+
 	"github.com/ct-zh/englishLearn/internal/logic/sections"
 	"github.com/ct-zh/englishLearn/model"
 )
@@ -18,10 +20,33 @@ func NewRandomWords(service *sections.Service) *RandomWordsNode {
 			ID:       "randomWords",
 			Name:     "随机练习",
 			Command:  "4",
+			Order:    3,
 			Children: make(map[string]model.MenuNode),
 			Handler: func(ctx *model.MenuContext) error {
 				req := &model.RandomWordsRequest{
-					Count: 10,
+					Section: service.GetCurrentSection(),
+					Count:   10,
+				}
+
+				// 从命令参数中读取练习选项
+				if ctx.Args != nil {
+					if section, ok := ctx.Args["section"].(string); ok {
+						req.Section = section
+					}
+					if count, ok := ctx.Args["count"].(int); ok && count > 0 {
+						req.Count = count
+					}
+					if seed, exists := ctx.Args["seed"]; exists {
+						value, ok := seed.(int)
+						if !ok {
+							return fmt.Errorf("随机种子必须是整数: %v", seed)
+						}
+						seed := int64(value)
+						req.Seed = &seed
+					}
+					if weighted, ok := ctx.Args["weighted"].(bool); ok {
+						req.Weighted = weighted
+					}
 				}
 
 				_, err := service.RandomWords(req)
@@ -30,4 +55,4 @@ func NewRandomWords(service *sections.Service) *RandomWordsNode {
 		},
 		service: service,
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,100 @@
+package sections
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/sections"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// SectionNode 管理章节节点：重命名、删除、合并、拆分、归档章节
+// 该节点只能通过命令行调用，交互菜单中请在章节菜单里管理章节
+// 命令行用法: section rename|delete|merge|split|archive|unarchive --section 章节 [--new-name 新名称] [--to 目标章节]
+// [--policy skip|overwrite|newer] [--size 每章单词数] [--words 单词1,单词2] [--yes]
+type SectionNode struct {
+	*model.BaseMenuNode
+	service *sections.Service
+}
+
+// NewSection 创建管理章节节点
+func NewSection(service *sections.Service) *SectionNode {
+	node := &SectionNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "section",
+			Name:     "管理章节",
+			Command:  "s",
+			Order:    5,
+			Children: make(map[string]model.MenuNode),
+			Hidden:   true,
+		},
+		service: service,
+	}
+	node.Handler = node.handleSection
+	return node
+}
+
+// handleSection 根据action参数执行对应的章节操作，未指定章节时使用当前章节
+func (n *SectionNode) handleSection(ctx *model.MenuContext) error {
+	name := stringArg(ctx.Args, "section")
+	if name == "" {
+		name = n.service.CurrentSection()
+	}
+	if name == "" {
+		return fmt.Errorf("请使用 --section 指定章节")
+	}
+
+	switch action := stringArg(ctx.Args, "action"); action {
+	case "rename":
+		return n.service.RenameSection(&model.RenameSectionRequest{Name: name, NewName: stringArg(ctx.Args, "new-name")})
+	case "delete":
+		if yes, _ := ctx.Args["yes"].(bool); !yes && !confirmDeleteSection(name) {
+			fmt.Println("已取消删除")
+			return nil
+		}
+		return n.service.DeleteSection(&model.DeleteSectionRequest{Name: name})
+	case "merge":
+		req := &model.MergeSectionsRequest{
+			Source: name,
+			Target: stringArg(ctx.Args, "to"),
+			Policy: model.DuplicatePolicy(stringArg(ctx.Args, "policy")),
+		}
+		if req.Target == "" {
+			return fmt.Errorf("请使用 --to 指定目标章节")
+		}
+		_, err := n.service.MergeSections(req)
+		return err
+	case "split":
+		req := &model.SplitSectionRequest{Name: name, NewName: stringArg(ctx.Args, "new-name")}
+		if size, ok := ctx.Args["size"].(int); ok {
+			req.Size = size
+		}
+		req.Words = splitList(stringArg(ctx.Args, "words"))
+		_, err := n.service.SplitSection(req)
+		return err
+	case "archive", "unarchive":
+		return n.service.ArchiveSection(&model.ArchiveSectionRequest{Name: name, Archived: action == "archive"})
+	case "":
+		return fmt.Errorf("请指定操作: rename、delete、merge、split、archive 或 unarchive")
+	default:
+		return fmt.Errorf("未知的章节操作 '%s'，可用操作: rename、delete、merge、split、archive、unarchive", action)
+	}
+}
+
+// confirmDeleteSection 删除章节前向用户确认
+func confirmDeleteSection(name string) bool {
+	input, _ := utils.Prompt(fmt.Sprintf("删除章节 %s 会同时删除其中的全部单词和复习记录，确认删除? (y/N): ", name))
+	return strings.ToLower(input) == "y"
+}
+
+// splitList 解析以逗号分隔的列表，忽略空项
+func splitList(input string) []string {
+	var items []string
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -3,6 +3,7 @@ package sections
 import (
 	"fmt"
 
+	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/internal/dao"
 	"github.com/ct-zh/englishLearn/internal/logic/sections"
 	"github.com/ct-zh/englishLearn/model"
@@ -17,7 +18,7 @@ type SectionsNode struct {
 // NewSections 创建章节节点
 func NewSections() *SectionsNode {
 	// 创建DAO工厂和service
-	daoFactory := dao.NewDAOFactory("../../data")
+	daoFactory := dao.NewDAOFactory(config.DefaultConfig().DataFilePath)
 	sectionDAO := daoFactory.GetSectionDAO()
 	service := sections.NewService(sectionDAO)
 
@@ -26,6 +27,7 @@ func NewSections() *SectionsNode {
 			ID:       "sections",
 			Name:     "按章节记忆",
 			Command:  "1",
+			Order:    1,
 			Children: make(map[string]model.MenuNode),
 			Handler: func(ctx *model.MenuContext) error {
 				fmt.Println("进入章节管理模式...")
@@ -1,35 +1,60 @@
 package sections
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"os"
+	"math/rand"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ct-zh/englishLearn/internal/cli/commands/review"
+	"github.com/ct-zh/englishLearn/internal/logic/practice"
+	reviewLogic "github.com/ct-zh/englishLearn/internal/logic/review"
 	"github.com/ct-zh/englishLearn/internal/logic/sections"
 	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
 )
 
+// errReselect 章节操作菜单返回章节列表重新选择，章节被删除或合并后也会返回该错误
+var errReselect = errors.New("重新选择章节")
+
 // SelectSectionNode 选择章节节点
 type SelectSectionNode struct {
 	*model.BaseMenuNode
-	service     *sections.Service
-	currentPage int
-	pageSize    int
+	service       *sections.Service
+	reviewService *reviewLogic.Service
+	currentPage   int
+	pageSize      int
+	showArchived  bool                  // 是否列出已归档的章节
+	sortMode      model.SectionSortMode // 章节列表的排序方式
+}
+
+// sectionSortModes 章节列表可切换的排序方式及其名称，按切换顺序排列
+var sectionSortModes = []struct {
+	mode model.SectionSortMode
+	name string
+}{
+	{model.SectionSortStored, "创建顺序"},
+	{model.SectionSortName, "名称"},
+	{model.SectionSortDate, "名称中的日期"},
+	{model.SectionSortCreated, "创建时间"},
 }
 
 // NewSelectSection 创建选择章节节点
-func NewSelectSection(service *sections.Service) *SelectSectionNode {
+func NewSelectSection(service *sections.Service, reviewService *reviewLogic.Service) *SelectSectionNode {
 	node := &SelectSectionNode{
 		BaseMenuNode: &model.BaseMenuNode{
 			ID:       "selectSection",
 			Name:     "选择章节",
 			Command:  "2",
+			Order:    2,
 			Children: make(map[string]model.MenuNode),
 		},
-		service:     service,
-		currentPage: 1,
-		pageSize:    5, // 每页显示5个章节
+		service:       service,
+		reviewService: reviewService,
+		currentPage:   1,
+		pageSize:      5, // 每页显示5个章节
 	}
 
 	node.Handler = node.handleSelectSection
@@ -45,8 +70,10 @@ func (n *SelectSectionNode) handleSelectSection(ctx *model.MenuContext) error {
 	for {
 		// 获取章节列表
 		req := &model.ListSectionsRequest{
-			Page: n.currentPage,
-			Size: n.pageSize,
+			Page:     n.currentPage,
+			Size:     n.pageSize,
+			Archived: n.showArchived,
+			Sort:     n.sortMode,
 		}
 
 		resp, err := n.service.ListSections(req)
@@ -56,13 +83,30 @@ func (n *SelectSectionNode) handleSelectSection(ctx *model.MenuContext) error {
 		}
 
 		if len(resp.Sections) == 0 {
-			fmt.Println("没有找到任何章节")
-			return nil
+			if n.showArchived {
+				fmt.Println("没有已归档的章节")
+				n.showArchived = false
+				n.currentPage = 1
+				continue
+			}
+			if !n.hasArchived() {
+				fmt.Println("没有找到任何章节")
+				return nil
+			}
+			fmt.Println("所有章节都已归档")
 		}
 
 		// 显示章节列表
-		fmt.Printf("\n=== 章节列表 (第%d页/共%d页) ===\n", resp.CurrentPage, resp.TotalPages)
+		title := "章节列表"
+		if n.showArchived {
+			title = "已归档章节"
+		}
+		fmt.Printf("\n=== %s (第%d页/共%d页，按%s排序) ===\n", title, resp.CurrentPage, resp.TotalPages, n.sortName())
 		for i, section := range resp.Sections {
+			if model.IsManagedSection(section.Name) {
+				fmt.Printf("%d. %s (包含 %d 个单词，自动维护)\n", i+1, section.Name, len(section.Words))
+				continue
+			}
 			fmt.Printf("%d. %s (包含 %d 个单词)\n", i+1, section.Name, len(section.Words))
 		}
 
@@ -74,14 +118,23 @@ func (n *SelectSectionNode) handleSelectSection(ctx *model.MenuContext) error {
 		if resp.HasNext {
 			fmt.Println("n. 下一页")
 		}
+		fmt.Println("o. 切换排序方式")
+		if n.showArchived {
+			fmt.Println("a. 返回未归档章节列表")
+		} else {
+			fmt.Println("a. 查看已归档章节")
+		}
 		fmt.Println("b. 返回上级菜单")
-		fmt.Printf("请选择章节序号(1-%d)或操作: ", len(resp.Sections))
+		if len(resp.Sections) > 0 {
+			fmt.Printf("请选择章节序号(1-%d)或操作: ", len(resp.Sections))
+		} else {
+			fmt.Print("请选择操作: ")
+		}
 
 		// 读取用户输入
-		var input string
-		if _, err := fmt.Scanln(&input); err != nil {
-			fmt.Printf("输入错误: %v\n", err)
-			continue
+		input, err := utils.ReadLine()
+		if err != nil {
+			return fmt.Errorf("输入错误: %w", err)
 		}
 		
 		switch input {
@@ -97,6 +150,12 @@ func (n *SelectSectionNode) handleSelectSection(ctx *model.MenuContext) error {
 			} else {
 				fmt.Println("已经是最后一页了")
 			}
+		case "o":
+			n.nextSortMode()
+			n.currentPage = 1
+		case "a":
+			n.showArchived = !n.showArchived
+			n.currentPage = 1
 		case "b":
 			return model.ErrBack
 		default:
@@ -119,8 +178,10 @@ func (n *SelectSectionNode) handleSelectSection(ctx *model.MenuContext) error {
 					fmt.Printf("\n✓ 已选择章节: %s (包含 %d 个单词)\n",
 						selectResp.Selected.Name, selectResp.WordCount)
 
-					// 显示章节操作菜单
-					return n.showSectionMenu(ctx, &selectResp.Selected)
+					// 显示章节操作菜单，需要重新选择时回到章节列表
+					if err := n.showSectionMenu(ctx, &selectResp.Selected); err != errReselect {
+						return err
+					}
 				}
 			} else {
 				fmt.Println("无效的选择，请重新输入")
@@ -129,7 +190,7 @@ func (n *SelectSectionNode) handleSelectSection(ctx *model.MenuContext) error {
 	}
 }
 
-// showSectionMenu 显示章节操作菜单
+// showSectionMenu 显示章节操作菜单，返回errReselect时由handleSelectSection重新列出章节
 func (n *SelectSectionNode) showSectionMenu(ctx *model.MenuContext, section *model.SectionEntity) error {
 	for {
 		fmt.Printf("\n=== 章节: %s ===\n", section.Name)
@@ -138,13 +199,30 @@ func (n *SelectSectionNode) showSectionMenu(ctx *model.MenuContext, section *mod
 		fmt.Println("3. 随机练习")
 		fmt.Println("4. 搜索单词")
 		fmt.Println("5. 重新选择章节")
+		fmt.Println("6. 闪卡测验")
+		fmt.Println("7. 拼写听写")
+		fmt.Println("8. 完形填空")
+		fmt.Println("9. 选择题")
+		fmt.Println("e. 编辑单词")
+		fmt.Println("d. 删除单词")
+		fmt.Println("m. 移动单词到其他章节")
+		fmt.Println("c. 复制单词到其他章节")
+		fmt.Println("r. 重命名章节")
+		fmt.Println("x. 删除章节")
+		fmt.Println("g. 合并到其他章节")
+		fmt.Println("s. 拆分章节")
+		fmt.Println("p. 批量粘贴单词")
+		if section.Archived {
+			fmt.Println("h. 取消归档")
+		} else {
+			fmt.Println("h. 归档章节(不再显示在章节列表中)")
+		}
 		fmt.Println("b. 返回上级菜单")
 		fmt.Print("请选择操作: ")
 
-		var choice string
-		if _, err := fmt.Scanln(&choice); err != nil {
-			fmt.Printf("输入错误: %v\n", err)
-			continue
+		choice, err := utils.ReadLine()
+		if err != nil {
+			return fmt.Errorf("输入错误: %w", err)
 		}
 
 		switch choice {
@@ -165,14 +243,77 @@ func (n *SelectSectionNode) showSectionMenu(ctx *model.MenuContext, section *mod
 				fmt.Printf("搜索单词失败: %v\n", err)
 			}
 		case "5":
-			// 重新选择章节，如果用户在章节列表中选择返回，则直接返回上级菜单
-			if err := n.handleSelectSection(ctx); err != nil {
-				if err == model.ErrBack {
-					return model.ErrBack
-				}
-				fmt.Printf("选择章节失败: %v\n", err)
+			return errReselect
+		case "6":
+			if err := n.handleFlashcardQuiz(section.Name); err != nil {
+				fmt.Printf("闪卡测验失败: %v\n", err)
+			}
+		case "7":
+			if err := n.handleDictation(section.Name); err != nil {
+				fmt.Printf("拼写听写失败: %v\n", err)
+			}
+		case "8":
+			if err := n.handleCloze(section.Name); err != nil {
+				fmt.Printf("完形填空失败: %v\n", err)
+			}
+		case "9":
+			if err := n.handleChoiceQuiz(section.Name); err != nil {
+				fmt.Printf("选择题练习失败: %v\n", err)
 			}
-			// 如果成功选择了新章节，会返回新的章节操作菜单，这里不需要额外处理
+		case "e":
+			if err := n.handleEditWord(section.Name); err != nil {
+				fmt.Printf("编辑单词失败: %v\n", err)
+			}
+		case "d":
+			if err := n.handleDeleteWord(section.Name); err != nil {
+				fmt.Printf("删除单词失败: %v\n", err)
+			}
+		case "m":
+			if err := n.handleTransferWord(section.Name, true); err != nil {
+				fmt.Printf("移动单词失败: %v\n", err)
+			}
+		case "c":
+			if err := n.handleTransferWord(section.Name, false); err != nil {
+				fmt.Printf("复制单词失败: %v\n", err)
+			}
+		case "r":
+			if err := n.handleRenameSection(section); err != nil {
+				fmt.Printf("重命名章节失败: %v\n", err)
+			}
+		case "x":
+			if !confirmDeleteSection(section.Name) {
+				fmt.Println("已取消删除")
+				continue
+			}
+			if err := n.service.DeleteSection(&model.DeleteSectionRequest{Name: section.Name}); err != nil {
+				fmt.Printf("删除章节失败: %v\n", err)
+				continue
+			}
+			// 章节已不存在，回到章节列表
+			return errReselect
+		case "g":
+			merged, err := n.handleMergeSection(section.Name)
+			if err != nil {
+				fmt.Printf("合并章节失败: %v\n", err)
+			}
+			if merged {
+				return errReselect
+			}
+		case "s":
+			if err := n.handleSplitSection(section.Name); err != nil {
+				fmt.Printf("拆分章节失败: %v\n", err)
+			}
+		case "p":
+			if err := n.handlePasteWords(section.Name); err != nil {
+				fmt.Printf("批量添加单词失败: %v\n", err)
+			}
+		case "h":
+			req := &model.ArchiveSectionRequest{Name: section.Name, Archived: !section.Archived}
+			if err := n.service.ArchiveSection(req); err != nil {
+				fmt.Printf("归档章节失败: %v\n", err)
+				continue
+			}
+			section.Archived = req.Archived
 		case "b":
 			return model.ErrBack
 		default:
@@ -181,24 +322,129 @@ func (n *SelectSectionNode) showSectionMenu(ctx *model.MenuContext, section *mod
 	}
 }
 
+// sortName 当前排序方式的名称
+func (n *SelectSectionNode) sortName() string {
+	for _, item := range sectionSortModes {
+		if item.mode == n.sortMode {
+			return item.name
+		}
+	}
+	return string(n.sortMode)
+}
+
+// nextSortMode 切换到下一种排序方式
+func (n *SelectSectionNode) nextSortMode() {
+	for i, item := range sectionSortModes {
+		if item.mode == n.sortMode {
+			n.sortMode = sectionSortModes[(i+1)%len(sectionSortModes)].mode
+			return
+		}
+	}
+	n.sortMode = model.SectionSortStored
+}
+
+// hasArchived 是否存在已归档的章节
+func (n *SelectSectionNode) hasArchived() bool {
+	resp, err := n.service.ListSections(&model.ListSectionsRequest{Page: 1, Size: 1, Archived: true})
+	return err == nil && resp.Total > 0
+}
+
+// handleRenameSection 处理重命名章节，成功后菜单显示新的名称
+func (n *SelectSectionNode) handleRenameSection(section *model.SectionEntity) error {
+	newName, err := utils.Prompt("请输入新的章节名称: ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %v", err)
+	}
+
+	if err := n.service.RenameSection(&model.RenameSectionRequest{Name: section.Name, NewName: newName}); err != nil {
+		return err
+	}
+	section.Name = newName
+	return nil
+}
+
+// handleMergeSection 处理将当前章节合并到其他章节，返回是否已合并
+func (n *SelectSectionNode) handleMergeSection(sectionName string) (bool, error) {
+	target, err := utils.Prompt("请输入目标章节名称: ")
+	if err != nil {
+		return false, fmt.Errorf("输入错误: %v", err)
+	}
+
+	fmt.Println("目标章节中已有同名单词时：")
+	fmt.Println("1. 保留目标章节中的单词")
+	fmt.Println("2. 使用本章节的单词覆盖")
+	fmt.Println("3. 保留修改时间较新的单词")
+	input, _ := utils.Prompt("请输入选项(默认1): ")
+	policy := model.DuplicateSkip
+	switch input {
+	case "2":
+		policy = model.DuplicateOverwrite
+	case "3":
+		policy = model.DuplicateNewer
+	}
+
+	req := &model.MergeSectionsRequest{Source: sectionName, Target: target, Policy: policy}
+	if _, err := n.service.MergeSections(req); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// handleSplitSection 处理拆分章节：按数量拆分，或选择单词拆分到新章节
+func (n *SelectSectionNode) handleSplitSection(sectionName string) error {
+	fmt.Println("1. 按数量拆分")
+	fmt.Println("2. 选择单词拆分到新章节")
+	mode, _ := utils.Prompt("请选择拆分方式(默认1): ")
+
+	req := &model.SplitSectionRequest{Name: sectionName}
+	if mode == "2" {
+		// 不再列出整个章节，序号与"查看单词列表"中显示的一致
+		words, err := n.service.SectionWords(sectionName)
+		if err != nil {
+			return err
+		}
+		input, err := utils.Prompt(fmt.Sprintf("请输入要拆分出的单词序号，与查看单词列表中的序号一致(如 1,3,5-8，共%d个): ", len(words)))
+		if err != nil {
+			return fmt.Errorf("输入错误: %v", err)
+		}
+		indexes, err := parseSelection(input, len(words))
+		if err != nil {
+			return err
+		}
+		for _, i := range indexes {
+			req.Words = append(req.Words, words[i-1].W)
+		}
+		if req.NewName, err = utils.Prompt("请输入新章节名称: "); err != nil {
+			return fmt.Errorf("输入错误: %v", err)
+		}
+	} else {
+		input, err := utils.Prompt("请输入每个章节的单词数量: ")
+		if err != nil {
+			return fmt.Errorf("输入错误: %v", err)
+		}
+		if req.Size, err = strconv.Atoi(input); err != nil || req.Size <= 0 {
+			return fmt.Errorf("无效的单词数量: %s", input)
+		}
+	}
+
+	_, err := n.service.SplitSection(req)
+	return err
+}
+
 // handleAddWord 处理添加单词
 func (n *SelectSectionNode) handleAddWord(sectionName string) error {
-	fmt.Print("请输入单词: ")
-	var word string
-	if _, err := fmt.Scanln(&word); err != nil {
+	// 整行读取，支持 "cleared out" 这类多词条目
+	word, err := utils.Prompt("请输入单词: ")
+	if err != nil {
 		return fmt.Errorf("输入错误: %v", err)
 	}
 
-	fmt.Print("请输入中文释义: ")
-	var translation string
-	if _, err := fmt.Scanln(&translation); err != nil {
+	translation, err := utils.Prompt("请输入中文释义: ")
+	if err != nil {
 		return fmt.Errorf("输入错误: %v", err)
 	}
 
-	fmt.Print("请输入例句(可选，直接回车跳过): ")
-	reader := bufio.NewReader(os.Stdin)
-	phrase, _ := reader.ReadString('\n')
-	phrase = strings.TrimSpace(phrase)
+	phrase, _ := utils.Prompt("请输入例句(可选，直接回车跳过): ")
 
 	req := &model.AddWordRequest{
 		Word:        word,
@@ -210,6 +456,77 @@ func (n *SelectSectionNode) handleAddWord(sectionName string) error {
 	return n.service.AddWord(req)
 }
 
+// handlePasteWords 批量粘贴单词列表，自动识别格式，修改后导入到当前章节或其他章节
+func (n *SelectSectionNode) handlePasteWords(sectionName string) error {
+	text, err := readPastedText()
+	if err != nil {
+		return err
+	}
+	return editWordList(n.service, sections.ParseWordList(text), sectionName, pasteSource)
+}
+
+// handleEditWord 处理编辑单词，直接回车的项保持不变
+func (n *SelectSectionNode) handleEditWord(sectionName string) error {
+	word, err := utils.Prompt("请输入要编辑的单词: ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %v", err)
+	}
+
+	newWord, _ := utils.Prompt("请输入新的拼写(直接回车保持不变): ")
+	translation, _ := utils.Prompt("请输入新的中文释义(直接回车保持不变): ")
+	phrase, _ := utils.Prompt("请输入新的例句(直接回车保持不变): ")
+
+	req := &model.UpdateWordRequest{
+		Section:     sectionName,
+		Word:        word,
+		NewWord:     newWord,
+		Translation: translation,
+		Phrase:      phrase,
+	}
+
+	return n.service.UpdateWord(req)
+}
+
+// handleDeleteWord 处理删除单词，删除前需要确认
+func (n *SelectSectionNode) handleDeleteWord(sectionName string) error {
+	word, err := utils.Prompt("请输入要删除的单词: ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %v", err)
+	}
+
+	confirm, _ := utils.Prompt(fmt.Sprintf("确认从章节 %s 删除单词 %s? (y/N): ", sectionName, word))
+	if strings.ToLower(confirm) != "y" {
+		fmt.Println("已取消删除")
+		return nil
+	}
+
+	return n.service.DeleteWord(&model.DeleteWordRequest{Section: sectionName, Word: word})
+}
+
+// handleTransferWord 处理移动或复制单词到其他章节
+func (n *SelectSectionNode) handleTransferWord(sectionName string, move bool) error {
+	word, err := utils.Prompt("请输入单词: ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %v", err)
+	}
+
+	target, err := utils.Prompt("请输入目标章节名称: ")
+	if err != nil {
+		return fmt.Errorf("输入错误: %v", err)
+	}
+
+	req := &model.TransferWordRequest{
+		Section: sectionName,
+		Word:    word,
+		Target:  target,
+	}
+
+	if move {
+		return n.service.MoveWord(req)
+	}
+	return n.service.CopyWord(req)
+}
+
 // handleListWords 处理查看单词列表
 func (n *SelectSectionNode) handleListWords(sectionName string) error {
 	req := &model.ListWordsRequest{
@@ -224,12 +541,8 @@ func (n *SelectSectionNode) handleListWords(sectionName string) error {
 
 // handleRandomWords 处理随机练习
 func (n *SelectSectionNode) handleRandomWords(sectionName string) error {
-	fmt.Print("请输入练习单词数量(默认10): ")
-	var input string
-	if _, err := fmt.Scanln(&input); err != nil {
-		// 输入错误时使用默认值
-		input = ""
-	}
+	// 输入错误时使用默认值
+	input, _ := utils.Prompt("请输入练习单词数量(默认10): ")
 
 	count := 10
 	if c := parseChoice(input, 100); c > 0 {
@@ -247,9 +560,8 @@ func (n *SelectSectionNode) handleRandomWords(sectionName string) error {
 
 // handleSearchWords 处理搜索单词
 func (n *SelectSectionNode) handleSearchWords(sectionName string) error {
-	fmt.Print("请输入搜索关键词: ")
-	var keyword string
-	if _, err := fmt.Scanln(&keyword); err != nil {
+	keyword, err := utils.Prompt("请输入搜索关键词: ")
+	if err != nil {
 		return fmt.Errorf("输入错误: %v", err)
 	}
 
@@ -258,6 +570,77 @@ func (n *SelectSectionNode) handleSearchWords(sectionName string) error {
 		Section: sectionName,
 	}
 
-	_, err := n.service.SearchWord(req)
+	_, err = n.service.SearchWord(req)
 	return err
-}
\ No newline at end of file
+}
+
+// handleFlashcardQuiz 处理闪卡测验：显示单词，回车后显示释义，再自评掌握程度
+func (n *SelectSectionNode) handleFlashcardQuiz(sectionName string) error {
+	items, err := n.reviewService.SectionItems(sectionName, 0)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== 闪卡测验: %s (共 %d 个单词) ===\n", sectionName, len(items))
+	session := n.reviewService.StartSession(sectionName, model.SessionModeFlashcard)
+	return review.RunFlashcards(session, items)
+}
+
+// handleDictation 处理拼写听写：显示中文释义，由用户输入英文单词
+func (n *SelectSectionNode) handleDictation(sectionName string) error {
+	items, err := n.reviewService.SectionItems(sectionName, 0)
+	if err != nil {
+		return err
+	}
+
+	input, _ := utils.Prompt("是否显示遮盖单词后的例句作为提示? (Y/n): ")
+	showPhrase := strings.ToLower(input) != "n"
+
+	fmt.Printf("\n=== 拼写听写: %s (共 %d 个单词) ===\n", sectionName, len(items))
+	session := n.reviewService.StartSession(sectionName, model.SessionModeDictation)
+	return review.RunDictation(session, items, showPhrase)
+}
+
+// handleCloze 处理完形填空：挖去例句中的目标单词，由用户填写
+func (n *SelectSectionNode) handleCloze(sectionName string) error {
+	items, err := n.reviewService.SectionItems(sectionName, 0)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n=== 完形填空: %s ===\n", sectionName)
+	session := n.reviewService.StartSession(sectionName, model.SessionModeCloze)
+	return review.RunCloze(session, items)
+}
+
+// handleChoiceQuiz 处理选择题练习，干扰项从所有章节中挑选
+func (n *SelectSectionNode) handleChoiceQuiz(sectionName string) error {
+	items, err := n.reviewService.SectionItems(sectionName, 0)
+	if err != nil {
+		return err
+	}
+	pool, err := n.reviewService.AllItems()
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("请选择出题方向：")
+	fmt.Println("1. 看英文选释义")
+	fmt.Println("2. 看释义选英文")
+	fmt.Println("3. 混合")
+	input, _ := utils.Prompt("请输入选项(默认1): ")
+	direction := practice.DirectionEnToCn
+	switch input {
+	case "2":
+		direction = practice.DirectionCnToEn
+	case "3":
+		direction = practice.DirectionMixed
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	questions := practice.BuildChoiceQuestions(items, pool, direction, rng)
+
+	fmt.Printf("\n=== 选择题: %s ===\n", sectionName)
+	session := n.reviewService.StartSession(sectionName, model.SessionModeChoice)
+	return review.RunChoiceQuiz(session, questions, len(items))
+}
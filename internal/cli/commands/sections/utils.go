@@ -1,5 +1,11 @@
 package sections
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 // parseChoice 解析用户输入的选择
 func parseChoice(input string, max int) int {
 	if input == "" {
@@ -20,4 +26,37 @@ func parseChoice(input string, max int) int {
 		return choice
 	}
 	return 0
-}
\ No newline at end of file
+}
+
+// parseSelection 解析以逗号分隔的序号和范围，如 "1,3,5-8"，返回去重后按输入顺序排列的序号
+func parseSelection(input string, max int) ([]int, error) {
+	var indexes []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		first, last := part, part
+		if i := strings.Index(part, "-"); i > 0 {
+			first, last = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		start, err1 := strconv.Atoi(first)
+		end, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil || start < 1 || end > max || start > end {
+			return nil, fmt.Errorf("无效的序号: %s", part)
+		}
+
+		for i := start; i <= end; i++ {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("没有选择任何单词")
+	}
+	return indexes, nil
+}
@@ -0,0 +1,154 @@
+package sections
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/sections"
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// pasteSource 粘贴的单词列表在修改历史中显示的来源
+const pasteSource = "粘贴的单词列表"
+
+// readPastedText 读取粘贴的多行文本，以单独一行的"."、连续两个空行或输入结束为止
+func readPastedText() (string, error) {
+	fmt.Println("请粘贴单词列表，每行一个单词，如 \"dam 水坝\" 或 \"palatable - 可口的 - 例句\"")
+	fmt.Println("粘贴完成后输入单独一行的 . 并回车（或连续输入两个空行）结束:")
+
+	var lines []string
+	blank := 0
+	for {
+		line, err := utils.ReadLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("输入错误: %w", err)
+		}
+		if line == "." {
+			break
+		}
+		if line == "" {
+			if blank++; blank == 2 {
+				break
+			}
+			continue
+		}
+		blank = 0
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// editWordList 显示解析出的单词，用户逐行修改或删除后导入到章节
+// section为默认的章节，为空时需要用户输入；source为修改历史中显示的来源
+func editWordList(service *sections.Service, list *model.ParsedWordList, section, source string) error {
+	words := list.Words
+	if len(words) == 0 {
+		fmt.Println("没有识别到单词")
+		return nil
+	}
+	fmt.Printf("\n识别的格式: %s\n", list.Layout)
+
+	for {
+		printParsedWords(words)
+		input, err := utils.Prompt("输入 e序号 修改一行，d序号 删除一行，y 确认导入，q 取消: ")
+		if err != nil {
+			return fmt.Errorf("输入错误: %w", err)
+		}
+		if input == "" {
+			continue
+		}
+
+		cmd := strings.ToLower(input[:1])
+		switch cmd {
+		case "y":
+			return importWordList(service, words, section, source)
+		case "q":
+			fmt.Println("已取消导入")
+			return nil
+		case "e", "d":
+			index, err := strconv.Atoi(strings.TrimSpace(input[1:]))
+			if err != nil || index < 1 || index > len(words) {
+				fmt.Printf("无效的序号，请输入1-%d之间的数字\n", len(words))
+				continue
+			}
+			if cmd == "d" {
+				words = append(words[:index-1], words[index:]...)
+				if len(words) == 0 {
+					fmt.Println("已删除全部单词")
+					return nil
+				}
+				continue
+			}
+			editParsedWord(&words[index-1])
+		default:
+			fmt.Println("无效的选择，请重新输入")
+		}
+	}
+}
+
+// printParsedWords 以表格显示解析出的单词，需要修改的行标出原因
+func printParsedWords(words []model.ParsedWord) {
+	issues := 0
+	fmt.Printf("\n序号  单词 | 释义 | 例句\n")
+	for i, item := range words {
+		meaning := strings.TrimSpace(item.Word.POS + " " + item.Word.C)
+		fmt.Printf("%3d.  %s | %s | %s\n", i+1, item.Word.W, meaning, item.Word.Phrase)
+		if item.Issue != "" {
+			fmt.Printf("      ✗ %s（第%d行: %s）\n", item.Issue, item.Line, item.Text)
+			issues++
+		}
+	}
+	if issues > 0 {
+		fmt.Printf("共 %d 个单词，其中 %d 行需要修改，不修改将不会导入\n", len(words), issues)
+	} else {
+		fmt.Printf("共 %d 个单词\n", len(words))
+	}
+}
+
+// editParsedWord 逐项修改一行，直接回车的项保持不变
+func editParsedWord(item *model.ParsedWord) {
+	if input, _ := utils.Prompt(fmt.Sprintf("单词 [%s]: ", item.Word.W)); input != "" {
+		item.Word.W = input
+	}
+	if input, _ := utils.Prompt(fmt.Sprintf("释义 [%s]: ", item.Word.C)); input != "" {
+		item.Word.C = input
+	}
+	input, _ := utils.Prompt(fmt.Sprintf("例句 [%s] (输入 - 清空): ", item.Word.Phrase))
+	switch input {
+	case "":
+	case "-":
+		item.Word.Phrase = ""
+	default:
+		item.Word.Phrase = input
+	}
+	sections.CheckParsedWord(item)
+}
+
+// importWordList 选择章节并预览，确认后导入
+func importWordList(service *sections.Service, words []model.ParsedWord, section, source string) error {
+	prompt := "导入到章节 (不存在时自动创建): "
+	if section != "" {
+		prompt = fmt.Sprintf("导入到章节 (回车为 %s，不存在时自动创建): ", section)
+	}
+	if input, _ := utils.Prompt(prompt); input != "" {
+		section = input
+	}
+
+	req := &model.ImportParsedWordsRequest{Section: section, Source: source, Words: words, DryRun: true}
+	preview, err := service.ImportParsedWords(req)
+	if err != nil {
+		return err
+	}
+	printImportPreview(preview)
+	return confirmImport(preview, func() (*model.ImportWordsResponse, error) {
+		req.DryRun = false
+		return service.ImportParsedWords(req)
+	})
+}
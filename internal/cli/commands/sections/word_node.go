@@ -0,0 +1,89 @@
+package sections
+
+import (
+	"fmt"
+
+	"github.com/ct-zh/englishLearn/internal/logic/sections"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// WordNode 管理单词节点：编辑、删除、移动或复制章节中的单词
+// 该节点只能通过命令行调用，交互菜单中请在章节菜单里编辑、删除、移动或复制单词
+// 命令行用法: word edit|delete|move|copy --section 章节 --word 单词 [--new-word 新拼写] [--chinese 释义] [--phrase 例句] [--to 目标章节]
+type WordNode struct {
+	*model.BaseMenuNode
+	service *sections.Service
+}
+
+// NewWord 创建管理单词节点
+func NewWord(service *sections.Service) *WordNode {
+	node := &WordNode{
+		BaseMenuNode: &model.BaseMenuNode{
+			ID:       "word",
+			Name:     "管理单词",
+			Command:  "w",
+			Order:    4,
+			Children: make(map[string]model.MenuNode),
+			Hidden:   true,
+		},
+		service: service,
+	}
+	node.Handler = node.handleWord
+	return node
+}
+
+// handleWord 根据action参数执行对应的单词操作，未指定章节时使用当前章节
+func (n *WordNode) handleWord(ctx *model.MenuContext) error {
+	section := stringArg(ctx.Args, "section")
+	if section == "" {
+		section = n.service.CurrentSection()
+	}
+	if section == "" {
+		return fmt.Errorf("请使用 --section 指定章节")
+	}
+	word := stringArg(ctx.Args, "word")
+	if word == "" {
+		return fmt.Errorf("请使用 --word 指定单词")
+	}
+
+	switch action := stringArg(ctx.Args, "action"); action {
+	case "edit":
+		translation := stringArg(ctx.Args, "chinese")
+		if translation == "" {
+			translation = stringArg(ctx.Args, "translation")
+		}
+		return n.service.UpdateWord(&model.UpdateWordRequest{
+			Section:     section,
+			Word:        word,
+			NewWord:     stringArg(ctx.Args, "new-word"),
+			Translation: translation,
+			Phrase:      stringArg(ctx.Args, "phrase"),
+		})
+	case "delete":
+		return n.service.DeleteWord(&model.DeleteWordRequest{Section: section, Word: word})
+	case "move", "copy":
+		req := &model.TransferWordRequest{Section: section, Word: word, Target: stringArg(ctx.Args, "to")}
+		if req.Target == "" {
+			return fmt.Errorf("请使用 --to 指定目标章节")
+		}
+		if action == "move" {
+			return n.service.MoveWord(req)
+		}
+		return n.service.CopyWord(req)
+	case "":
+		return fmt.Errorf("请指定操作: edit、delete、move 或 copy")
+	default:
+		return fmt.Errorf("未知的单词操作 '%s'，可用操作: edit、delete、move、copy", action)
+	}
+}
+
+// stringArg 读取字符串参数；解析器会把数字形式的值转为整数，这里转换回字符串
+func stringArg(args map[string]interface{}, key string) string {
+	switch value := args[key].(type) {
+	case string:
+		return value
+	case int:
+		return fmt.Sprint(value)
+	}
+	return ""
+}
@@ -1,7 +1,6 @@
 package cli
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +8,7 @@ import (
 	
 	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
 )
 
 // 错误常量
@@ -89,8 +89,11 @@ func (e *InteractiveEngine) displayCurrentMenu() {
 	}
 	
 	fmt.Println("请选择操作：")
-	for cmd, child := range children {
-		fmt.Printf("%s. %s\n", cmd, child.GetName())
+	for _, child := range model.SortedChildren(e.currentNode) {
+		if child.IsHidden() {
+			continue // 只能通过命令行调用的节点不在菜单中显示
+		}
+		fmt.Printf("%s. %s\n", child.GetCommand(), child.GetName())
 	}
 	
 	// 显示导航选项
@@ -103,12 +106,8 @@ func (e *InteractiveEngine) displayCurrentMenu() {
 
 // getUserInput 获取用户输入
 func (e *InteractiveEngine) getUserInput() (string, error) {
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(input), nil
+	// 与各命令节点共用同一个标准输入读取器，避免输入被其他读取器缓冲后丢失
+	return utils.ReadLine()
 }
 
 // handleInput 处理用户输入
@@ -125,7 +124,7 @@ func (e *InteractiveEngine) handleInput(input string) error {
 	
 	// 查找对应的子节点
 	children := e.currentNode.GetChildren()
-	if child, exists := children[input]; exists {
+	if child, exists := children[input]; exists && !child.IsHidden() {
 		return e.navigateToNode(child)
 	}
 	
@@ -52,9 +52,8 @@ func (r *CommandPathResolver) traverseNode(node model.MenuNode, path []string) {
 		}
 	}
 	
-	// 递归处理子节点
-	children := node.GetChildren()
-	for _, child := range children {
+	// 按显示顺序递归处理子节点，命令名冲突时结果保持确定
+	for _, child := range model.SortedChildren(node) {
 		r.traverseNode(child, currentPath)
 	}
 }
@@ -148,8 +147,34 @@ func (r *CommandPathResolver) parseCommandArgs(args []string) (string, map[strin
 			// 处理位置参数
 			switch cmd {
 			case "random":
-				if count, err := strconv.Atoi(arg); err == nil {
-					params["count"] = count
+				// 第一个数字为练习数量，第二个数字为随机种子
+				if value, err := strconv.Atoi(arg); err == nil {
+					if _, exists := params["count"]; !exists {
+						params["count"] = value
+					} else if _, exists := params["seed"]; !exists {
+						params["seed"] = value
+					}
+				}
+			case "import", "merge":
+				// 第一个位置参数为要读取的文件路径，如 import words.csv
+				// --file 已用于指定数据文件，所以使用 in 作为参数名
+				if _, exists := params["in"]; !exists {
+					params["in"] = arg
+				}
+			case "export":
+				// 第一个位置参数为导出的文件路径，如 export words.csv
+				if _, exists := params["out"]; !exists {
+					params["out"] = arg
+				}
+			case "undo":
+				// 位置参数为要撤销的修改序号，如 undo 3
+				if value, err := strconv.Atoi(arg); err == nil {
+					params["index"] = value
+				}
+			case "word", "section":
+				// 第一个位置参数为操作，如 word edit、section rename
+				if _, exists := params["action"]; !exists {
+					params["action"] = arg
 				}
 			case "add":
 				// 处理添加单词的位置参数
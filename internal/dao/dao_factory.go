@@ -3,14 +3,21 @@ package dao
 import (
 	"fmt"
 	"path/filepath"
+	"sync"
+
 	"github.com/ct-zh/englishLearn/config"
+	"github.com/ct-zh/englishLearn/model"
 )
 
 // DAOFactory DAO工厂
+// GetSectionDAO/GetSessionDAO/GetHistoryDAO返回的是代理，总是转发到当前数据文件对应的DAO实例，
+// 因此切换或回滚数据文件后，已经创建的Service无需重建即可使用新文件
 type DAOFactory struct {
 	dataFilePath string
-	sectionDAO   SectionDAOInterface
+	sectionDAO   SectionDAOInterface // 当前数据文件对应的DAO实例，切换文件后重新创建
+	sessionDAO   SessionDAOInterface
 	config       *config.Config // 添加配置引用
+	mutex        sync.Mutex
 }
 
 // NewDAOFactory 创建新的DAO工厂
@@ -28,14 +35,83 @@ func NewDAOFactoryWithConfig(cfg *config.Config) *DAOFactory {
 	}
 }
 
-// GetSectionDAO 获取章节DAO实例
+// GetSectionDAO 获取章节DAO，返回的代理始终作用于当前数据文件
 func (f *DAOFactory) GetSectionDAO() SectionDAOInterface {
+	return &sectionDAOProxy{factory: f}
+}
+
+// GetSessionDAO 获取练习会话日志DAO，日志文件与当前数据文件放在同一目录
+func (f *DAOFactory) GetSessionDAO() SessionDAOInterface {
+	return &sessionDAOProxy{factory: f}
+}
+
+// GetHistoryDAO 获取修改历史DAO，返回的代理始终作用于当前数据文件
+func (f *DAOFactory) GetHistoryDAO() HistoryDAOInterface {
+	return &historyDAOProxy{factory: f}
+}
+
+// currentSectionDAO 获取当前数据文件对应的章节DAO实例，路径为目录时使用目录词库，.vlog文件使用日志存储
+func (f *DAOFactory) currentSectionDAO() SectionDAOInterface {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	if f.sectionDAO == nil {
-		f.sectionDAO = NewSectionDAO(filepath.Dir(f.dataFilePath))
+		if config.IsLibraryDir(f.dataFilePath) {
+			f.sectionDAO = NewDirSectionDAO(f.dataFilePath)
+		} else if filepath.Ext(f.dataFilePath) == model.LogStoreExt {
+			f.sectionDAO = NewLogSectionDAO(f.dataFilePath)
+		} else {
+			f.sectionDAO = NewSectionDAO(f.dataFilePath)
+		}
 	}
 	return f.sectionDAO
 }
 
+// ReadDataFile 以只读方式读取数据文件（JSON文件、词库目录或日志存储）中的全部章节，用于合并其他数据文件
+// 不加锁，旧格式的数据只在内存中迁移，不会修改文件或在文件旁创建锁文件、备份等任何文件；
+// 文件有未完成的写入时返回错误，需要先用程序打开一次完成恢复
+func ReadDataFile(path string) ([]model.SectionEntity, error) {
+	var data *model.WordsFileDAO
+	var err error
+	if config.IsLibraryDir(path) {
+		data, err = NewDirSectionDAO(path).(*DirSectionDAOImpl).readOnly()
+	} else if filepath.Ext(path) == model.LogStoreExt {
+		data, err = NewLogSectionDAO(path).(*LogSectionDAOImpl).readOnly()
+	} else {
+		data, err = NewSectionDAO(path).(*SectionDAOImpl).readOnly()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make([]model.SectionEntity, 0, len(data.Sections))
+	for _, section := range data.Sections {
+		sections = append(sections, toEntity(section))
+	}
+	return sections, nil
+}
+
+// currentSessionDAO 获取当前数据文件对应的会话日志DAO实例
+func (f *DAOFactory) currentSessionDAO() SessionDAOInterface {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.sessionDAO == nil {
+		f.sessionDAO = NewSessionDAO(SessionLogPath(f.dataFilePath))
+	}
+	return f.sessionDAO
+}
+
+// switchDataFile 切换到新的数据文件，DAO实例在下次使用时重新创建
+func (f *DAOFactory) switchDataFile(path string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.sectionDAO = nil
+	f.sessionDAO = nil
+	f.dataFilePath = path
+}
+
 // ProvideDAOFactory 提供DAO工厂实例 (Wire Provider)
 func ProvideDAOFactory(cfg *config.Config) *DAOFactory {
 	return NewDAOFactoryWithConfig(cfg)
@@ -46,8 +122,20 @@ func ProvideSectionDAO(factory *DAOFactory) SectionDAOInterface {
 	return factory.GetSectionDAO()
 }
 
+// ProvideSessionDAO 提供练习会话日志DAO实例 (Wire Provider)
+func ProvideSessionDAO(factory *DAOFactory) SessionDAOInterface {
+	return factory.GetSessionDAO()
+}
+
+// ProvideHistoryDAO 提供修改历史DAO实例 (Wire Provider)
+func ProvideHistoryDAO(factory *DAOFactory) HistoryDAOInterface {
+	return factory.GetHistoryDAO()
+}
+
 // GetDataFilePath 获取数据文件路径
 func (f *DAOFactory) GetDataFilePath() string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 	return f.dataFilePath
 }
 
@@ -61,14 +149,8 @@ func (f *DAOFactory) ReloadDataFile(newFilePath string) error {
 		newFilePath = f.config.DataFilePath // 使用配置处理后的路径
 	}
 	
-	// 清理现有的DAO实例
-	f.sectionDAO = nil
-	
-	// 更新数据文件路径
-	f.dataFilePath = newFilePath
-	
-	// 重新初始化SectionDAO（延迟初始化）
-	// 下次调用GetSectionDAO时会自动创建新实例
+	// 切换数据文件，已创建的DAO代理在下次调用时使用新文件
+	f.switchDataFile(newFilePath)
 	
 	return nil
 }
@@ -84,11 +166,8 @@ func (f *DAOFactory) RollbackDataFile() error {
 		return err
 	}
 	
-	// 清理现有的DAO实例
-	f.sectionDAO = nil
-	
-	// 更新数据文件路径
-	f.dataFilePath = f.config.DataFilePath
+	// 切换回上一个数据文件
+	f.switchDataFile(f.config.DataFilePath)
 	
 	return nil
 }
@@ -101,6 +180,6 @@ func (f *DAOFactory) GetCurrentFileInfo() (map[string]interface{}, error) {
 	
 	// 如果没有配置引用，返回基本信息
 	info := make(map[string]interface{})
-	info["path"] = f.dataFilePath
+	info["path"] = f.GetDataFilePath()
 	return info, nil
 }
\ No newline at end of file
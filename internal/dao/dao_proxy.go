@@ -0,0 +1,133 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// sectionDAOProxy 章节DAO代理，每次调用都转发到工厂当前数据文件对应的DAO实例
+type sectionDAOProxy struct {
+	factory *DAOFactory
+}
+
+// CreateSection 创建章节
+func (p *sectionDAOProxy) CreateSection(ctx context.Context, section *model.SectionEntity) error {
+	return p.factory.currentSectionDAO().CreateSection(ctx, section)
+}
+
+// GetSection 根据名称获取章节
+func (p *sectionDAOProxy) GetSection(ctx context.Context, name string) (*model.SectionEntity, error) {
+	return p.factory.currentSectionDAO().GetSection(ctx, name)
+}
+
+// UpdateSection 更新章节
+func (p *sectionDAOProxy) UpdateSection(ctx context.Context, name string, section *model.SectionEntity) error {
+	return p.factory.currentSectionDAO().UpdateSection(ctx, name, section)
+}
+
+// DeleteSection 删除章节
+func (p *sectionDAOProxy) DeleteSection(ctx context.Context, name string) error {
+	return p.factory.currentSectionDAO().DeleteSection(ctx, name)
+}
+
+// ListSections 列出所有章节
+func (p *sectionDAOProxy) ListSections(ctx context.Context) ([]model.SectionEntity, error) {
+	return p.factory.currentSectionDAO().ListSections(ctx)
+}
+
+// SectionExists 检查章节是否存在
+func (p *sectionDAOProxy) SectionExists(ctx context.Context, name string) (bool, error) {
+	return p.factory.currentSectionDAO().SectionExists(ctx, name)
+}
+
+// AddWordToSection 向章节添加单词
+func (p *sectionDAOProxy) AddWordToSection(ctx context.Context, sectionName string, word model.WordEntity) error {
+	return p.factory.currentSectionDAO().AddWordToSection(ctx, sectionName, word)
+}
+
+// RemoveWordFromSection 从章节移除单词
+func (p *sectionDAOProxy) RemoveWordFromSection(ctx context.Context, sectionName string, wordText string) error {
+	return p.factory.currentSectionDAO().RemoveWordFromSection(ctx, sectionName, wordText)
+}
+
+// UpdateWord 更新章节中的单词
+func (p *sectionDAOProxy) UpdateWord(ctx context.Context, sectionName string, wordText string, word model.WordEntity) error {
+	return p.factory.currentSectionDAO().UpdateWord(ctx, sectionName, wordText, word)
+}
+
+// MoveWord 将单词移动到另一个章节
+func (p *sectionDAOProxy) MoveWord(ctx context.Context, from string, to string, wordText string) error {
+	return p.factory.currentSectionDAO().MoveWord(ctx, from, to, wordText)
+}
+
+// CopyWord 将单词复制到另一个章节
+func (p *sectionDAOProxy) CopyWord(ctx context.Context, from string, to string, wordText string) error {
+	return p.factory.currentSectionDAO().CopyWord(ctx, from, to, wordText)
+}
+
+// QueryWords 按条件查询单词
+func (p *sectionDAOProxy) QueryWords(ctx context.Context, query *model.WordQuery) (*model.WordQueryResult, error) {
+	return p.factory.currentSectionDAO().QueryWords(ctx, query)
+}
+
+// QuerySections 分页查询章节
+func (p *sectionDAOProxy) QuerySections(ctx context.Context, query *model.SectionQuery) (*model.SectionQueryResult, error) {
+	return p.factory.currentSectionDAO().QuerySections(ctx, query)
+}
+
+// sessionDAOProxy 会话日志DAO代理，每次调用都转发到工厂当前数据文件对应的DAO实例
+type sessionDAOProxy struct {
+	factory *DAOFactory
+}
+
+// AppendSession 追加一条练习会话记录
+func (p *sessionDAOProxy) AppendSession(ctx context.Context, session *model.SessionEntity) error {
+	return p.factory.currentSessionDAO().AppendSession(ctx, session)
+}
+
+// ListSessions 按记录顺序列出所有练习会话
+func (p *sessionDAOProxy) ListSessions(ctx context.Context) ([]model.SessionEntity, error) {
+	return p.factory.currentSessionDAO().ListSessions(ctx)
+}
+
+// historyDAOProxy 修改历史DAO代理，转发到工厂当前数据文件对应的章节DAO实例，日志存储不支持修改历史
+type historyDAOProxy struct {
+	factory *DAOFactory
+}
+
+// current 获取当前数据文件对应的修改历史DAO
+func (p *historyDAOProxy) current() (HistoryDAOInterface, error) {
+	history, ok := p.factory.currentSectionDAO().(HistoryDAOInterface)
+	if !ok {
+		return nil, ErrHistoryUnsupported
+	}
+	return history, nil
+}
+
+// ListHistory 列出保存的修改记录，最近的修改在前
+func (p *historyDAOProxy) ListHistory(ctx context.Context) ([]model.HistoryEntry, error) {
+	history, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	return history.ListHistory(ctx)
+}
+
+// RevertHistory 只撤销id对应的一次修改
+func (p *historyDAOProxy) RevertHistory(ctx context.Context, id string) (*model.HistoryEntry, error) {
+	history, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	return history.RevertHistory(ctx, id)
+}
+
+// RestoreHistory 将词库恢复到id对应的修改之前的状态
+func (p *historyDAOProxy) RestoreHistory(ctx context.Context, id string) (*model.HistoryEntry, error) {
+	history, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	return history.RestoreHistory(ctx, id)
+}
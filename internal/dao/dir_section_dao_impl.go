@@ -0,0 +1,333 @@
+package dao
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// maxSectionFileSlug 章节文件名（不含扩展名）的最大字符数
+const maxSectionFileSlug = 60
+
+// DirSectionDAOImpl 目录词库的章节DAO实现
+// 每个章节保存为目录中的一个JSON文件，索引文件index.json记录章节顺序和元数据，
+// 修改一个章节只会改动对应的文件，适合用git管理词库
+type DirSectionDAOImpl struct {
+	sectionOps
+	*fileStore
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewDirSectionDAO 创建目录词库的SectionDAO实例
+func NewDirSectionDAO(dir string) SectionDAOInterface {
+	d := &DirSectionDAOImpl{
+		fileStore: newFileStore(dir, model.LibraryIndexFileName, ".*.tmp-*"),
+		dir:       dir,
+	}
+	d.sectionOps = sectionOps{store: d, history: newHistoryStore(HistoryDirPath(dir))}
+	return d
+}
+
+// librarySection 章节文件的内容，章节的创建时间记录在索引中
+type librarySection struct {
+	Name     string             `json:"name"`
+	Words    []model.WordEntity `json:"words"`
+	Archived bool               `json:"archived,omitempty"`
+}
+
+// librarySnapshot 加载时词库目录的状态，保存时据此只写入发生变化的文件
+type librarySnapshot struct {
+	index    model.LibraryIndexDAO
+	indexRaw []byte
+	files    map[string][]byte // 章节文件名 -> 文件内容
+}
+
+// view 在锁内加载数据并执行只读操作
+func (d *DirSectionDAOImpl) view(fn func(data *model.WordsFileDAO) error) error {
+	unlock, err := d.lock(false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, _, err := d.loadData()
+	if err != nil {
+		return err
+	}
+	return fn(data)
+}
+
+// update 在锁内完成“加载-修改-保存”，fn返回错误时不保存
+func (d *DirSectionDAOImpl) update(fn func(data *model.WordsFileDAO) error) error {
+	unlock, err := d.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, snapshot, err := d.loadData()
+	if err != nil {
+		return err
+	}
+	if err := fn(data); err != nil {
+		return err
+	}
+	return d.saveData(data, snapshot)
+}
+
+// lock 获取进程内的互斥锁和词库目录中的锁文件
+// 只读操作在索引文件和预写日志都不存在时不创建锁文件
+func (d *DirSectionDAOImpl) lock(write bool) (func(), error) {
+	d.mutex.Lock()
+
+	if !write && !utils.FileExists(d.indexPath()) && !d.hasPendingJournal() {
+		return d.mutex.Unlock, nil
+	}
+
+	if err := os.MkdirAll(d.dir, 0755); err != nil {
+		d.mutex.Unlock()
+		return nil, fmt.Errorf("创建词库目录失败: %w", err)
+	}
+	release, err := acquireFileLock(d.lockPath(), lockTimeout)
+	if err != nil {
+		d.mutex.Unlock()
+		return nil, err
+	}
+	return func() {
+		release()
+		d.mutex.Unlock()
+	}, nil
+}
+
+// indexPath 索引文件路径
+func (d *DirSectionDAOImpl) indexPath() string {
+	return filepath.Join(d.dir, model.LibraryIndexFileName)
+}
+
+// loadData 按索引顺序加载全部章节，单词缺少的字段会被补全并写回
+// 加载前先检查并恢复上次未完成的写入，调用方需持有锁
+func (d *DirSectionDAOImpl) loadData() (*model.WordsFileDAO, *librarySnapshot, error) {
+	if err := d.recover(); err != nil {
+		return nil, nil, err
+	}
+
+	data, snapshot, migrated, err := d.readLibrary()
+	if err != nil {
+		return nil, nil, err
+	}
+	if migrated {
+		if err := d.saveData(data, snapshot); err != nil {
+			return nil, nil, fmt.Errorf("保存补全后的数据失败: %w", err)
+		}
+	}
+	return data, snapshot, nil
+}
+
+// readOnly 不加锁读取全部章节，不恢复未完成的写入，补全的字段也不写回
+func (d *DirSectionDAOImpl) readOnly() (*model.WordsFileDAO, error) {
+	if d.hasPendingJournal() {
+		return nil, errPendingJournal(d.dir)
+	}
+	data, _, _, err := d.readLibrary()
+	return data, err
+}
+
+// readLibrary 按索引顺序读取全部章节并补全单词缺少的字段，返回是否需要写回
+func (d *DirSectionDAOImpl) readLibrary() (*model.WordsFileDAO, *librarySnapshot, bool, error) {
+	data := &model.WordsFileDAO{
+		Version:  model.CurrentSchemaVersion,
+		Sections: make([]model.SectionDAO, 0),
+	}
+	snapshot := &librarySnapshot{
+		index: model.LibraryIndexDAO{Version: model.CurrentSchemaVersion},
+		files: make(map[string][]byte),
+	}
+
+	raw, err := os.ReadFile(d.indexPath())
+	if os.IsNotExist(err) {
+		// 新建的空词库
+		return data, snapshot, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("读取索引文件失败: %w", err)
+	}
+	if err := json.Unmarshal(raw, &snapshot.index); err != nil {
+		return nil, nil, false, fmt.Errorf("解析索引文件失败: %w", err)
+	}
+	if snapshot.index.Version > model.CurrentSchemaVersion {
+		return nil, nil, false, fmt.Errorf("词库版本 %d 高于程序支持的版本 %d，请升级程序", snapshot.index.Version, model.CurrentSchemaVersion)
+	}
+	snapshot.indexRaw = raw
+
+	now := time.Now()
+	migrated := false
+	names := make(map[string]bool)
+	for _, meta := range snapshot.index.Sections {
+		if names[meta.Name] {
+			return nil, nil, false, fmt.Errorf("索引文件中章节 '%s' 重复", meta.Name)
+		}
+		names[meta.Name] = true
+
+		section, content, err := d.readSection(meta)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		for i := range section.Words {
+			if normalizeWord(&section.Words[i], now) {
+				migrated = true
+			}
+		}
+		if normalizeSection(&section, now) {
+			migrated = true
+		}
+		snapshot.files[meta.File] = content
+		data.Sections = append(data.Sections, section)
+	}
+	return data, snapshot, migrated, nil
+}
+
+// readSection 读取索引中的一个章节文件，章节名称以索引为准
+func (d *DirSectionDAOImpl) readSection(meta model.LibrarySectionMeta) (model.SectionDAO, []byte, error) {
+	// 章节文件只能位于词库目录下，防止索引指向目录外的文件
+	if meta.File == "" || filepath.Base(meta.File) != meta.File || strings.HasPrefix(meta.File, ".") {
+		return model.SectionDAO{}, nil, fmt.Errorf("章节 '%s' 的文件名 '%s' 无效", meta.Name, meta.File)
+	}
+
+	content, err := os.ReadFile(filepath.Join(d.dir, meta.File))
+	if err != nil {
+		return model.SectionDAO{}, nil, fmt.Errorf("读取章节文件 %s 失败: %w", meta.File, err)
+	}
+
+	var file librarySection
+	if err := json.Unmarshal(content, &file); err != nil {
+		return model.SectionDAO{}, nil, fmt.Errorf("解析章节文件 %s 失败: %w", meta.File, err)
+	}
+	section := model.SectionDAO{
+		Name:      meta.Name,
+		CreatedAt: meta.CreatedAt,
+		Words:     file.Words,
+		Archived:  file.Archived,
+	}
+	if section.Words == nil {
+		section.Words = make([]model.WordEntity, 0)
+	}
+	return section, content, nil
+}
+
+// saveData 只写入发生变化的章节文件和索引，并删除已移除章节的文件，所有修改在同一次提交中完成
+// 重命名的章节会按新名称保存为新文件。调用方需持有锁，保存成功后更新snapshot
+func (d *DirSectionDAOImpl) saveData(data *model.WordsFileDAO, snapshot *librarySnapshot) error {
+	now := time.Now()
+	metas := make(map[string]model.LibrarySectionMeta, len(snapshot.index.Sections))
+	for _, meta := range snapshot.index.Sections {
+		metas[meta.Name] = meta
+	}
+
+	index := model.LibraryIndexDAO{
+		Version:  model.CurrentSchemaVersion,
+		Sections: make([]model.LibrarySectionMeta, 0, len(data.Sections)),
+	}
+	files := make(map[string][]byte, len(data.Sections))
+	taken := func(file string) bool {
+		_, inSnapshot := snapshot.files[file]
+		_, allocated := files[file]
+		return inSnapshot || allocated || file == model.LibraryIndexFileName ||
+			utils.FileExists(filepath.Join(d.dir, file))
+	}
+
+	var writes, deletes []fileChange
+	for _, section := range data.Sections {
+		meta, ok := metas[section.Name]
+		if !ok {
+			meta = model.LibrarySectionMeta{
+				Name: section.Name,
+				File: sectionFileName(section.Name, taken),
+			}
+		}
+		meta.CreatedAt = section.CreatedAt
+		if meta.CreatedAt.IsZero() {
+			meta.CreatedAt = now
+		}
+		index.Sections = append(index.Sections, meta)
+
+		content, err := marshalLibraryFile(librarySection{Name: section.Name, Words: section.Words, Archived: section.Archived})
+		if err != nil {
+			return err
+		}
+		files[meta.File] = content
+		if previous, ok := snapshot.files[meta.File]; !ok || !bytes.Equal(previous, content) {
+			writes = append(writes, fileChange{Path: meta.File, Data: content})
+		}
+	}
+
+	indexRaw, err := marshalLibraryFile(index)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(indexRaw, snapshot.indexRaw) {
+		writes = append(writes, fileChange{Path: model.LibraryIndexFileName, Data: indexRaw})
+	}
+
+	for file := range snapshot.files {
+		if _, ok := files[file]; !ok {
+			deletes = append(deletes, fileChange{Path: file, Delete: true})
+		}
+	}
+
+	// 先写章节文件再写索引，最后删除不再使用的文件，中途失败时由预写日志完成剩余的修改
+	if err := d.commit(append(writes, deletes...)); err != nil {
+		return err
+	}
+	snapshot.index = index
+	snapshot.indexRaw = indexRaw
+	snapshot.files = files
+	return nil
+}
+
+// marshalLibraryFile 序列化词库目录中的文件，末尾保留换行便于用git查看差异
+func marshalLibraryFile(v interface{}) ([]byte, error) {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("序列化JSON失败: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// sectionFileName 根据章节名称生成文件名：保留字母和数字（包括中文），其余字符替换为连字符，
+// 与已占用的文件名冲突时追加序号，如 "Day 5 - 2025.3.25" -> day-5-2025-3-25.json
+func sectionFileName(name string, taken func(file string) bool) string {
+	var slug []rune
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if len(slug) >= maxSectionFileSlug {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			slug = append(slug, r)
+			dash = false
+		} else if len(slug) > 0 && !dash {
+			slug = append(slug, '-')
+			dash = true
+		}
+	}
+	base := strings.TrimSuffix(string(slug), "-")
+	if base == "" {
+		base = "section"
+	}
+
+	file := base + ".json"
+	for i := 2; taken(file); i++ {
+		file = fmt.Sprintf("%s-%d.json", base, i)
+	}
+	return file
+}
@@ -0,0 +1,31 @@
+package dao
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// lockTimeout 等待其他进程释放锁文件的最长时间
+	lockTimeout = 10 * time.Second
+	// lockRetryInterval 获取锁文件失败后的重试间隔
+	lockRetryInterval = 20 * time.Millisecond
+)
+
+// acquireFileLock 获取锁文件上的排他锁，超时返回错误；返回的函数用于释放锁
+func acquireFileLock(path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		release, busy, err := tryLockFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("获取锁文件失败: %w", err)
+		}
+		if !busy {
+			return release, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("数据文件正被其他进程使用（锁文件 %s），请稍后重试", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,35 @@
+//go:build !unix
+
+package dao
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// staleLockAge 超过该时间的锁文件视为持有者已崩溃
+const staleLockAge = time.Minute
+
+// tryLockFile 以独占创建锁文件的方式获取锁，busy表示锁文件已被其他进程创建
+// 该方式无法在进程崩溃时自动释放，超过staleLockAge的锁文件会被视为失效并删除
+func tryLockFile(path string) (release func(), busy bool, err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, false, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		if !os.IsExist(err) {
+			return nil, false, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+		}
+		return nil, true, nil
+	}
+	file.Close()
+
+	return func() {
+		os.Remove(path)
+	}, false, nil
+}
@@ -0,0 +1,357 @@
+package dao
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// historyChange 修改历史中的一项变化，撤销时反向应用
+// 单词的变化记录变化前后的单词：添加时Before为空，删除时After为空；
+// 章节本身的变化（创建、删除、重命名、归档）记录在SectionBefore、SectionAfter中，此时没有单词
+type historyChange struct {
+	Section       string            `json:"section,omitempty"` // 单词所在的章节
+	Index         int               `json:"index"`             // 单词在章节中（或章节在词库中）的位置，撤销删除时放回原处
+	Before        *model.WordEntity `json:"before,omitempty"`
+	After         *model.WordEntity `json:"after,omitempty"`
+	SectionBefore *historySection   `json:"section_before,omitempty"`
+	SectionAfter  *historySection   `json:"section_after,omitempty"`
+}
+
+// historySection 章节本身的属性，不包含单词
+type historySection struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Archived  bool      `json:"archived,omitempty"`
+}
+
+// newHistorySection 记录章节本身的属性
+func newHistorySection(section *model.SectionDAO) *historySection {
+	return &historySection{Name: section.Name, CreatedAt: section.CreatedAt, Archived: section.Archived}
+}
+
+// wordChange 单词的变化，before为nil表示添加，after为nil表示删除；单词会被复制，之后修改原单词不影响记录
+func wordChange(section string, index int, before, after *model.WordEntity) historyChange {
+	change := historyChange{Section: section, Index: index}
+	if before != nil {
+		word := *before
+		change.Before = &word
+	}
+	if after != nil {
+		word := *after
+		change.After = &word
+	}
+	return change
+}
+
+// createChanges 创建章节的变化：先创建章节，再逐个添加单词
+func createChanges(index int, section *model.SectionDAO) []historyChange {
+	changes := make([]historyChange, 0, len(section.Words)+1)
+	changes = append(changes, historyChange{Index: index, SectionAfter: newHistorySection(section)})
+	for i := range section.Words {
+		changes = append(changes, wordChange(section.Name, i, nil, &section.Words[i]))
+	}
+	return changes
+}
+
+// deleteChanges 删除章节的变化：先从后往前删除单词，再删除章节，撤销时按相反的顺序恢复
+func deleteChanges(index int, section *model.SectionDAO) []historyChange {
+	changes := make([]historyChange, 0, len(section.Words)+1)
+	for i := len(section.Words) - 1; i >= 0; i-- {
+		changes = append(changes, wordChange(section.Name, i, &section.Words[i], nil))
+	}
+	return append(changes, historyChange{Index: index, SectionBefore: newHistorySection(section)})
+}
+
+// updateChanges UpdateSection的变化：章节属性的变化，以及按ID比较得到的删除、编辑和添加的单词
+// 只改变了复习状态或单词顺序时没有变化
+func updateChanges(old, updated *model.SectionDAO) []historyChange {
+	var changes []historyChange
+	if old.Name != updated.Name || old.Archived != updated.Archived {
+		changes = append(changes, historyChange{SectionBefore: newHistorySection(old), SectionAfter: newHistorySection(updated)})
+	}
+
+	kept := make(map[string]bool, len(updated.Words))
+	for i := range updated.Words {
+		kept[updated.Words[i].ID] = true
+	}
+	previous := make(map[string]*model.WordEntity, len(old.Words))
+	for i := len(old.Words) - 1; i >= 0; i-- {
+		word := &old.Words[i]
+		previous[word.ID] = word
+		if !kept[word.ID] {
+			changes = append(changes, wordChange(updated.Name, i, word, nil))
+		}
+	}
+	for i := range updated.Words {
+		word := &updated.Words[i]
+		if before, exists := previous[word.ID]; !exists {
+			changes = append(changes, wordChange(updated.Name, i, nil, word))
+		} else if !sameContent(before, word) {
+			changes = append(changes, wordChange(updated.Name, i, before, word))
+		}
+	}
+	return changes
+}
+
+// historyReverter 在数据上反向应用修改记录中的变化，同时记录反向应用的变化，撤销本身也可以再撤销
+// 要撤销的单词或章节在之后又被修改过时记录为冲突，有冲突时调用方不能保存数据
+type historyReverter struct {
+	data      *model.WordsFileDAO
+	owned     map[string]bool             // 已经复制过单词列表的章节，不修改与内存数据共享的切片
+	removed   map[string]model.WordEntity // 撤销时移除的单词，移回原章节时保留当前的复习状态
+	changes   []historyChange             // 反向应用的变化
+	conflicts []string
+	now       time.Time
+}
+
+// newHistoryReverter 创建反向应用变化的工具，data的章节列表会被复制，单词列表在修改前复制
+func newHistoryReverter(data *model.WordsFileDAO, now time.Time) *historyReverter {
+	data.Sections = append([]model.SectionDAO(nil), data.Sections...)
+	return &historyReverter{
+		data:    data,
+		owned:   make(map[string]bool),
+		removed: make(map[string]model.WordEntity),
+		now:     now,
+	}
+}
+
+// revert 从后往前反向应用一次修改的变化
+func (r *historyReverter) revert(changes []historyChange) {
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := &changes[i]
+		switch {
+		case change.SectionBefore != nil || change.SectionAfter != nil:
+			r.revertSection(change)
+		case change.Before == nil && change.After != nil:
+			r.revertAdd(change)
+		case change.Before != nil && change.After == nil:
+			r.revertRemove(change)
+		case change.Before != nil:
+			r.revertEdit(change)
+		}
+	}
+}
+
+// err 返回撤销时发现的冲突
+func (r *historyReverter) err() error {
+	if len(r.conflicts) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrHistoryConflict, strings.Join(r.conflicts, "；"))
+}
+
+// conflict 记录一个冲突
+func (r *historyReverter) conflict(format string, args ...interface{}) {
+	r.conflicts = append(r.conflicts, fmt.Sprintf(format, args...))
+}
+
+// words 返回可以修改的单词列表
+func (r *historyReverter) words(index int) []model.WordEntity {
+	section := &r.data.Sections[index]
+	if !r.owned[section.Name] {
+		section.Words = append(make([]model.WordEntity, 0, len(section.Words)+1), section.Words...)
+		r.owned[section.Name] = true
+	}
+	return section.Words
+}
+
+// locate 查找变化后的单词当前所在的位置，单词不在原章节或之后又被编辑过时记录冲突
+func (r *historyReverter) locate(change *historyChange) (int, int, bool) {
+	index := findSection(r.data, change.Section)
+	if index < 0 {
+		r.conflict("章节 %s 已不存在", change.Section)
+		return -1, -1, false
+	}
+	pos := findWordByID(r.data.Sections[index].Words, change.After)
+	if pos < 0 {
+		r.conflict("单词 %s 已不在章节 %s 中", change.After.W, change.Section)
+		return -1, -1, false
+	}
+	if !sameContent(&r.data.Sections[index].Words[pos], change.After) {
+		r.conflict("章节 %s 中的单词 %s 之后又被编辑过", change.Section, change.After.W)
+		return -1, -1, false
+	}
+	return index, pos, true
+}
+
+// revertAdd 撤销添加单词：删除这个单词
+func (r *historyReverter) revertAdd(change *historyChange) {
+	index, pos, ok := r.locate(change)
+	if !ok {
+		return
+	}
+	words := r.words(index)
+	current := words[pos]
+	r.data.Sections[index].Words = append(words[:pos], words[pos+1:]...)
+	r.removed[current.ID] = current
+	r.changes = append(r.changes, wordChange(change.Section, pos, &current, nil))
+}
+
+// revertRemove 撤销删除单词：放回原来的位置；单词是被移动走的话，保留移动后的复习状态
+func (r *historyReverter) revertRemove(change *historyChange) {
+	index := findSection(r.data, change.Section)
+	if index < 0 {
+		r.conflict("章节 %s 已不存在", change.Section)
+		return
+	}
+	if findWord(r.data.Sections[index].Words, change.Before.W) >= 0 {
+		r.conflict("章节 %s 中已经有单词 %s", change.Section, change.Before.W)
+		return
+	}
+
+	word := cloneWord(*change.Before)
+	if moved, ok := r.removed[word.ID]; ok {
+		word.Review, word.Mistake = moved.Review, moved.Mistake
+	}
+	words := r.words(index)
+	pos := clampIndex(change.Index, len(words))
+	words = append(words, model.WordEntity{})
+	copy(words[pos+1:], words[pos:])
+	words[pos] = word
+	r.data.Sections[index].Words = words
+	r.changes = append(r.changes, wordChange(change.Section, pos, nil, &word))
+}
+
+// revertEdit 撤销编辑单词：恢复编辑前的内容，复习状态和错题记录保持当前的值
+func (r *historyReverter) revertEdit(change *historyChange) {
+	index, pos, ok := r.locate(change)
+	if !ok {
+		return
+	}
+	current := r.data.Sections[index].Words[pos]
+	if change.Before.W != current.W && findWord(r.data.Sections[index].Words, change.Before.W) >= 0 {
+		r.conflict("章节 %s 中已经有单词 %s", change.Section, change.Before.W)
+		return
+	}
+
+	word := cloneWord(*change.Before)
+	word.Review, word.Mistake = current.Review, current.Mistake
+	word.UpdatedAt = r.now
+	r.words(index)[pos] = word
+	r.changes = append(r.changes, wordChange(change.Section, pos, &current, &word))
+}
+
+// revertSection 撤销章节的创建、删除、重命名或归档
+func (r *historyReverter) revertSection(change *historyChange) {
+	before, after := change.SectionBefore, change.SectionAfter
+	if before != nil && after == nil {
+		// 撤销删除：单词随后由删除单词的变化恢复
+		if findSection(r.data, before.Name) >= 0 {
+			r.conflict("已经有名为 %s 的章节", before.Name)
+			return
+		}
+		pos := clampIndex(change.Index, len(r.data.Sections))
+		r.data.Sections = append(r.data.Sections, model.SectionDAO{})
+		copy(r.data.Sections[pos+1:], r.data.Sections[pos:])
+		r.data.Sections[pos] = model.SectionDAO{
+			Name:      before.Name,
+			CreatedAt: before.CreatedAt,
+			Words:     make([]model.WordEntity, 0),
+			Archived:  before.Archived,
+		}
+		r.owned[before.Name] = true
+		r.changes = append(r.changes, historyChange{Index: pos, SectionAfter: newHistorySection(&r.data.Sections[pos])})
+		return
+	}
+
+	index := findSection(r.data, after.Name)
+	if index < 0 {
+		r.conflict("章节 %s 已不存在", after.Name)
+		return
+	}
+	section := &r.data.Sections[index]
+	current := newHistorySection(section)
+
+	if before == nil {
+		// 撤销创建：章节中的单词已经由添加单词的变化撤销，剩下的是之后添加的单词，
+		// 或者是前面已经报告了冲突、没能撤销的单词
+		if len(section.Words) > 0 {
+			if len(r.conflicts) == 0 {
+				r.conflict("章节 %s 中还有之后添加的 %d 个单词", after.Name, len(section.Words))
+			}
+			return
+		}
+		r.data.Sections = append(r.data.Sections[:index], r.data.Sections[index+1:]...)
+		delete(r.owned, after.Name)
+		r.changes = append(r.changes, historyChange{Index: index, SectionBefore: current})
+		return
+	}
+
+	if before.Name != after.Name && findSection(r.data, before.Name) >= 0 {
+		r.conflict("已经有名为 %s 的章节", before.Name)
+		return
+	}
+	if r.owned[after.Name] {
+		delete(r.owned, after.Name)
+		r.owned[before.Name] = true
+	}
+	section.Name, section.Archived = before.Name, before.Archived
+	r.changes = append(r.changes, historyChange{Index: index, SectionBefore: current, SectionAfter: newHistorySection(section)})
+}
+
+// findWordByID 按ID查找单词的下标，较早的数据没有ID时按拼写查找；不存在时返回-1
+func findWordByID(words []model.WordEntity, word *model.WordEntity) int {
+	if word.ID == "" {
+		return findWord(words, word.W)
+	}
+	for i := range words {
+		if words[i].ID == word.ID {
+			return i
+		}
+	}
+	return -1
+}
+
+// clampIndex 将插入位置限制在[0, length]之内
+func clampIndex(index, length int) int {
+	if index < 0 {
+		return 0
+	}
+	if index > length {
+		return length
+	}
+	return index
+}
+
+// revertOne 只撤销id对应的一次修改，返回这条修改记录和撤销时反向应用的变化
+// 之后的修改改动过同样的单词或章节时返回ErrHistoryConflict。调用方需持有数据锁，返回错误时不能保存data
+func (h *historyStore) revertOne(data *model.WordsFileDAO, id string) (*model.HistoryEntry, []historyChange, error) {
+	entry, changes, err := h.load(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	reverter := newHistoryReverter(data, h.now())
+	reverter.revert(changes)
+	return entry, reverter.changes, reverter.err()
+}
+
+// revertTo 从最近一条修改开始依次撤销，直到id对应的修改，返回这条修改记录和撤销时反向应用的全部变化
+// 调用方需持有数据锁，返回错误时data可能已被部分修改，不能保存
+func (h *historyStore) revertTo(data *model.WordsFileDAO, id string) (*model.HistoryEntry, []historyChange, error) {
+	entries, err := h.list()
+	if err != nil {
+		return nil, nil, err
+	}
+	target := -1
+	for i := range entries {
+		if entries[i].ID == id {
+			target = i
+		}
+	}
+	if target < 0 {
+		return nil, nil, errSnapshotNotFound(id)
+	}
+
+	reverter := newHistoryReverter(data, h.now())
+	for i := 0; i <= target; i++ {
+		_, changes, err := h.load(entries[i].ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		reverter.revert(changes)
+	}
+	return &entries[target], reverter.changes, reverter.err()
+}
@@ -0,0 +1,110 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/config"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// historyDirSuffix 修改历史目录相对数据文件的后缀，如 data/sections.json 的修改历史保存在 data/sections.history
+const historyDirSuffix = ".history"
+
+// ErrHistoryUnsupported 当前存储方式不保存修改历史
+var ErrHistoryUnsupported = errors.New("当前存储方式不支持修改历史")
+
+// ErrHistoryConflict 要撤销的单词或章节在这次修改之后又被修改过，撤销会覆盖之后的修改
+var ErrHistoryConflict = errors.New("修改之后又被修改过，无法撤销")
+
+// HistoryDAOInterface 修改历史DAO接口
+// 每次修改词库时记录这次修改改变了哪些章节和单词，可以单独撤销任意一次修改，也可以把词库恢复到任意一次修改之前的状态
+type HistoryDAOInterface interface {
+	// ListHistory 列出保存的修改记录，最近的修改在前
+	ListHistory(ctx context.Context) ([]model.HistoryEntry, error)
+
+	// RevertHistory 只撤销id对应的一次修改，之后的修改保持不变
+	// 这次修改涉及的单词或章节在之后又被修改过时返回ErrHistoryConflict，不做任何修改；撤销同样记录在修改历史中
+	RevertHistory(ctx context.Context, id string) (*model.HistoryEntry, error)
+
+	// RestoreHistory 从最近一次修改开始依次撤销，直到id对应的修改，将词库恢复到这次修改之前的状态
+	// 恢复本身也是一次修改，同样会记录在修改历史中；单词的复习进度和错题本保持当前状态
+	RestoreHistory(ctx context.Context, id string) (*model.HistoryEntry, error)
+}
+
+// historyBatchKey ctx中保存批量修改的key
+type historyBatchKey struct{}
+
+// historyBatch 合并为一条修改记录的一组修改
+type historyBatch struct {
+	action string
+	id     string // 已经写入的修改记录编号
+}
+
+// historyRecorder 在一次修改中保存修改记录，各存储方式共用
+type historyRecorder struct {
+	history *historyStore // 为nil时不保存修改记录
+	batch   *historyBatch
+	id      string // 本次修改写入的记录编号
+	size    int64  // 写入前记录文件的大小，为0表示本次新建了记录
+}
+
+// newHistoryRecorder 创建一次修改的记录器，ctx来自WithHistoryBatch时追加到这批修改的记录中
+func newHistoryRecorder(ctx context.Context, history *historyStore) *historyRecorder {
+	batch, _ := ctx.Value(historyBatchKey{}).(*historyBatch)
+	return &historyRecorder{history: history, batch: batch}
+}
+
+// record 在保存修改之前调用，action为修改的描述，changes为这次修改的变化
+// 描述为空表示只是复习进度之类的自动更新，不保存记录
+func (r *historyRecorder) record(action string, changes []historyChange) error {
+	if r.history == nil || action == "" || len(changes) == 0 {
+		return nil
+	}
+	if r.batch != nil && r.batch.id != "" {
+		size, err := r.history.append(r.batch.id, changes)
+		if err != nil {
+			return err
+		}
+		r.id, r.size = r.batch.id, size
+		return nil
+	}
+
+	if r.batch != nil {
+		action = r.batch.action
+	}
+	id, err := r.history.record(action, changes)
+	r.id = id
+	return err
+}
+
+// finish 在修改结束后调用：修改没有保存成功时撤回对应的记录
+func (r *historyRecorder) finish(err error) error {
+	if r.id == "" {
+		return err
+	}
+	if err != nil {
+		r.history.discard(r.id, r.size)
+		return err
+	}
+	if r.batch != nil {
+		r.batch.id = r.id
+	}
+	return nil
+}
+
+// WithHistoryBatch 使用返回的ctx进行的多次修改合并为一条修改记录，描述为action
+// 用于导入等一次写入大量单词的操作，撤销一次即可回到导入之前，也不会挤掉更早的记录；ctx不能在多个goroutine中同时使用
+func WithHistoryBatch(ctx context.Context, action string) context.Context {
+	return context.WithValue(ctx, historyBatchKey{}, &historyBatch{action: action})
+}
+
+// HistoryDirPath 根据数据文件路径得到修改历史目录的路径，词库目录的修改历史放在目录旁边
+func HistoryDirPath(dataFilePath string) string {
+	if config.IsLibraryDir(dataFilePath) {
+		return filepath.Clean(dataFilePath) + historyDirSuffix
+	}
+	return strings.TrimSuffix(dataFilePath, filepath.Ext(dataFilePath)) + historyDirSuffix
+}
@@ -0,0 +1,309 @@
+package dao
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+const (
+	// historyKeep 无论多久以前都保留的最近修改记录数
+	historyKeep = 50
+	// historyDays 超出historyKeep的修改记录，最近多少天内每天保留当天最后一条
+	historyDays = 30
+	// historyExt 修改记录文件的扩展名
+	historyExt = ".json.gz"
+	// historyIDLayout 修改记录编号的时间格式，按字符串排序即按时间排序
+	historyIDLayout = "20060102-150405.000000"
+)
+
+// historyExtraID gzip头部扩展字段中保存修改记录的子字段标识
+var historyExtraID = [2]byte{'E', 'L'}
+
+// historyWriters 复用gzip压缩器，每次新建压缩器需要分配和清零近1MB的内存，比压缩一次修改的变化还慢
+var historyWriters = sync.Pool{New: func() interface{} { return gzip.NewWriter(nil) }}
+
+// historyStore 修改历史的存储
+// 每条修改记录是一个gzip压缩的文件，内容为这次修改的变化（每行一个historyChange），修改的描述保存在gzip头部，
+// 列出历史时不需要解压内容；只记录变化的章节和单词，保存记录的开销与修改本身的大小成正比，与词库大小无关。
+// 记录文件写入后不再修改（批量修改只在末尾追加），不需要索引文件，列出历史时不需要持有数据锁
+type historyStore struct {
+	dir string
+	now func() time.Time // 当前时间，便于测试替换
+}
+
+// newHistoryStore 创建修改历史存储，dir不存在时在第一次保存记录时创建
+func newHistoryStore(dir string) *historyStore {
+	return &historyStore{dir: dir, now: time.Now}
+}
+
+// errSnapshotNotFound 修改记录不存在的错误
+func errSnapshotNotFound(id string) error {
+	return fmt.Errorf("修改记录 '%s' 不存在", id)
+}
+
+// path 修改记录文件的路径
+func (h *historyStore) path(id string) string {
+	return filepath.Join(h.dir, id+historyExt)
+}
+
+// record 保存一条修改记录并清理过期的记录，返回记录编号；调用方需持有数据锁
+// 记录文件直接创建并fsync，不经过临时文件：写入一半时崩溃留下的文件无法读取，列出历史时会被跳过
+func (h *historyStore) record(action string, changes []historyChange) (string, error) {
+	if err := os.MkdirAll(h.dir, 0755); err != nil {
+		return "", fmt.Errorf("创建修改历史目录失败: %w", err)
+	}
+
+	// 同一微秒内的多次修改顺延编号，保证编号唯一且有序
+	now := h.now()
+	for {
+		id := now.Format(historyIDLayout)
+		file, err := os.OpenFile(h.path(id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if os.IsExist(err) {
+			now = now.Add(time.Microsecond)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("保存修改记录失败: %w", err)
+		}
+
+		entry := model.HistoryEntry{ID: id, Time: now, Action: action}
+		content, err := encodeChanges(&entry, changes)
+		if err == nil {
+			_, err = file.Write(content)
+		}
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			h.discard(id, 0)
+			return "", fmt.Errorf("保存修改记录失败: %w", err)
+		}
+
+		if err := h.prune(); err != nil {
+			return id, err
+		}
+		return id, nil
+	}
+}
+
+// append 向已有的修改记录追加变化，用于合并为一条记录的批量修改，返回追加前的文件大小
+// 追加的内容是一个新的gzip成员，读取时与之前的内容连在一起
+func (h *historyStore) append(id string, changes []historyChange) (int64, error) {
+	content, err := encodeChanges(nil, changes)
+	if err != nil {
+		return 0, err
+	}
+	file, err := os.OpenFile(h.path(id), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("保存修改记录失败: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("保存修改记录失败: %w", err)
+	}
+	if _, err := file.Write(content); err == nil {
+		err = file.Sync()
+	}
+	if err != nil {
+		_ = file.Truncate(info.Size())
+		return 0, fmt.Errorf("保存修改记录失败: %w", err)
+	}
+	return info.Size(), nil
+}
+
+// discard 撤回修改没有保存成功时写入的记录：size为0时删除记录，否则截断为追加前的大小；目录为空时一并删除
+func (h *historyStore) discard(id string, size int64) {
+	if size > 0 {
+		_ = os.Truncate(h.path(id), size)
+		return
+	}
+	_ = os.Remove(h.path(id))
+	_ = os.Remove(h.dir)
+}
+
+// ids 列出所有修改记录的编号，最近的在前；只读取目录，不打开记录文件
+func (h *historyStore) ids() ([]string, error) {
+	files, err := os.ReadDir(h.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取修改历史目录失败: %w", err)
+	}
+
+	ids := make([]string, 0, len(files))
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, historyExt) {
+			continue
+		}
+		ids = append(ids, strings.TrimSuffix(name, historyExt))
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+	return ids, nil
+}
+
+// list 列出所有修改记录，最近的在前；无法读取的记录文件会被跳过
+func (h *historyStore) list() ([]model.HistoryEntry, error) {
+	ids, err := h.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]model.HistoryEntry, 0, len(ids))
+	for _, id := range ids {
+		entry, err := h.readEntry(id)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// readEntry 只读取记录文件的gzip头部，得到修改的描述
+func (h *historyStore) readEntry(id string) (*model.HistoryEntry, error) {
+	file, err := os.Open(h.path(id))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	return decodeEntry(id, reader.Header.Extra)
+}
+
+// load 读取修改记录和这次修改的全部变化
+func (h *historyStore) load(id string) (*model.HistoryEntry, []historyChange, error) {
+	if id == "" || filepath.Base(id) != id || strings.HasPrefix(id, ".") {
+		return nil, nil, errSnapshotNotFound(id)
+	}
+	file, err := os.Open(h.path(id))
+	if os.IsNotExist(err) {
+		return nil, nil, errSnapshotNotFound(id)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取修改记录失败: %w", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("修改记录 '%s' 已损坏: %w", id, err)
+	}
+	entry, err := decodeEntry(id, reader.Header.Extra)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []historyChange
+	decoder := json.NewDecoder(reader)
+	for {
+		var change historyChange
+		err := decoder.Decode(&change)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("修改记录 '%s' 已损坏: %w", id, err)
+		}
+		changes = append(changes, change)
+	}
+	return entry, changes, nil
+}
+
+// prune 清理过期的修改记录：保留最近historyKeep条，更早的记录在historyDays天内每天保留最后一条
+// 修改时间从编号中解析，每次修改都会调用，不需要打开记录文件
+func (h *historyStore) prune() error {
+	ids, err := h.ids()
+	if err != nil || len(ids) <= historyKeep {
+		return err
+	}
+
+	cutoff := h.now().AddDate(0, 0, -historyDays)
+	days := make(map[string]bool)
+	for i, id := range ids {
+		recorded, err := time.ParseInLocation(historyIDLayout, id, time.Local)
+		if err != nil {
+			// 不是程序写入的文件
+			continue
+		}
+		day := recorded.Format("2006-01-02")
+		if i < historyKeep || (recorded.After(cutoff) && !days[day]) {
+			days[day] = true
+			continue
+		}
+		if err := os.Remove(h.path(id)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("清理修改记录失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// encodeChanges 将变化压缩为一个gzip成员，每行一个变化；entry不为nil时写入gzip头部的扩展字段
+func encodeChanges(entry *model.HistoryEntry, changes []historyChange) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := historyWriters.Get().(*gzip.Writer)
+	defer historyWriters.Put(writer)
+	writer.Reset(&buf)
+	if entry != nil {
+		meta, err := json.Marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("序列化修改记录失败: %w", err)
+		}
+		// 扩展字段由子字段组成：2字节标识、2字节小端长度和内容
+		extra := make([]byte, 4, 4+len(meta))
+		copy(extra, historyExtraID[:])
+		binary.LittleEndian.PutUint16(extra[2:], uint16(len(meta)))
+		writer.Extra = append(extra, meta...)
+		writer.ModTime = entry.Time
+	}
+
+	encoder := json.NewEncoder(writer)
+	for i := range changes {
+		if err := encoder.Encode(&changes[i]); err != nil {
+			return nil, fmt.Errorf("序列化修改记录失败: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("压缩修改记录失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeEntry 从gzip头部的扩展字段中解析修改记录
+func decodeEntry(id string, extra []byte) (*model.HistoryEntry, error) {
+	for len(extra) >= 4 {
+		size := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if len(extra) < 4+size {
+			break
+		}
+		if extra[0] == historyExtraID[0] && extra[1] == historyExtraID[1] {
+			var entry model.HistoryEntry
+			if err := json.Unmarshal(extra[4:4+size], &entry); err != nil {
+				break
+			}
+			entry.ID = id
+			return &entry, nil
+		}
+		extra = extra[4+size:]
+	}
+	return nil, fmt.Errorf("修改记录 '%s' 缺少修改的描述", id)
+}
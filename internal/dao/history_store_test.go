@@ -0,0 +1,157 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+func TestHistoryPrune(t *testing.T) {
+	history := newHistoryStore(filepath.Join(t.TempDir(), "sections.history"))
+	changes := []historyChange{wordChange("day1", 0, nil, &model.WordEntity{ID: "1", W: "dam", C: "水坝"})}
+
+	// 60天前开始每天中午和晚上各修改一次，最后一天集中修改historyKeep次
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	now := start
+	history.now = func() time.Time { return now }
+	for day := 0; day < 60; day++ {
+		for _, hour := range []int{12, 20} {
+			now = start.AddDate(0, 0, day).Add(time.Duration(hour-12) * time.Hour)
+			if _, err := history.record(fmt.Sprintf("day %d %d:00", day, hour), changes); err != nil {
+				t.Fatalf("保存修改记录失败: %v", err)
+			}
+		}
+	}
+	last := start.AddDate(0, 0, 60)
+	for i := 0; i < historyKeep; i++ {
+		now = last.Add(time.Duration(i) * time.Minute)
+		if _, err := history.record(fmt.Sprintf("recent %d", i), changes); err != nil {
+			t.Fatalf("保存修改记录失败: %v", err)
+		}
+	}
+
+	entries, err := history.list()
+	if err != nil {
+		t.Fatalf("列出修改记录失败: %v", err)
+	}
+	// 最近historyKeep份全部保留，之前historyDays天内每天保留当天最后一份
+	if len(entries) != historyKeep+historyDays {
+		t.Fatalf("期望保留%d条修改记录，实际%d条", historyKeep+historyDays, len(entries))
+	}
+	if entries[0].Action != fmt.Sprintf("recent %d", historyKeep-1) {
+		t.Errorf("最新的修改记录不正确: %s", entries[0].Action)
+	}
+	if older := entries[historyKeep]; older.Action != "day 59 20:00" {
+		t.Errorf("超出最近记录数后应保留每天最后一条，实际为%s", older.Action)
+	}
+	if oldest := entries[len(entries)-1]; oldest.Time.Before(now.AddDate(0, 0, -historyDays)) {
+		t.Errorf("不应保留%d天之前的修改记录: %s", historyDays, oldest.Action)
+	}
+
+	entry, loaded, err := history.load(entries[0].ID)
+	if err != nil || entry.Action != entries[0].Action || len(loaded) != 1 || loaded[0].After.W != "dam" {
+		t.Errorf("读取修改记录失败: %+v, %v", entry, err)
+	}
+	if _, _, err := history.load("../sections"); err == nil {
+		t.Error("修改记录编号不能包含路径")
+	}
+}
+
+func TestHistoryReviewUpdate(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "sections.json")
+	sectionDAO := NewSectionDAO(filePath)
+	if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{
+		Name:  "unit1",
+		Words: []model.WordEntity{{W: "apple", C: "苹果"}},
+	}); err != nil {
+		t.Fatalf("创建章节失败: %v", err)
+	}
+
+	// 只更新复习进度，不应保存修改记录
+	section, err := sectionDAO.GetSection(ctx, "unit1")
+	if err != nil {
+		t.Fatalf("获取章节失败: %v", err)
+	}
+	section.Words[0].Review = &model.ReviewState{Repetitions: 1, Interval: 1}
+	if err := sectionDAO.UpdateSection(ctx, "unit1", section); err != nil {
+		t.Fatalf("更新复习进度失败: %v", err)
+	}
+	files, err := os.ReadDir(HistoryDirPath(filePath))
+	if err != nil {
+		t.Fatalf("读取修改历史目录失败: %v", err)
+	}
+	if len(files) != 1 {
+		t.Errorf("只更新复习进度时不应保存修改记录，目录中有%d个文件", len(files))
+	}
+
+	// 修改单词内容时保存修改记录
+	section.Words[0].C = "苹果；苹果树"
+	if err := sectionDAO.UpdateSection(ctx, "unit1", section); err != nil {
+		t.Fatalf("更新单词失败: %v", err)
+	}
+	entries, err := sectionDAO.(*SectionDAOImpl).ListHistory(ctx)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("期望2条修改记录，实际%d条: %v", len(entries), err)
+	}
+	if entries[0].Action != "修改章节 unit1 的单词（1 个 -> 1 个）" {
+		t.Errorf("修改记录的描述不正确: %s", entries[0].Action)
+	}
+}
+
+func TestRevertHistory(t *testing.T) {
+	ctx := context.Background()
+	filePath := filepath.Join(t.TempDir(), "sections.json")
+	sectionDAO := NewSectionDAO(filePath).(*SectionDAOImpl)
+	for _, name := range []string{"day1", "day2"} {
+		if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{
+			Name:  name,
+			Words: []model.WordEntity{{W: name + "-a", C: "甲"}, {W: name + "-b", C: "乙"}},
+		}); err != nil {
+			t.Fatalf("创建章节失败: %v", err)
+		}
+	}
+
+	// 移动单词后在新章节中复习，再重命名新章节
+	if err := sectionDAO.MoveWord(ctx, "day1", "day2", "day1-a"); err != nil {
+		t.Fatalf("移动单词失败: %v", err)
+	}
+	section, _ := sectionDAO.GetSection(ctx, "day2")
+	section.Words[2].Review = &model.ReviewState{Reviews: 2}
+	section.Name = "unit2"
+	if err := sectionDAO.UpdateSection(ctx, "day2", section); err != nil {
+		t.Fatalf("更新章节失败: %v", err)
+	}
+
+	entries, _ := sectionDAO.ListHistory(ctx)
+	if len(entries) != 4 || entries[1].Action != moveWordAction("day1-a", "day1", "day2") {
+		t.Fatalf("修改历史不正确: %+v", entries)
+	}
+
+	// 单独撤销移动：重命名之后单词所在的章节名称变了，报告冲突
+	if _, err := sectionDAO.RevertHistory(ctx, entries[1].ID); !errors.Is(err, ErrHistoryConflict) {
+		t.Fatalf("章节被重命名后撤销移动应报告冲突，实际为%v", err)
+	}
+
+	// 先撤销重命名，再撤销移动：单词回到原位置，保留移动后的复习进度
+	if _, err := sectionDAO.RevertHistory(ctx, entries[0].ID); err != nil {
+		t.Fatalf("撤销重命名失败: %v", err)
+	}
+	if _, err := sectionDAO.RevertHistory(ctx, entries[1].ID); err != nil {
+		t.Fatalf("撤销移动失败: %v", err)
+	}
+	day1, _ := sectionDAO.GetSection(ctx, "day1")
+	day2, err := sectionDAO.GetSection(ctx, "day2")
+	if err != nil || len(day2.Words) != 2 {
+		t.Fatalf("撤销后章节day2不正确: %+v, %v", day2, err)
+	}
+	if len(day1.Words) != 2 || day1.Words[0].W != "day1-a" || day1.Words[0].Review == nil || day1.Words[0].Review.Reviews != 2 {
+		t.Errorf("撤销移动后单词应回到原位置并保留复习进度: %+v", day1.Words)
+	}
+}
@@ -0,0 +1,254 @@
+package dao
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// journalMagic 预写日志的文件头标识
+const journalMagic = "englishLearn-journal"
+
+// writeAll 将数据完整写入w，测试时可替换为中途失败的实现
+func writeAll(w io.Writer, data []byte) error {
+	_, err := w.Write(data)
+	return err
+}
+
+// fileChange 一次提交中对单个文件的修改
+type fileChange struct {
+	Path   string `json:"path"`             // 相对于存储根目录的路径
+	Data   []byte `json:"data,omitempty"`   // 新内容
+	Delete bool   `json:"delete,omitempty"` // 是否删除该文件
+}
+
+// fileStore 崩溃安全的文件存储，负责锁文件、预写日志和原子替换，单文件和目录两种存储共用
+type fileStore struct {
+	root     string                               // 存储根目录
+	name     string                               // 锁文件和预写日志的文件名前缀
+	tempGlob string                               // 本存储创建的临时文件的匹配模式，用于清理崩溃后残留的临时文件
+	write    func(w io.Writer, data []byte) error // 写入数据，便于测试模拟写入中途失败
+}
+
+// newFileStore 创建文件存储，锁文件和预写日志为root下的<name>.lock和<name>.journal
+func newFileStore(root, name, tempGlob string) *fileStore {
+	return &fileStore{
+		root:     root,
+		name:     name,
+		tempGlob: tempGlob,
+		write:    writeAll,
+	}
+}
+
+// journalPath 预写日志文件路径
+func (fs *fileStore) journalPath() string {
+	return filepath.Join(fs.root, fs.name+".journal")
+}
+
+// errPendingJournal 只读方式读取时发现未完成的写入的错误
+func errPendingJournal(path string) error {
+	return fmt.Errorf("%s 有未完成的写入，请先用程序打开一次完成恢复", path)
+}
+
+// lockPath 锁文件路径
+func (fs *fileStore) lockPath() string {
+	return filepath.Join(fs.root, fs.name+".lock")
+}
+
+// commit 以崩溃安全的方式提交一组文件修改：
+// 1. 先把全部修改连同校验和写入预写日志并fsync
+// 2. 依次写入临时文件、fsync后重命名覆盖目标文件，或删除文件
+// 3. 最后删除预写日志
+// 任何一步中途崩溃，残留的完整日志会在下次加载时重放，不完整的日志会被丢弃
+// 调用方需持有锁
+func (fs *fileStore) commit(changes []fileChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	journal := fs.journalPath()
+	if err := fs.writeJournal(journal, changes); err != nil {
+		os.Remove(journal)
+		return fmt.Errorf("写入预写日志失败: %w", err)
+	}
+
+	for i, change := range changes {
+		if err := fs.apply(change); err != nil {
+			if i == 0 {
+				// 文件未被修改，本次修改已向调用方报错，不应在下次加载时重放
+				os.Remove(journal)
+				return err
+			}
+			// 部分文件已经修改，保留日志，下次加载时完成剩余的修改
+			return fmt.Errorf("%w（已保留预写日志，下次加载时将完成剩余的写入）", err)
+		}
+	}
+
+	if err := os.Remove(journal); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除预写日志失败: %w", err)
+	}
+	return nil
+}
+
+// apply 执行单个文件修改
+func (fs *fileStore) apply(change fileChange) error {
+	path := filepath.Join(fs.root, change.Path)
+	if change.Delete {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除文件失败: %w", err)
+		}
+		return nil
+	}
+	return fs.writeAtomic(path, change.Data)
+}
+
+// writeJournal 写入预写日志，格式为一行文件头（标识、SHA-256校验和、长度）加JSON编码的修改列表
+func (fs *fileStore) writeJournal(path string, changes []fileChange) error {
+	payload, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	sum := sha256.Sum256(payload)
+	header := fmt.Sprintf("%s %s %d\n", journalMagic, hex.EncodeToString(sum[:]), len(payload))
+	if err := fs.write(file, append([]byte(header), payload...)); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// parseJournal 解析预写日志，日志不完整或校验失败时返回false
+func parseJournal(raw []byte) ([]fileChange, bool) {
+	newline := bytes.IndexByte(raw, '\n')
+	if newline < 0 {
+		return nil, false
+	}
+
+	fields := bytes.Fields(raw[:newline])
+	if len(fields) != 3 || string(fields[0]) != journalMagic {
+		return nil, false
+	}
+	size, err := strconv.Atoi(string(fields[2]))
+	if err != nil {
+		return nil, false
+	}
+
+	payload := raw[newline+1:]
+	if len(payload) != size {
+		return nil, false
+	}
+	sum := sha256.Sum256(payload)
+	if hex.EncodeToString(sum[:]) != string(fields[1]) {
+		return nil, false
+	}
+
+	var changes []fileChange
+	if err := json.Unmarshal(payload, &changes); err != nil {
+		return nil, false
+	}
+	return changes, true
+}
+
+// writeAtomic 先写入同目录下的临时文件并fsync，再重命名覆盖目标文件
+func (fs *fileStore) writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpPath := tmp.Name()
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmpPath)
+	}
+
+	if err := fs.write(tmp, data); err != nil {
+		cleanup()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		cleanup()
+		return fmt.Errorf("同步文件失败: %w", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		cleanup()
+		return fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("替换数据文件失败: %w", err)
+	}
+	return syncDir(dir)
+}
+
+// recover 检查上次是否有未完成的写入：重放完整的预写日志，丢弃不完整的日志并清理残留的临时文件
+// 调用方需持有锁
+func (fs *fileStore) recover() error {
+	journal := fs.journalPath()
+	raw, err := os.ReadFile(journal)
+	switch {
+	case os.IsNotExist(err):
+		// 没有未完成的写入
+	case err != nil:
+		return fmt.Errorf("读取预写日志失败: %w", err)
+	default:
+		if changes, ok := parseJournal(raw); ok {
+			// 日志完整，说明崩溃发生在修改文件的过程中，重放本次提交的全部修改
+			for _, change := range changes {
+				if err := fs.apply(change); err != nil {
+					return fmt.Errorf("重放预写日志失败: %w", err)
+				}
+			}
+		}
+		// 日志不完整说明崩溃发生在修改文件之前，文件仍为旧内容
+		if err := os.Remove(journal); err != nil {
+			return fmt.Errorf("删除预写日志失败: %w", err)
+		}
+	}
+
+	leftovers, _ := filepath.Glob(filepath.Join(fs.root, fs.tempGlob))
+	for _, leftover := range leftovers {
+		os.Remove(leftover)
+	}
+	return nil
+}
+
+// hasPendingJournal 判断是否有未处理的预写日志
+func (fs *fileStore) hasPendingJournal() bool {
+	_, err := os.Stat(fs.journalPath())
+	return err == nil
+}
+
+// syncDir 同步目录，确保重命名和新建文件落盘；部分平台不支持对目录fsync，忽略该错误
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return nil
+	}
+	defer d.Close()
+	d.Sync()
+	return nil
+}
@@ -0,0 +1,1010 @@
+package dao
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
+)
+
+// 日志记录的操作类型
+const (
+	logOpCreate     = "create"      // 创建章节，包含全部单词
+	logOpUpdate     = "update"      // 更新（或重命名）章节，包含全部单词
+	logOpDelete     = "delete"      // 删除章节
+	logOpAddWord    = "add_word"    // 向章节添加一个单词
+	logOpRemoveWord = "remove_word" // 从章节移除单词
+	logOpUpdateWord = "update_word" // 替换章节中的一个单词
+	logOpMoveWord   = "move_word"   // 将单词移动到另一个章节
+)
+
+// compactMinBytes 两次压缩之间日志至少增长的字节数，日志同时还需超过上次压缩后的大小才会压缩
+var compactMinBytes int64 = 4 << 20
+
+// logHeader 日志文件的第一行
+type logHeader struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Generation string `json:"generation"` // 每次压缩后重新生成，其他进程据此发现文件已被替换
+}
+
+// logRecord 日志中的一条修改记录，回放全部记录即可得到当前数据
+type logRecord struct {
+	Op        string             `json:"op"`
+	Section   string             `json:"section"`
+	NewName   string             `json:"new_name,omitempty"`
+	Words     []model.WordEntity `json:"words,omitempty"`
+	Word      *model.WordEntity  `json:"word,omitempty"`
+	W         string             `json:"w,omitempty"`
+	Target    string             `json:"target,omitempty"`     // 移动单词的目标章节
+	Archived  bool               `json:"archived,omitempty"`   // 创建或更新后章节是否已归档
+	CreatedAt *time.Time         `json:"created_at,omitempty"` // 创建章节的时间
+}
+
+// logSection 内存中的章节
+type logSection struct {
+	name      string
+	words     []model.WordEntity
+	createdAt time.Time
+	archived  bool
+	counts    map[string]int // 单词 -> 出现次数，用于快速判断单词是否存在
+	revision  string         // 缓存的版本标识，内容变化时清空
+	texts     []searchText   // 缓存的查询文本，与words一一对应，首次查询时计算
+}
+
+// LogSectionDAOImpl 日志存储的章节DAO实现
+// 数据常驻内存并建立索引，每次修改只向文件末尾追加一行记录，不再重新读写整个文件；
+// 日志增长到一定大小后压缩为每个章节一条记录。每次操作前只读取其他进程新追加的记录
+type LogSectionDAOImpl struct {
+	*fileStore
+	filePath string
+	mutex    sync.Mutex
+	history  *historyStore // 保存修改历史
+
+	sections      []*logSection
+	byName        map[string]*logSection
+	loaded        bool
+	generation    string // 已加载的日志代数
+	offset        int64  // 已回放到的文件偏移
+	compactedSize int64  // 上次压缩或完整加载后的文件大小
+}
+
+// NewLogSectionDAO 创建日志存储的SectionDAO实例
+func NewLogSectionDAO(filePath string) SectionDAOInterface {
+	base := filepath.Base(filePath)
+	return &LogSectionDAOImpl{
+		fileStore: newFileStore(filepath.Dir(filePath), base, "."+base+".tmp-*"),
+		filePath:  filePath,
+		history:   newHistoryStore(HistoryDirPath(filePath)),
+	}
+}
+
+// view 在锁内同步其他进程的修改后执行只读操作
+func (d *LogSectionDAOImpl) view(fn func() error) error {
+	unlock, err := d.lock(false)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := d.refresh(); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// update 在锁内同步其他进程的修改后执行修改，fn通过commit追加记录；日志过大时顺带压缩
+func (d *LogSectionDAOImpl) update(fn func() error) error {
+	unlock, err := d.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := d.refresh(); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if grown := d.offset - d.compactedSize; grown > compactMinBytes && grown > d.compactedSize {
+		// 修改已经写入日志，压缩失败不影响本次操作，下次修改时会再次尝试
+		d.writeSnapshot()
+	}
+	return nil
+}
+
+// mutate 在update的基础上保存修改记录，fn在调用commit之前调用record，用法与sectionOps.mutate相同
+// 修改记录只包含commit追加的记录涉及的章节和单词，不会序列化整个词库
+func (d *LogSectionDAOImpl) mutate(ctx context.Context, fn func(record func(action string, changes []historyChange) error) error) error {
+	recorder := newHistoryRecorder(ctx, d.history)
+	return recorder.finish(d.update(func() error {
+		return fn(recorder.record)
+	}))
+}
+
+// lock 获取进程内的互斥锁和数据文件旁的锁文件
+// 只读操作在数据文件不存在时不创建锁文件
+func (d *LogSectionDAOImpl) lock(write bool) (func(), error) {
+	d.mutex.Lock()
+
+	if !write && !utils.FileExists(d.filePath) {
+		return d.mutex.Unlock, nil
+	}
+
+	release, err := acquireFileLock(d.lockPath(), lockTimeout)
+	if err != nil {
+		d.mutex.Unlock()
+		return nil, err
+	}
+	return func() {
+		release()
+		d.mutex.Unlock()
+	}, nil
+}
+
+// reset 清空内存中的数据
+func (d *LogSectionDAOImpl) reset(generation string) {
+	d.sections = nil
+	d.byName = make(map[string]*logSection)
+	d.loaded = true
+	d.generation = generation
+	d.offset = 0
+	d.compactedSize = 0
+}
+
+// refresh 使内存中的数据与文件一致：文件被压缩替换时完整加载，否则只回放新追加的记录
+// 末尾不完整的记录说明上次追加时崩溃，会被截断。调用方需持有锁
+func (d *LogSectionDAOImpl) refresh() error {
+	file, err := os.Open(d.filePath)
+	if os.IsNotExist(err) {
+		d.reset("")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("打开数据文件失败: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("读取文件信息失败: %w", err)
+	}
+	if info.Size() == 0 {
+		d.reset("")
+		return nil
+	}
+
+	reader := bufio.NewReader(file)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	header, err := decodeLogHeader(line)
+	if err != nil {
+		return err
+	}
+
+	full := !d.loaded || header.Generation != d.generation || info.Size() < d.offset
+	if full {
+		if err := d.recover(); err != nil {
+			return err
+		}
+		d.reset(header.Generation)
+		d.offset = int64(len(line))
+	}
+
+	if info.Size() > d.offset {
+		if _, err := file.Seek(d.offset, io.SeekStart); err != nil {
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		if err := d.replay(bufio.NewReader(file), true); err != nil {
+			return err
+		}
+	}
+
+	if full {
+		d.compactedSize = d.offset
+	}
+	return nil
+}
+
+// readOnly 不加锁读取全部数据，不截断不完整的最后一行。返回的数据归调用方所有
+func (d *LogSectionDAOImpl) readOnly() (*model.WordsFileDAO, error) {
+	if d.hasPendingJournal() {
+		return nil, errPendingJournal(d.filePath)
+	}
+	d.reset("")
+	file, err := os.Open(d.filePath)
+	if os.IsNotExist(err) {
+		return d.data(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开数据文件失败: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	line, err := reader.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	if len(line) > 0 {
+		if _, err := decodeLogHeader(line); err != nil {
+			return nil, err
+		}
+		d.offset = int64(len(line))
+		if err := d.replay(reader, false); err != nil {
+			return nil, err
+		}
+	}
+	return d.data(), nil
+}
+
+// replay 回放reader中的记录，repair为false时忽略不完整的最后一行，不截断文件
+func (d *LogSectionDAOImpl) replay(reader *bufio.Reader, repair bool) error {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("读取文件失败: %w", err)
+		}
+		if len(line) == 0 {
+			return nil
+		}
+
+		payload, ok := decodeLogLine(line)
+		if !ok {
+			if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+				// 最后一行不完整，说明追加时崩溃，该修改未向调用方确认成功，截断即可
+				if !repair {
+					return nil
+				}
+				if err := os.Truncate(d.filePath, d.offset); err != nil {
+					return fmt.Errorf("截断不完整的日志记录失败: %w", err)
+				}
+				return nil
+			}
+			return fmt.Errorf("数据文件在偏移 %d 处损坏", d.offset)
+		}
+
+		var record logRecord
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return fmt.Errorf("解析日志记录失败（偏移 %d）: %w", d.offset, err)
+		}
+		if err := d.apply(&record); err != nil {
+			return fmt.Errorf("回放日志记录失败（偏移 %d）: %w", d.offset, err)
+		}
+		d.offset += int64(len(line))
+	}
+}
+
+// apply 将一条记录应用到内存中的数据，记录的单词归内存数据所有
+func (d *LogSectionDAOImpl) apply(record *logRecord) error {
+	words := record.Words
+	if words == nil {
+		words = make([]model.WordEntity, 0)
+	}
+
+	switch record.Op {
+	case logOpCreate:
+		if d.byName[record.Section] != nil {
+			return errSectionExists(record.Section)
+		}
+		section := &logSection{name: record.Section, archived: record.Archived}
+		if record.CreatedAt != nil {
+			section.createdAt = *record.CreatedAt
+		} else {
+			// 较早的日志没有记录创建时间
+			section.createdAt = earliestCreated(words, time.Now())
+		}
+		section.setWords(words)
+		d.sections = append(d.sections, section)
+		d.byName[section.name] = section
+		return nil
+	}
+
+	section := d.byName[record.Section]
+	if section == nil {
+		return errSectionNotFound(record.Section)
+	}
+
+	switch record.Op {
+	case logOpUpdate:
+		if record.NewName != section.name {
+			if d.byName[record.NewName] != nil {
+				return errSectionExists(record.NewName)
+			}
+			delete(d.byName, section.name)
+			section.name = record.NewName
+			d.byName[section.name] = section
+		}
+		section.archived = record.Archived
+		section.setWords(words)
+	case logOpDelete:
+		for i, existing := range d.sections {
+			if existing == section {
+				d.sections = append(d.sections[:i], d.sections[i+1:]...)
+				break
+			}
+		}
+		delete(d.byName, section.name)
+	case logOpAddWord:
+		if record.Word == nil {
+			return fmt.Errorf("添加单词的记录缺少单词")
+		}
+		section.words = append(section.words, *record.Word)
+		section.counts[record.Word.W]++
+		section.revision = ""
+		if section.texts != nil {
+			section.texts = append(section.texts, newSearchText(record.Word))
+		}
+	case logOpRemoveWord:
+		remaining := make([]model.WordEntity, 0, len(section.words))
+		for _, word := range section.words {
+			if word.W != record.W {
+				remaining = append(remaining, word)
+			}
+		}
+		section.setWords(remaining)
+	case logOpUpdateWord:
+		pos := findWord(section.words, record.W)
+		if pos < 0 || record.Word == nil {
+			return fmt.Errorf("更新单词的记录无效: 章节 '%s' 中没有单词 '%s'", section.name, record.W)
+		}
+		words := append([]model.WordEntity(nil), section.words...)
+		words[pos] = *record.Word
+		section.setWords(words)
+	case logOpMoveWord:
+		target := d.byName[record.Target]
+		if target == nil {
+			return errSectionNotFound(record.Target)
+		}
+		pos := findWord(section.words, record.W)
+		if pos < 0 {
+			return errWordNotFound(record.W, section.name)
+		}
+		word := section.words[pos]
+		remaining := append(section.words[:pos:pos], section.words[pos+1:]...)
+		section.setWords(remaining)
+		target.setWords(append(target.words, word))
+	default:
+		return fmt.Errorf("未知的日志操作 '%s'", record.Op)
+	}
+	return nil
+}
+
+// setWords 替换章节的单词并重建索引
+func (s *logSection) setWords(words []model.WordEntity) {
+	s.words = words
+	s.counts = make(map[string]int, len(words))
+	for _, word := range words {
+		s.counts[word.W]++
+	}
+	s.revision = ""
+	s.texts = nil
+}
+
+// searchTexts 返回每个单词的查询文本，计算结果会被缓存
+func (s *logSection) searchTexts() []searchText {
+	if s.texts == nil {
+		s.texts = make([]searchText, len(s.words))
+		for i := range s.words {
+			s.texts[i] = newSearchText(&s.words[i])
+		}
+	}
+	return s.texts
+}
+
+// currentRevision 返回章节内容的版本标识，计算结果会被缓存
+func (s *logSection) currentRevision() string {
+	if s.revision == "" {
+		s.revision = sectionRevision(s.words)
+	}
+	return s.revision
+}
+
+// entity 返回章节实体，单词为副本，调用方修改不会影响内存中的数据
+func (s *logSection) entity() model.SectionEntity {
+	return model.SectionEntity{
+		Name:      s.name,
+		Words:     cloneWords(s.words),
+		CreatedAt: s.createdAt,
+		Archived:  s.archived,
+		Revision:  s.currentRevision(),
+	}
+}
+
+// dao 返回章节的存储结构，单词与内存中的数据共享，调用方不能修改
+func (s *logSection) dao() model.SectionDAO {
+	return model.SectionDAO{
+		Name:      s.name,
+		CreatedAt: s.createdAt,
+		Words:     s.words,
+		Archived:  s.archived,
+	}
+}
+
+// data 将内存中的数据组装为单文件的数据结构，用于撤销修改。调用方需持有锁，且不能修改返回的单词列表
+func (d *LogSectionDAOImpl) data() *model.WordsFileDAO {
+	data := &model.WordsFileDAO{
+		Version:  model.CurrentSchemaVersion,
+		Sections: make([]model.SectionDAO, 0, len(d.sections)),
+	}
+	for _, section := range d.sections {
+		data.Sections = append(data.Sections, section.dao())
+	}
+	return data
+}
+
+// sectionIndex 返回章节在词库中的位置
+func (d *LogSectionDAOImpl) sectionIndex(section *logSection) int {
+	for i, existing := range d.sections {
+		if existing == section {
+			return i
+		}
+	}
+	return -1
+}
+
+// replace 用data替换内存中的全部数据并重写日志，数据归内存所有。调用方需持有锁
+func (d *LogSectionDAOImpl) replace(data *model.WordsFileDAO) error {
+	sections, byName := d.sections, d.byName
+	d.sections = nil
+	d.byName = make(map[string]*logSection)
+	var err error
+	for i := 0; i < len(data.Sections) && err == nil; i++ {
+		section := &data.Sections[i]
+		err = d.apply(&logRecord{
+			Op:        logOpCreate,
+			Section:   section.Name,
+			Words:     section.Words,
+			Archived:  section.Archived,
+			CreatedAt: &section.CreatedAt,
+		})
+	}
+	if err == nil {
+		err = d.writeSnapshot()
+	}
+	if err != nil {
+		// 写入失败时内存中的数据保持不变
+		d.sections, d.byName = sections, byName
+	}
+	return err
+}
+
+// commit 追加一条记录并fsync，成功后应用到内存中的数据。调用方需持有锁
+func (d *LogSectionDAOImpl) commit(record *logRecord) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("序列化JSON失败: %w", err)
+	}
+
+	if d.offset == 0 {
+		// 文件不存在或为空，先写入只有文件头的日志
+		if err := d.writeSnapshot(); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(d.filePath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("打开数据文件失败: %w", err)
+	}
+	line := encodeLogLine(payload)
+	err = d.write(file, line)
+	if err == nil {
+		err = file.Sync()
+	}
+	file.Close()
+	if err != nil {
+		// 去掉写入了一半的记录，失败的修改不会在下次加载时被回放
+		os.Truncate(d.filePath, d.offset)
+		return fmt.Errorf("写入日志失败: %w", err)
+	}
+
+	d.offset += int64(len(line))
+	return d.apply(record)
+}
+
+// Compact 压缩日志，每个章节只保留一条记录。修改时会按需自动压缩，一般不需要手动调用
+func (d *LogSectionDAOImpl) Compact() error {
+	unlock, err := d.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := d.refresh(); err != nil {
+		return err
+	}
+	return d.writeSnapshot()
+}
+
+// writeSnapshot 将内存中的数据写成新的日志（文件头加每个章节一条创建记录），以新的代数原子替换数据文件
+// 调用方需持有锁
+func (d *LogSectionDAOImpl) writeSnapshot() error {
+	generation := newWordID()
+	header, err := json.Marshal(logHeader{
+		Format:     model.LogStoreFormat,
+		Version:    model.CurrentSchemaVersion,
+		Generation: generation,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化JSON失败: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(encodeLogLine(header))
+	for _, section := range d.sections {
+		payload, err := json.Marshal(logRecord{
+			Op:        logOpCreate,
+			Section:   section.name,
+			Words:     section.words,
+			Archived:  section.archived,
+			CreatedAt: &section.createdAt,
+		})
+		if err != nil {
+			return fmt.Errorf("序列化JSON失败: %w", err)
+		}
+		buf.Write(encodeLogLine(payload))
+	}
+
+	if err := d.writeAtomic(d.filePath, buf.Bytes()); err != nil {
+		return err
+	}
+	d.generation = generation
+	d.offset = int64(buf.Len())
+	d.compactedSize = d.offset
+	return nil
+}
+
+// encodeLogLine 编码一行日志：CRC32校验和、空格、JSON内容、换行
+func encodeLogLine(payload []byte) []byte {
+	line := make([]byte, 0, len(payload)+10)
+	line = append(line, fmt.Sprintf("%08x ", crc32.ChecksumIEEE(payload))...)
+	line = append(line, payload...)
+	return append(line, '\n')
+}
+
+// decodeLogLine 解码一行日志，行不完整或校验失败时返回false
+func decodeLogLine(line []byte) ([]byte, bool) {
+	if len(line) < 10 || line[len(line)-1] != '\n' || line[8] != ' ' {
+		return nil, false
+	}
+	sum, err := strconv.ParseUint(string(line[:8]), 16, 32)
+	if err != nil {
+		return nil, false
+	}
+	payload := line[9 : len(line)-1]
+	if crc32.ChecksumIEEE(payload) != uint32(sum) {
+		return nil, false
+	}
+	return payload, true
+}
+
+// decodeLogHeader 解析日志文件头
+func decodeLogHeader(line []byte) (*logHeader, error) {
+	payload, ok := decodeLogLine(line)
+	if !ok {
+		return nil, fmt.Errorf("数据文件头损坏，不是有效的%s文件", model.LogStoreExt)
+	}
+	var header logHeader
+	if err := json.Unmarshal(payload, &header); err != nil || header.Format != model.LogStoreFormat {
+		return nil, fmt.Errorf("数据文件头损坏，不是有效的%s文件", model.LogStoreExt)
+	}
+	if header.Version > model.CurrentSchemaVersion {
+		return nil, fmt.Errorf("数据文件版本 %d 高于程序支持的版本 %d，请升级程序", header.Version, model.CurrentSchemaVersion)
+	}
+	return &header, nil
+}
+
+// cloneWords 深拷贝单词列表，避免调用方与内存中的数据共享切片和指针
+func cloneWords(words []model.WordEntity) []model.WordEntity {
+	if words == nil {
+		return make([]model.WordEntity, 0)
+	}
+	cloned := make([]model.WordEntity, len(words))
+	for i, word := range words {
+		cloned[i] = cloneWord(word)
+	}
+	return cloned
+}
+
+// cloneWord 深拷贝单个单词
+func cloneWord(word model.WordEntity) model.WordEntity {
+	if word.Senses != nil {
+		senses := make([]model.Sense, len(word.Senses))
+		for i, sense := range word.Senses {
+			sense.Examples = append([]string(nil), sense.Examples...)
+			senses[i] = sense
+		}
+		word.Senses = senses
+	}
+	if word.Tags != nil {
+		word.Tags = append([]string(nil), word.Tags...)
+	}
+	if word.Review != nil {
+		review := *word.Review
+		word.Review = &review
+	}
+	if word.Mistake != nil {
+		mistake := *word.Mistake
+		word.Mistake = &mistake
+	}
+	return word
+}
+
+// CreateSection 创建章节
+func (d *LogSectionDAOImpl) CreateSection(ctx context.Context, section *model.SectionEntity) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		if d.byName[section.Name] != nil {
+			return errSectionExists(section.Name)
+		}
+
+		now := time.Now()
+		words := cloneWords(section.Words)
+		stampWords(nil, words, now)
+		createdAt := sectionCreatedAt(section, now)
+		created := model.SectionDAO{Name: section.Name, CreatedAt: createdAt, Words: words, Archived: section.Archived}
+		if err := record(createAction(section.Name), createChanges(len(d.sections), &created)); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{
+			Op:        logOpCreate,
+			Section:   section.Name,
+			Words:     words,
+			Archived:  section.Archived,
+			CreatedAt: &createdAt,
+		})
+	})
+}
+
+// GetSection 根据名称获取章节
+func (d *LogSectionDAOImpl) GetSection(ctx context.Context, name string) (*model.SectionEntity, error) {
+	var result *model.SectionEntity
+	err := d.view(func() error {
+		section := d.byName[name]
+		if section == nil {
+			return errSectionNotFound(name)
+		}
+		entity := section.entity()
+		result = &entity
+		return nil
+	})
+	return result, err
+}
+
+// UpdateSection 更新章节
+// section.Revision不为空时，如果章节在读取之后被其他goroutine或进程修改过，返回ErrSectionConflict
+func (d *LogSectionDAOImpl) UpdateSection(ctx context.Context, name string, section *model.SectionEntity) error {
+	var revision string
+	err := d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		existing := d.byName[name]
+		if existing == nil {
+			return errSectionNotFound(name)
+		}
+
+		if section.Revision != "" && section.Revision != existing.currentRevision() {
+			return errSectionConflict(name)
+		}
+
+		if section.Name != name && d.byName[section.Name] != nil {
+			return errSectionExists(section.Name)
+		}
+
+		words := cloneWords(section.Words)
+		stampWords(existing.words, words, time.Now())
+		old := existing.dao()
+		updated := model.SectionDAO{
+			Name:      section.Name,
+			CreatedAt: existing.createdAt,
+			Words:     words,
+			Archived:  section.Archived,
+		}
+		if err := record(describeSectionUpdate(&old, &updated), updateChanges(&old, &updated)); err != nil {
+			return err
+		}
+		if err := d.commit(&logRecord{Op: logOpUpdate, Section: name, NewName: section.Name, Words: words, Archived: section.Archived}); err != nil {
+			return err
+		}
+		revision = existing.currentRevision()
+		return nil
+	})
+	if err == nil {
+		// 更新版本，调用方可以继续使用同一个实体进行下一次修改
+		section.Revision = revision
+	}
+	return err
+}
+
+// DeleteSection 删除章节
+func (d *LogSectionDAOImpl) DeleteSection(ctx context.Context, name string) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		section := d.byName[name]
+		if section == nil {
+			return errSectionNotFound(name)
+		}
+		deleted := section.dao()
+		if err := record(deleteAction(name, len(section.words)), deleteChanges(d.sectionIndex(section), &deleted)); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{Op: logOpDelete, Section: name})
+	})
+}
+
+// ListSections 列出所有章节，按创建顺序返回
+func (d *LogSectionDAOImpl) ListSections(ctx context.Context) ([]model.SectionEntity, error) {
+	var sections []model.SectionEntity
+	err := d.view(func() error {
+		sections = make([]model.SectionEntity, 0, len(d.sections))
+		for _, section := range d.sections {
+			sections = append(sections, section.entity())
+		}
+		return nil
+	})
+	return sections, err
+}
+
+// SectionExists 检查章节是否存在
+func (d *LogSectionDAOImpl) SectionExists(ctx context.Context, name string) (bool, error) {
+	exists := false
+	err := d.view(func() error {
+		exists = d.byName[name] != nil
+		return nil
+	})
+	return exists, err
+}
+
+// AddWordToSection 向章节添加单词
+func (d *LogSectionDAOImpl) AddWordToSection(ctx context.Context, sectionName string, word model.WordEntity) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		section := d.byName[sectionName]
+		if section == nil {
+			return errSectionNotFound(sectionName)
+		}
+		if section.counts[word.W] > 0 {
+			return errWordExists(word.W, sectionName)
+		}
+
+		added := cloneWords([]model.WordEntity{word})[0]
+		normalizeWord(&added, time.Now())
+		if err := record(addWordAction(sectionName, word.W), []historyChange{wordChange(sectionName, len(section.words), nil, &added)}); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{Op: logOpAddWord, Section: sectionName, Word: &added})
+	})
+}
+
+// RemoveWordFromSection 从章节移除单词
+func (d *LogSectionDAOImpl) RemoveWordFromSection(ctx context.Context, sectionName string, wordText string) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		section := d.byName[sectionName]
+		if section == nil {
+			return errSectionNotFound(sectionName)
+		}
+		if section.counts[wordText] == 0 {
+			return errWordNotFound(wordText, sectionName)
+		}
+		var changes []historyChange
+		for i := range section.words {
+			if section.words[i].W == wordText {
+				changes = append(changes, wordChange(sectionName, i-len(changes), &section.words[i], nil))
+			}
+		}
+		if err := record(removeWordAction(sectionName, wordText), changes); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{Op: logOpRemoveWord, Section: sectionName, W: wordText})
+	})
+}
+
+// UpdateWord 更新章节中的单词
+func (d *LogSectionDAOImpl) UpdateWord(ctx context.Context, sectionName string, wordText string, word model.WordEntity) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		section := d.byName[sectionName]
+		if section == nil {
+			return errSectionNotFound(sectionName)
+		}
+		pos := findWord(section.words, wordText)
+		if pos < 0 {
+			return errWordNotFound(wordText, sectionName)
+		}
+		if word.W != wordText && section.counts[word.W] > 0 {
+			return errWordExists(word.W, sectionName)
+		}
+
+		edited := editedWord(section.words[pos], cloneWord(word), time.Now())
+		if err := record(editWordAction(sectionName, wordText), []historyChange{wordChange(sectionName, pos, &section.words[pos], &edited)}); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{Op: logOpUpdateWord, Section: sectionName, W: wordText, Word: &edited})
+	})
+}
+
+// MoveWord 将单词移动到另一个章节
+func (d *LogSectionDAOImpl) MoveWord(ctx context.Context, from string, to string, wordText string) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		word, err := d.locateTransfer(from, to, wordText)
+		if err != nil {
+			return err
+		}
+		if err := record(moveWordAction(wordText, from, to), []historyChange{
+			wordChange(from, findWord(d.byName[from].words, wordText), word, nil),
+			wordChange(to, len(d.byName[to].words), nil, word),
+		}); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{Op: logOpMoveWord, Section: from, Target: to, W: wordText})
+	})
+}
+
+// CopyWord 将单词复制到另一个章节，记录为向目标章节添加单词
+func (d *LogSectionDAOImpl) CopyWord(ctx context.Context, from string, to string, wordText string) error {
+	return d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		word, err := d.locateTransfer(from, to, wordText)
+		if err != nil {
+			return err
+		}
+		copied := copiedWord(*word, time.Now())
+		if err := record(copyWordAction(wordText, from, to), []historyChange{wordChange(to, len(d.byName[to].words), nil, &copied)}); err != nil {
+			return err
+		}
+		return d.commit(&logRecord{Op: logOpAddWord, Section: to, Word: &copied})
+	})
+}
+
+// locateTransfer 检查移动或复制单词的源章节、目标章节和单词，返回源章节中的单词
+// 检查顺序与sectionOps.locateTransfer一致，保证各存储方式返回相同的错误
+func (d *LogSectionDAOImpl) locateTransfer(from, to, wordText string) (*model.WordEntity, error) {
+	source := d.byName[from]
+	if source == nil {
+		return nil, errSectionNotFound(from)
+	}
+	target := d.byName[to]
+	if target == nil {
+		return nil, errSectionNotFound(to)
+	}
+	pos := findWord(source.words, wordText)
+	if pos < 0 {
+		return nil, errWordNotFound(wordText, from)
+	}
+	if target.counts[wordText] > 0 {
+		return nil, errWordExists(wordText, to)
+	}
+	return &source.words[pos], nil
+}
+
+// QueryWords 按条件查询单词，查询文本按章节缓存，只复制返回的单词
+func (d *LogSectionDAOImpl) QueryWords(ctx context.Context, query *model.WordQuery) (*model.WordQueryResult, error) {
+	filter, err := newWordFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *model.WordQueryResult
+	err = d.view(func() error {
+		if err := filter.checkSections(func(name string) bool { return d.byName[name] != nil }); err != nil {
+			return err
+		}
+
+		var hits []wordHit
+		for _, section := range d.sections {
+			if !filter.includes(section.name) {
+				continue
+			}
+			var texts []searchText
+			if filter.hasText() {
+				texts = section.searchTexts()
+			}
+			for i := range section.words {
+				if texts != nil && !filter.match(&texts[i]) {
+					continue
+				}
+				hits = append(hits, wordHit{section: section.name, word: &section.words[i]})
+			}
+		}
+		result = buildWordResult(hits, query)
+		return nil
+	})
+	return result, err
+}
+
+// QuerySections 分页查询章节，只复制返回的章节
+func (d *LogSectionDAOImpl) QuerySections(ctx context.Context, query *model.SectionQuery) (*model.SectionQueryResult, error) {
+	if err := checkPage(query.Offset, query.Limit); err != nil {
+		return nil, err
+	}
+	if err := checkSectionSort(query.Sort); err != nil {
+		return nil, err
+	}
+
+	var result *model.SectionQueryResult
+	err := d.view(func() error {
+		var matched []*logSection
+		var keys []sectionKey
+		for _, section := range d.sections {
+			if section.archived == query.Archived {
+				matched = append(matched, section)
+				keys = append(keys, sectionKey{name: section.name, createdAt: section.createdAt})
+			}
+		}
+
+		order := sortSections(keys, query.Sort)
+		start, end := pageBounds(len(order), query.Offset, query.Limit)
+		result = &model.SectionQueryResult{
+			Sections: make([]model.SectionEntity, 0, end-start),
+			Total:    len(matched),
+		}
+		for _, i := range order[start:end] {
+			result.Sections = append(result.Sections, matched[i].entity())
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ListHistory 列出保存的修改记录，最近的修改在前
+func (d *LogSectionDAOImpl) ListHistory(ctx context.Context) ([]model.HistoryEntry, error) {
+	return d.history.list()
+}
+
+// RevertHistory 只撤销id对应的一次修改，撤销后的数据重写为新的日志
+func (d *LogSectionDAOImpl) RevertHistory(ctx context.Context, id string) (*model.HistoryEntry, error) {
+	var entry *model.HistoryEntry
+	err := d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		data := d.data()
+		reverted, changes, err := d.history.revertOne(data, id)
+		if err != nil {
+			return err
+		}
+		if err := record(revertAction(reverted.Action), changes); err != nil {
+			return err
+		}
+		if err := d.replace(data); err != nil {
+			return err
+		}
+		entry = reverted
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RestoreHistory 从最近一次修改开始依次撤销，直到id对应的修改，恢复后的数据重写为新的日志
+// 与其他存储方式相同，恢复同样记录在修改历史中，单词的复习进度和错题本保持当前状态
+func (d *LogSectionDAOImpl) RestoreHistory(ctx context.Context, id string) (*model.HistoryEntry, error) {
+	var entry *model.HistoryEntry
+	err := d.mutate(ctx, func(record func(action string, changes []historyChange) error) error {
+		data := d.data()
+		restored, changes, err := d.history.revertTo(data, id)
+		if err != nil {
+			return err
+		}
+		if err := record(restoreAction(restored.Action), changes); err != nil {
+			return err
+		}
+		if err := d.replace(data); err != nil {
+			return err
+		}
+		entry = restored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
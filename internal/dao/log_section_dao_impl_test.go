@@ -0,0 +1,666 @@
+package dao
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// sectionBackends 全部SectionDAO实现，用于验证各实现的行为一致
+var sectionBackends = []struct {
+	name   string
+	newDAO func(dir string) SectionDAOInterface
+}{
+	{"file", func(dir string) SectionDAOInterface { return NewSectionDAO(filepath.Join(dir, "sections.json")) }},
+	{"dir", func(dir string) SectionDAOInterface { return NewDirSectionDAO(filepath.Join(dir, "vocab")) }},
+	{"log", func(dir string) SectionDAOInterface { return NewLogSectionDAO(filepath.Join(dir, "sections.vlog")) }},
+}
+
+func TestSectionDAOBackends(t *testing.T) {
+	ctx := context.Background()
+
+	// run 依次执行一组操作，返回每一步的结果（错误信息或读取到的内容），各实现的结果应完全相同
+	run := func(t *testing.T, dao SectionDAOInterface) []string {
+		var results []string
+		record := func(step string, err error) {
+			if err != nil {
+				step += ": " + err.Error()
+			}
+			results = append(results, step)
+		}
+
+		record("create", dao.CreateSection(ctx, &model.SectionEntity{Name: "day1", Words: []model.WordEntity{{W: "dam", C: "水坝"}}}))
+		record("create-dup", dao.CreateSection(ctx, &model.SectionEntity{Name: "day1"}))
+		record("create-2", dao.CreateSection(ctx, &model.SectionEntity{Name: "day2"}))
+		record("add", dao.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid", C: "中标"}))
+		record("add-dup", dao.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid", C: "出价"}))
+		record("add-missing", dao.AddWordToSection(ctx, "day9", model.WordEntity{W: "bid"}))
+		record("remove-missing", dao.RemoveWordFromSection(ctx, "day1", "nope"))
+
+		// 修改返回的实体不应影响已保存的数据
+		section, err := dao.GetSection(ctx, "day1")
+		record("get", err)
+		section.Words[0].C = "未保存的修改"
+		again, _ := dao.GetSection(ctx, "day1")
+		record("isolated "+again.Words[0].C, nil)
+
+		// 使用过期的版本更新时报冲突，更新成功后版本随之更新
+		stale := *again
+		again.Words = append(again.Words, model.WordEntity{W: "cue", C: "提示"})
+		record("update", dao.UpdateSection(ctx, "day1", again))
+		stale.Words = again.Words[:1]
+		err = dao.UpdateSection(ctx, "day1", &stale)
+		record(fmt.Sprintf("conflict %v", errors.Is(err, ErrSectionConflict)), err)
+		again.Words = again.Words[:2]
+		record("update-again", dao.UpdateSection(ctx, "day1", again))
+
+		renamed := &model.SectionEntity{Name: "day2", Words: again.Words}
+		record("rename-taken", dao.UpdateSection(ctx, "day1", renamed))
+		renamed.Name = "day3"
+		record("rename", dao.UpdateSection(ctx, "day1", renamed))
+		record("remove", dao.RemoveWordFromSection(ctx, "day3", "dam"))
+		record("delete", dao.DeleteSection(ctx, "day2"))
+		record("delete-missing", dao.DeleteSection(ctx, "day2"))
+		exists, err := dao.SectionExists(ctx, "day3")
+		record(fmt.Sprintf("exists %v", exists), err)
+
+		sections, err := dao.ListSections(ctx)
+		record("list", err)
+		for _, section := range sections {
+			words := make([]string, 0, len(section.Words))
+			for _, word := range section.Words {
+				if word.ID == "" || word.CreatedAt.IsZero() {
+					t.Errorf("单词 %s 缺少ID或创建时间", word.W)
+				}
+				words = append(words, word.W+"="+word.C)
+			}
+			results = append(results, section.Name+": "+strings.Join(words, ","))
+		}
+		return results
+	}
+
+	var expected []string
+	for _, backend := range sectionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			dir := t.TempDir()
+			results := run(t, backend.newDAO(dir))
+
+			// 新的实例从文件中读取到相同的数据
+			sections, err := backend.newDAO(dir).ListSections(ctx)
+			if err != nil || len(sections) != 1 || len(sections[0].Words) != 1 || sections[0].Words[0].W != "bid" {
+				t.Errorf("重新加载的数据不正确: %v, %+v", err, sections)
+			}
+
+			if expected == nil {
+				expected = results
+				return
+			}
+			if strings.Join(results, "\n") != strings.Join(expected, "\n") {
+				t.Errorf("与%s实现的行为不一致:\n%s\n期望:\n%s", sectionBackends[0].name, strings.Join(results, "\n"), strings.Join(expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestWordOperations(t *testing.T) {
+	ctx := context.Background()
+
+	// dump 以文本形式输出全部章节，包含单词的ID、释义和复习状态，用于比较各实现和重新加载后的结果
+	dump := func(dao SectionDAOInterface) string {
+		sections, err := dao.ListSections(ctx)
+		if err != nil {
+			return "list: " + err.Error()
+		}
+		var lines []string
+		for _, section := range sections {
+			words := make([]string, 0, len(section.Words))
+			for _, word := range section.Words {
+				words = append(words, fmt.Sprintf("%s=%s(review %v)", word.W, word.C, word.Review != nil))
+			}
+			lines = append(lines, section.Name+": "+strings.Join(words, ","))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	var expected []string
+	for _, backend := range sectionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dao := backend.newDAO(dir)
+			var results []string
+			record := func(step string, err error) {
+				if err != nil {
+					step += ": " + err.Error()
+				}
+				results = append(results, step)
+			}
+
+			reviewed := model.WordEntity{W: "dam", C: "水坝", Review: &model.ReviewState{Reviews: 2}}
+			record("create", dao.CreateSection(ctx, &model.SectionEntity{Name: "day1", Words: []model.WordEntity{reviewed, {W: "bid", C: "出价"}}}))
+			record("create-2", dao.CreateSection(ctx, &model.SectionEntity{Name: "day2", Words: []model.WordEntity{{W: "cue", C: "提示"}}}))
+			before, _ := dao.GetSection(ctx, "day1")
+
+			record("update", dao.UpdateWord(ctx, "day1", "dam", model.WordEntity{W: "dam", C: "水坝;堤坝", Review: before.Words[0].Review}))
+			record("update-missing-section", dao.UpdateWord(ctx, "day9", "dam", model.WordEntity{W: "dam"}))
+			record("update-missing-word", dao.UpdateWord(ctx, "day1", "nope", model.WordEntity{W: "nope"}))
+			record("rename-taken", dao.UpdateWord(ctx, "day1", "bid", model.WordEntity{W: "dam"}))
+			record("rename", dao.UpdateWord(ctx, "day1", "bid", model.WordEntity{W: "bids", C: "出价"}))
+
+			after, _ := dao.GetSection(ctx, "day1")
+			if after.Words[0].ID != before.Words[0].ID || !after.Words[0].CreatedAt.Equal(before.Words[0].CreatedAt) {
+				t.Errorf("更新单词后ID或创建时间发生了变化: %+v -> %+v", before.Words[0], after.Words[0])
+			}
+
+			record("copy", dao.CopyWord(ctx, "day1", "day2", "dam"))
+			record("copy-dup", dao.CopyWord(ctx, "day1", "day2", "dam"))
+			record("copy-same", dao.CopyWord(ctx, "day1", "day1", "dam"))
+			record("move-missing-target", dao.MoveWord(ctx, "day1", "day9", "bids"))
+			record("move-missing-word", dao.MoveWord(ctx, "day1", "day2", "nope"))
+			record("move", dao.MoveWord(ctx, "day1", "day2", "bids"))
+
+			copied, _ := dao.GetSection(ctx, "day2")
+			if copied.Words[1].ID == after.Words[0].ID {
+				t.Errorf("复制的单词应使用新的ID")
+			}
+			if copied.Words[2].ID != after.Words[1].ID {
+				t.Errorf("移动的单词应保留ID")
+			}
+
+			record(dump(dao), nil)
+			if reloaded := dump(backend.newDAO(dir)); reloaded != dump(dao) {
+				t.Errorf("重新加载的数据不一致:\n%s\n期望:\n%s", reloaded, dump(dao))
+			}
+
+			if expected == nil {
+				expected = results
+				return
+			}
+			if strings.Join(results, "\n") != strings.Join(expected, "\n") {
+				t.Errorf("与%s实现的行为不一致:\n%s\n期望:\n%s", sectionBackends[0].name, strings.Join(results, "\n"), strings.Join(expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestArchivedSections(t *testing.T) {
+	ctx := context.Background()
+
+	for _, backend := range sectionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dao := backend.newDAO(dir)
+			for _, name := range []string{"day1", "day2", "day3"} {
+				if err := dao.CreateSection(ctx, &model.SectionEntity{Name: name, Archived: name == "day1"}); err != nil {
+					t.Fatalf("创建章节失败: %v", err)
+				}
+			}
+
+			// 更新单词时保留归档状态，重命名后仍为归档
+			section, _ := dao.GetSection(ctx, "day2")
+			section.Archived = true
+			section.Words = []model.WordEntity{{W: "dam", C: "水坝"}}
+			if err := dao.UpdateSection(ctx, "day2", section); err != nil {
+				t.Fatalf("更新章节失败: %v", err)
+			}
+			section.Name = "day2-old"
+			if err := dao.UpdateSection(ctx, "day2", section); err != nil {
+				t.Fatalf("重命名章节失败: %v", err)
+			}
+			if err := dao.AddWordToSection(ctx, "day2-old", model.WordEntity{W: "bid"}); err != nil {
+				t.Fatalf("添加单词失败: %v", err)
+			}
+
+			// 压缩日志后仍保留归档状态
+			if logDAO, ok := dao.(*LogSectionDAOImpl); ok {
+				if err := logDAO.Compact(); err != nil {
+					t.Fatalf("压缩日志失败: %v", err)
+				}
+			}
+
+			// 新的实例从文件中读取到相同的归档状态
+			for _, instance := range []SectionDAOInterface{dao, backend.newDAO(dir)} {
+				archived, err := instance.QuerySections(ctx, &model.SectionQuery{Archived: true})
+				if err != nil || archived.Total != 2 || archived.Sections[0].Name != "day1" || archived.Sections[1].Name != "day2-old" {
+					t.Errorf("已归档章节不正确: %v, %+v", err, archived)
+				}
+				active, err := instance.QuerySections(ctx, &model.SectionQuery{Limit: 10})
+				if err != nil || active.Total != 1 || active.Sections[0].Name != "day3" {
+					t.Errorf("未归档章节不正确: %v, %+v", err, active)
+				}
+			}
+		})
+	}
+}
+
+func TestSectionOrder(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, backend := range sectionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dao := backend.newDAO(dir)
+			// 创建顺序、创建时间、名称和名称中的日期各不相同
+			sections := []model.SectionEntity{
+				{Name: "day10 2025 Feb. 22", CreatedAt: created.Add(2 * time.Hour)},
+				{Name: "Day2 2025-01-05", CreatedAt: created},
+				{Name: "复习"},
+				{Name: "day3 Jan 9, 2025", CreatedAt: created.Add(time.Hour)},
+			}
+			for i := range sections {
+				if err := dao.CreateSection(ctx, &sections[i]); err != nil {
+					t.Fatalf("创建章节失败: %v", err)
+				}
+			}
+
+			// 更新和重命名不改变创建时间
+			section, _ := dao.GetSection(ctx, "Day2 2025-01-05")
+			section.Words = []model.WordEntity{{W: "dam"}}
+			if err := dao.UpdateSection(ctx, "Day2 2025-01-05", section); err != nil {
+				t.Fatalf("更新章节失败: %v", err)
+			}
+
+			expected := map[model.SectionSortMode]string{
+				model.SectionSortStored:  "day10 2025 Feb. 22,Day2 2025-01-05,复习,day3 Jan 9, 2025",
+				model.SectionSortCreated: "Day2 2025-01-05,day3 Jan 9, 2025,day10 2025 Feb. 22,复习",
+				model.SectionSortName:    "Day2 2025-01-05,day3 Jan 9, 2025,day10 2025 Feb. 22,复习",
+				model.SectionSortDate:    "Day2 2025-01-05,day3 Jan 9, 2025,day10 2025 Feb. 22,复习",
+			}
+			// 新的实例从文件中读取到相同的创建时间
+			for _, instance := range []SectionDAOInterface{dao, backend.newDAO(dir)} {
+				for mode, names := range expected {
+					result, err := instance.QuerySections(ctx, &model.SectionQuery{Sort: mode})
+					if err != nil {
+						t.Fatalf("查询章节失败: %v", err)
+					}
+					var actual []string
+					for _, section := range result.Sections {
+						actual = append(actual, section.Name)
+					}
+					if strings.Join(actual, ",") != names {
+						t.Errorf("排序方式 %q 的结果不正确: %v", mode, actual)
+					}
+				}
+
+				// 按创建时间分页
+				page, _ := instance.QuerySections(ctx, &model.SectionQuery{Sort: model.SectionSortCreated, Offset: 1, Limit: 1})
+				if page.Total != 4 || len(page.Sections) != 1 || !page.Sections[0].CreatedAt.Equal(created.Add(time.Hour)) {
+					t.Errorf("分页结果不正确: %+v", page)
+				}
+			}
+
+			if _, err := dao.QuerySections(ctx, &model.SectionQuery{Sort: "size"}); err == nil {
+				t.Error("不支持的排序方式应返回错误")
+			}
+		})
+	}
+}
+
+func TestLogSectionDAO(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (*LogSectionDAOImpl, string) {
+		path := filepath.Join(t.TempDir(), "sections.vlog")
+		dao := NewLogSectionDAO(path).(*LogSectionDAOImpl)
+		if err := dao.CreateSection(ctx, &model.SectionEntity{Name: "day1", Words: []model.WordEntity{{W: "dam", C: "水坝"}}}); err != nil {
+			t.Fatalf("创建章节失败: %v", err)
+		}
+		return dao, path
+	}
+	countWords := func(t *testing.T, dao SectionDAOInterface, name string) int {
+		section, err := dao.GetSection(ctx, name)
+		if err != nil {
+			t.Fatalf("获取章节失败: %v", err)
+		}
+		return len(section.Words)
+	}
+
+	t.Run("SeeOtherInstances", func(t *testing.T) {
+		first, path := setup(t)
+		second := NewLogSectionDAO(path)
+		if countWords(t, second, "day1") != 1 {
+			t.Fatal("第二个实例应读取到已有数据")
+		}
+
+		// 两个实例交替写入，各自都能看到对方追加的记录
+		if err := first.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+		if err := second.AddWordToSection(ctx, "day1", model.WordEntity{W: "cue"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+		if err := first.AddWordToSection(ctx, "day1", model.WordEntity{W: "cue"}); err == nil {
+			t.Error("应发现另一个实例已添加的单词")
+		}
+
+		// 压缩后其他实例重新加载
+		if err := first.Compact(); err != nil {
+			t.Fatalf("压缩失败: %v", err)
+		}
+		if err := second.AddWordToSection(ctx, "day1", model.WordEntity{W: "due"}); err != nil {
+			t.Fatalf("压缩后添加单词失败: %v", err)
+		}
+		if countWords(t, first, "day1") != 4 {
+			t.Error("压缩后两个实例的数据应一致")
+		}
+	})
+
+	t.Run("TruncateTornTail", func(t *testing.T) {
+		_, path := setup(t)
+
+		// 模拟追加记录时崩溃：文件末尾只有半行
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatalf("打开数据文件失败: %v", err)
+		}
+		file.WriteString(`0badc0de {"op":"add_word","section":"day1","word":{"W":"bi`)
+		file.Close()
+
+		dao := NewLogSectionDAO(path)
+		if err := dao.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid"}); err != nil {
+			t.Fatalf("截断后添加单词失败: %v", err)
+		}
+		if countWords(t, NewLogSectionDAO(path), "day1") != 2 {
+			t.Error("应丢弃不完整的记录并保留之后的修改")
+		}
+	})
+
+	t.Run("RejectCorruptRecord", func(t *testing.T) {
+		first, path := setup(t)
+		if err := first.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+
+		// 中间的记录损坏时不能截断之后的有效数据
+		raw, _ := os.ReadFile(path)
+		corrupted := bytes.Replace(raw, []byte("水坝"), []byte("大坝"), 1)
+		if err := os.WriteFile(path, corrupted, 0644); err != nil {
+			t.Fatalf("写入数据文件失败: %v", err)
+		}
+		if _, err := NewLogSectionDAO(path).ListSections(ctx); err == nil {
+			t.Error("应报告数据文件损坏")
+		}
+		if after, _ := os.ReadFile(path); !bytes.Equal(after, corrupted) {
+			t.Error("不应修改损坏的数据文件")
+		}
+	})
+
+	t.Run("FailedAppendNotReplayed", func(t *testing.T) {
+		dao, path := setup(t)
+
+		dao.write = failingWrite(1)
+		if err := dao.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid"}); err == nil {
+			t.Fatal("写入失败时应返回错误")
+		}
+		if countWords(t, dao, "day1") != 1 || countWords(t, NewLogSectionDAO(path), "day1") != 1 {
+			t.Error("失败的修改不应生效")
+		}
+	})
+
+	t.Run("AutoCompact", func(t *testing.T) {
+		defer func(previous int64) { compactMinBytes = previous }(compactMinBytes)
+		compactMinBytes = 1
+
+		dao, path := setup(t)
+		for i := 0; i < 20; i++ {
+			section, err := dao.GetSection(ctx, "day1")
+			if err != nil {
+				t.Fatalf("获取章节失败: %v", err)
+			}
+			section.Words[0].C = fmt.Sprintf("释义%d", i)
+			if err := dao.UpdateSection(ctx, "day1", section); err != nil {
+				t.Fatalf("更新章节失败: %v", err)
+			}
+		}
+
+		raw, _ := os.ReadFile(path)
+		if lines := bytes.Count(raw, []byte("\n")); lines > 3 {
+			t.Errorf("日志应被自动压缩，实际有%d行", lines)
+		}
+		section, _ := NewLogSectionDAO(path).GetSection(ctx, "day1")
+		if section.Words[0].C != "释义19" {
+			t.Errorf("压缩后数据不正确: %s", section.Words[0].C)
+		}
+	})
+
+	t.Run("RestoreHistory", func(t *testing.T) {
+		dao, path := setup(t)
+		if err := dao.AddWordToSection(ctx, "day1", model.WordEntity{W: "bid", C: "出价"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+		section, _ := dao.GetSection(ctx, "day1")
+		section.Words[0].Review = &model.ReviewState{Repetitions: 1}
+		if err := dao.UpdateSection(ctx, "day1", section); err != nil {
+			t.Fatalf("更新复习进度失败: %v", err)
+		}
+
+		entries, err := dao.ListHistory(ctx)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("期望2条修改记录，实际%d条: %v", len(entries), err)
+		}
+		if entries[0].Action != "向章节 day1 添加单词 bid" {
+			t.Errorf("修改记录不正确: %+v", entries[0])
+		}
+
+		if _, err := dao.RestoreHistory(ctx, entries[0].ID); err != nil {
+			t.Fatalf("撤销修改失败: %v", err)
+		}
+		// 其他实例重新加载压缩后的日志，复习进度保持恢复前的状态
+		restored, err := NewLogSectionDAO(path).GetSection(ctx, "day1")
+		if err != nil || len(restored.Words) != 1 || restored.Words[0].Review == nil {
+			t.Fatalf("恢复后的数据不正确: %+v, %v", restored, err)
+		}
+		if entries, _ := dao.ListHistory(ctx); len(entries) != 3 || entries[0].Action != "撤销到“向章节 day1 添加单词 bid”之前" {
+			t.Errorf("撤销本身也应记录在修改历史中: %+v", entries)
+		}
+	})
+}
+
+const (
+	benchSections        = 100
+	benchWordsPerSection = 1000 // 共10万个单词
+)
+
+// seedBench 创建包含10万个单词的数据，JSON文件直接写入，其他实现通过DAO创建
+func seedBench(b *testing.B, backend string, dao SectionDAOInterface, dir string) {
+	ctx := context.Background()
+	now := time.Now()
+	data := &model.WordsFileDAO{Version: model.CurrentSchemaVersion}
+	for i := 0; i < benchSections; i++ {
+		words := make([]model.WordEntity, benchWordsPerSection)
+		for j := range words {
+			words[j] = model.WordEntity{W: fmt.Sprintf("word-%d-%d", i, j), C: fmt.Sprintf("释义%d", j), Phrase: "an example phrase"}
+			normalizeWord(&words[j], now)
+		}
+		data.Sections = append(data.Sections, model.SectionDAO{Name: fmt.Sprintf("day%d", i), Words: words})
+	}
+
+	if backend == "file" {
+		file := dao.(*SectionDAOImpl)
+		if err := file.saveData(data); err != nil {
+			b.Fatalf("写入测试数据失败: %v", err)
+		}
+		return
+	}
+	for _, section := range data.Sections {
+		if err := dao.CreateSection(ctx, &model.SectionEntity{Name: section.Name, Words: section.Words}); err != nil {
+			b.Fatalf("写入测试数据失败: %v", err)
+		}
+	}
+}
+
+// BenchmarkAddWord 在10万个单词的词库中添加单词（与Service.AddWord一样先检查章节是否存在）
+func BenchmarkAddWord(b *testing.B) {
+	ctx := context.Background()
+	for _, backend := range sectionBackends {
+		b.Run(backend.name, func(b *testing.B) {
+			dir := b.TempDir()
+			dao := backend.newDAO(dir)
+			seedBench(b, backend.name, dao, dir)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				section := fmt.Sprintf("day%d", i%benchSections)
+				if _, err := dao.SectionExists(ctx, section); err != nil {
+					b.Fatal(err)
+				}
+				if err := dao.AddWordToSection(ctx, section, model.WordEntity{W: fmt.Sprintf("new-%d", i), C: "新词"}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSearchWord 在10万个单词的词库中搜索单词（与Service.SearchWord一样遍历全部章节）
+func BenchmarkSearchWord(b *testing.B) {
+	ctx := context.Background()
+	for _, backend := range sectionBackends {
+		b.Run(backend.name, func(b *testing.B) {
+			dir := b.TempDir()
+			dao := backend.newDAO(dir)
+			seedBench(b, backend.name, dao, dir)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				target := fmt.Sprintf("word-%d-%d", i%benchSections, i%benchWordsPerSection)
+				sections, err := dao.ListSections(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+				found := false
+				for _, section := range sections {
+					for _, word := range section.Words {
+						if word.W == target {
+							found = true
+						}
+					}
+				}
+				if !found {
+					b.Fatalf("未找到单词 %s", target)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryWords(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 3, 24, 9, 0, 0, 0, time.Local)
+
+	for _, backend := range sectionBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			dao := backend.newDAO(t.TempDir())
+			sections := []model.SectionEntity{
+				{Name: "day1", Words: []model.WordEntity{
+					{W: "dam", C: "水坝", Phrase: "The dam broke.", Tags: []string{"CET4"}, CreatedAt: base.Add(3 * time.Hour)},
+					{W: "Bid", C: "出价", Senses: []model.Sense{{Meaning: "出价"}, {Meaning: "投标", Examples: []string{"They won the bid."}}}, CreatedAt: base.Add(1 * time.Hour)},
+				}},
+				{Name: "day2", Words: []model.WordEntity{
+					{W: "École", C: "学校", Tags: []string{"french"}, CreatedAt: base.Add(2 * time.Hour)},
+					{W: "cue", C: "提示", Phrase: "Wait for your cue.", Tags: []string{"cet4"}, CreatedAt: base},
+				}},
+			}
+			for i := range sections {
+				if err := dao.CreateSection(ctx, &sections[i]); err != nil {
+					t.Fatalf("创建章节失败: %v", err)
+				}
+			}
+
+			cases := []struct {
+				name  string
+				query model.WordQuery
+				want  string
+				total int
+			}{
+				{"All", model.WordQuery{}, "dam,Bid,École,cue", 4},
+				{"Section", model.WordQuery{Sections: []string{"day2"}}, "École,cue", 2},
+				{"WordIgnoreCase", model.WordQuery{Word: "BI"}, "Bid", 1},
+				{"UnicodeIgnoreCase", model.WordQuery{Word: "éCOLE"}, "École", 1},
+				{"MeaningInSenses", model.WordQuery{Meaning: "投标"}, "Bid", 1},
+				{"PhraseInExamples", model.WordQuery{Phrase: "WON THE"}, "Bid", 1},
+				{"Tag", model.WordQuery{Tag: "cet4"}, "dam,cue", 2},
+				{"Keyword", model.WordQuery{Keyword: "提示"}, "cue", 1},
+				{"Combined", model.WordQuery{Tag: "cet4", Sections: []string{"day1"}}, "dam", 1},
+				{"SortByWord", model.WordQuery{Sort: []model.WordSortKey{{Field: model.SortByWord}}}, "Bid,cue,dam,École", 4},
+				{"SortByCreatedDesc", model.WordQuery{Sort: []model.WordSortKey{{Field: model.SortByCreatedAt, Desc: true}}}, "dam,École,Bid,cue", 4},
+				{"SortThenSection", model.WordQuery{Sort: []model.WordSortKey{{Field: model.SortBySection, Desc: true}, {Field: model.SortByWord}}}, "cue,École,Bid,dam", 4},
+				{"Page", model.WordQuery{Sort: []model.WordSortKey{{Field: model.SortByWord}}, Offset: 1, Limit: 2}, "cue,dam", 4},
+				{"OffsetPastEnd", model.WordQuery{Offset: 10}, "", 4},
+			}
+			for _, c := range cases {
+				result, err := dao.QueryWords(ctx, &c.query)
+				if err != nil {
+					t.Errorf("%s: 查询失败: %v", c.name, err)
+					continue
+				}
+				words := make([]string, 0, len(result.Words))
+				for _, match := range result.Words {
+					words = append(words, match.Word.W)
+				}
+				if got := strings.Join(words, ","); got != c.want || result.Total != c.total {
+					t.Errorf("%s: 期望%s(共%d个)，实际%s(共%d个)", c.name, c.want, c.total, got, result.Total)
+				}
+			}
+
+			// 查询之后添加的单词也能被查到
+			if err := dao.AddWordToSection(ctx, "day2", model.WordEntity{W: "bidder", C: "投标人"}); err != nil {
+				t.Fatalf("添加单词失败: %v", err)
+			}
+			if result, _ := dao.QueryWords(ctx, &model.WordQuery{Meaning: "投标"}); result.Total != 2 {
+				t.Errorf("应查到新添加的单词，实际共%d个", result.Total)
+			}
+
+			// 修改查询结果不影响已保存的数据
+			result, _ := dao.QueryWords(ctx, &model.WordQuery{Word: "dam"})
+			result.Words[0].Word.Tags[0] = "changed"
+			if again, _ := dao.QueryWords(ctx, &model.WordQuery{Tag: "cet4"}); again.Total != 2 {
+				t.Error("查询结果应为副本")
+			}
+
+			if _, err := dao.QueryWords(ctx, &model.WordQuery{Sections: []string{"day9"}}); err == nil {
+				t.Error("限定的章节不存在时应返回错误")
+			}
+			if _, err := dao.QueryWords(ctx, &model.WordQuery{Sort: []model.WordSortKey{{Field: "color"}}}); err == nil {
+				t.Error("不支持的排序字段应返回错误")
+			}
+
+			page, err := dao.QuerySections(ctx, &model.SectionQuery{Offset: 1, Limit: 5})
+			if err != nil || page.Total != 2 || len(page.Sections) != 1 || page.Sections[0].Name != "day2" {
+				t.Errorf("分页查询章节不正确: %v, %+v", err, page)
+			}
+		})
+	}
+}
+
+// BenchmarkQueryWords 在10万个单词的词库中按关键词查询单词（Service.SearchWord的实现）
+func BenchmarkQueryWords(b *testing.B) {
+	ctx := context.Background()
+	for _, backend := range sectionBackends {
+		b.Run(backend.name, func(b *testing.B) {
+			dir := b.TempDir()
+			dao := backend.newDAO(dir)
+			seedBench(b, backend.name, dao, dir)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				target := fmt.Sprintf("WORD-%d-%d", i%benchSections, i%benchWordsPerSection)
+				result, err := dao.QueryWords(ctx, &model.WordQuery{Keyword: target, Limit: 10})
+				if err != nil {
+					b.Fatal(err)
+				}
+				if result.Total == 0 {
+					b.Fatalf("未找到单词 %s", target)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package dao
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// wordFilter 校验并编译后的单词查询条件，文本条件已转为小写
+type wordFilter struct {
+	sections []string
+	included map[string]bool
+	word     string
+	meaning  string
+	phrase   string
+	tag      string
+	keyword  string
+}
+
+// newWordFilter 校验查询条件并编译为wordFilter
+func newWordFilter(query *model.WordQuery) (*wordFilter, error) {
+	if err := checkPage(query.Offset, query.Limit); err != nil {
+		return nil, err
+	}
+	for _, key := range query.Sort {
+		switch key.Field {
+		case model.SortByWord, model.SortBySection, model.SortByCreatedAt, model.SortByUpdatedAt, model.SortByErrorRate:
+		default:
+			return nil, fmt.Errorf("不支持的排序字段 '%s'", key.Field)
+		}
+	}
+
+	filter := &wordFilter{
+		sections: query.Sections,
+		word:     strings.ToLower(query.Word),
+		meaning:  strings.ToLower(query.Meaning),
+		phrase:   strings.ToLower(query.Phrase),
+		tag:      strings.ToLower(query.Tag),
+		keyword:  strings.ToLower(query.Keyword),
+	}
+	if len(query.Sections) > 0 {
+		filter.included = make(map[string]bool, len(query.Sections))
+		for _, name := range query.Sections {
+			filter.included[name] = true
+		}
+	}
+	return filter, nil
+}
+
+// checkPage 校验分页参数
+func checkPage(offset, limit int) error {
+	if offset < 0 || limit < 0 {
+		return fmt.Errorf("分页参数不能为负数: offset=%d, limit=%d", offset, limit)
+	}
+	return nil
+}
+
+// checkSections 限定的章节必须存在
+func (f *wordFilter) checkSections(exists func(name string) bool) error {
+	for _, name := range f.sections {
+		if !exists(name) {
+			return errSectionNotFound(name)
+		}
+	}
+	return nil
+}
+
+// includes 判断章节是否在查询范围内
+func (f *wordFilter) includes(section string) bool {
+	return f.included == nil || f.included[section]
+}
+
+// hasText 是否有文本条件，没有时无需计算单词的查询文本
+func (f *wordFilter) hasText() bool {
+	return f.word != "" || f.meaning != "" || f.phrase != "" || f.tag != "" || f.keyword != ""
+}
+
+// match 判断单词是否满足全部文本条件
+func (f *wordFilter) match(text *searchText) bool {
+	if f.word != "" && !strings.Contains(text.word, f.word) {
+		return false
+	}
+	if f.meaning != "" && !strings.Contains(text.meanings, f.meaning) {
+		return false
+	}
+	if f.phrase != "" && !strings.Contains(text.phrases, f.phrase) {
+		return false
+	}
+	if f.keyword != "" && !strings.Contains(text.word, f.keyword) && !strings.Contains(text.c, f.keyword) {
+		return false
+	}
+	if f.tag != "" {
+		for _, tag := range text.tags {
+			if tag == f.tag {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// searchText 单词中参与查询的文本，均已转为小写；多个释义或例句以换行分隔，避免跨越两条文本匹配
+type searchText struct {
+	word     string
+	c        string
+	meanings string
+	phrases  string
+	tags     []string
+}
+
+// newSearchText 计算单词的查询文本
+func newSearchText(word *model.WordEntity) searchText {
+	meanings := []string{word.C}
+	phrases := []string{word.Phrase}
+	for _, sense := range word.Senses {
+		meanings = append(meanings, sense.Meaning)
+		phrases = append(phrases, sense.Examples...)
+	}
+	tags := make([]string, len(word.Tags))
+	for i, tag := range word.Tags {
+		tags[i] = strings.ToLower(tag)
+	}
+
+	return searchText{
+		word:     strings.ToLower(word.W),
+		c:        strings.ToLower(word.C),
+		meanings: strings.ToLower(strings.Join(meanings, "\n")),
+		phrases:  strings.ToLower(strings.Join(phrases, "\n")),
+		tags:     tags,
+	}
+}
+
+// wordHit 命中查询条件的单词，指向存储中的数据，排序分页后才复制
+type wordHit struct {
+	section string
+	word    *model.WordEntity
+}
+
+// compareHits 按字段比较两个单词，返回-1、0或1
+func compareHits(a, b *wordHit, field model.WordSortField) int {
+	switch field {
+	case model.SortByWord:
+		return strings.Compare(strings.ToLower(a.word.W), strings.ToLower(b.word.W))
+	case model.SortBySection:
+		return strings.Compare(a.section, b.section)
+	case model.SortByCreatedAt:
+		return compareResult(a.word.CreatedAt.Before(b.word.CreatedAt), a.word.CreatedAt.After(b.word.CreatedAt))
+	case model.SortByUpdatedAt:
+		return compareResult(a.word.UpdatedAt.Before(b.word.UpdatedAt), a.word.UpdatedAt.After(b.word.UpdatedAt))
+	case model.SortByErrorRate:
+		x, y := a.word.Review.ErrorRate(), b.word.Review.ErrorRate()
+		return compareResult(x < y, x > y)
+	}
+	return 0
+}
+
+// compareResult 将“小于”“大于”的比较结果转换为-1、0或1
+func compareResult(less, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// buildWordResult 对命中的单词排序、分页，只复制返回的单词
+// 没有排序键或排序键相同时保持章节顺序和章节内的顺序
+func buildWordResult(hits []wordHit, query *model.WordQuery) *model.WordQueryResult {
+	if len(query.Sort) > 0 {
+		sort.SliceStable(hits, func(i, j int) bool {
+			for _, key := range query.Sort {
+				c := compareHits(&hits[i], &hits[j], key.Field)
+				if c == 0 {
+					continue
+				}
+				if key.Desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	start, end := pageBounds(len(hits), query.Offset, query.Limit)
+	words := make([]model.WordMatch, 0, end-start)
+	for _, hit := range hits[start:end] {
+		words = append(words, model.WordMatch{Section: hit.section, Word: cloneWord(*hit.word)})
+	}
+	return &model.WordQueryResult{Words: words, Total: len(hits)}
+}
+
+// pageBounds 计算分页的起止下标
+func pageBounds(total, offset, limit int) (int, int) {
+	start := offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	return start, end
+}
@@ -0,0 +1,193 @@
+package dao
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// decodeWordsFile 解析数据文件，旧格式会自动迁移到当前版本
+// migrated表示数据在解析过程中被修改（如迁移格式、补全ID），需要写回文件
+func decodeWordsFile(data []byte, now time.Time) (file *model.WordsFileDAO, migrated bool, err error) {
+	version, err := detectSchemaVersion(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case version > model.CurrentSchemaVersion:
+		return nil, false, fmt.Errorf("数据文件版本 %d 高于程序支持的版本 %d，请升级程序", version, model.CurrentSchemaVersion)
+	case version == 1:
+		var legacy model.WordsDataDAO
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return nil, false, fmt.Errorf("解析JSON失败: %w", err)
+		}
+		return migrateV1(legacy, now), true, nil
+	}
+
+	file = &model.WordsFileDAO{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, false, fmt.Errorf("解析JSON失败: %w", err)
+	}
+	for i := range file.Sections {
+		if file.Sections[i].Words == nil {
+			file.Sections[i].Words = make([]model.WordEntity, 0)
+		}
+		for j := range file.Sections[i].Words {
+			if normalizeWord(&file.Sections[i].Words[j], now) {
+				migrated = true
+			}
+		}
+		if normalizeSection(&file.Sections[i], now) {
+			migrated = true
+		}
+	}
+	return file, migrated, nil
+}
+
+// detectSchemaVersion 检测数据文件的格式版本，没有version字段的对象为版本1
+func detectSchemaVersion(data []byte) (int, error) {
+	var root map[string]json.RawMessage
+	if err := json.Unmarshal(data, &root); err != nil {
+		return 0, fmt.Errorf("解析JSON失败: %w", err)
+	}
+
+	raw, ok := root["version"]
+	if !ok {
+		return 1, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		// 旧格式中恰好有名为version的章节
+		return 1, nil
+	}
+	return version, nil
+}
+
+// migrateV1 将版本1的数据迁移到当前版本
+// 旧格式的章节顺序不固定，迁移时按章节名称排序
+func migrateV1(legacy model.WordsDataDAO, now time.Time) *model.WordsFileDAO {
+	names := make([]string, 0, len(legacy))
+	for name := range legacy {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	file := &model.WordsFileDAO{
+		Version:  model.CurrentSchemaVersion,
+		Sections: make([]model.SectionDAO, 0, len(names)),
+	}
+	for _, name := range names {
+		words := legacy[name]
+		if words == nil {
+			words = make([]model.WordEntity, 0)
+		}
+		for i := range words {
+			normalizeWord(&words[i], now)
+		}
+		file.Sections = append(file.Sections, model.SectionDAO{Name: name, CreatedAt: now, Words: words})
+	}
+	return file
+}
+
+// normalizeSection 补全章节的创建时间：较早的数据没有该字段，取章节中最早的单词创建时间，没有单词时取当前时间
+// 调用前单词需已补全，返回是否修改了章节
+func normalizeSection(section *model.SectionDAO, now time.Time) bool {
+	if !section.CreatedAt.IsZero() {
+		return false
+	}
+	section.CreatedAt = earliestCreated(section.Words, now)
+	return true
+}
+
+// earliestCreated 返回单词中最早的创建时间，没有单词时返回fallback
+func earliestCreated(words []model.WordEntity, fallback time.Time) time.Time {
+	earliest := fallback
+	for i, word := range words {
+		if i == 0 || word.CreatedAt.Before(earliest) {
+			earliest = word.CreatedAt
+		}
+	}
+	return earliest
+}
+
+// normalizeWord 补全单词缺失的字段：ID、时间戳，以及主释义与义项列表之间的对应
+// 返回是否修改了单词
+func normalizeWord(word *model.WordEntity, now time.Time) bool {
+	changed := false
+	if word.ID == "" {
+		word.ID = newWordID()
+		changed = true
+	}
+	if word.CreatedAt.IsZero() {
+		word.CreatedAt = now
+		changed = true
+	}
+	if word.UpdatedAt.IsZero() {
+		word.UpdatedAt = word.CreatedAt
+		changed = true
+	}
+
+	switch {
+	case len(word.Senses) == 0 && word.C != "":
+		sense := model.Sense{Meaning: word.C}
+		if word.Phrase != "" {
+			sense.Examples = []string{word.Phrase}
+		}
+		word.Senses = []model.Sense{sense}
+		changed = true
+	case len(word.Senses) > 0 && word.C == "" && word.Phrase == "":
+		// 第一个义项的释义和例句也为空时单词没有变化，不能每次加载都视为修改
+		word.C = word.Senses[0].Meaning
+		if len(word.Senses[0].Examples) > 0 {
+			word.Phrase = word.Senses[0].Examples[0]
+		}
+		changed = changed || word.C != "" || word.Phrase != ""
+	}
+	return changed
+}
+
+// stampWords 为即将保存的单词补全字段，并为内容有变化的单词更新修改时间
+// previous为保存前章节中的单词，用于按ID比较内容
+func stampWords(previous, words []model.WordEntity, now time.Time) {
+	byID := make(map[string]*model.WordEntity, len(previous))
+	for i := range previous {
+		byID[previous[i].ID] = &previous[i]
+	}
+
+	for i := range words {
+		word := &words[i]
+		old, exists := byID[word.ID]
+		if word.ID == "" || !exists {
+			// 新加入的单词（可能来自其他章节），保留已有的创建时间
+			normalizeWord(word, now)
+			continue
+		}
+		normalizeWord(word, now)
+		if !sameContent(old, word) {
+			word.UpdatedAt = now
+		}
+	}
+}
+
+// sameContent 比较两个单词的内容是否相同，不比较复习状态和时间戳
+func sameContent(a, b *model.WordEntity) bool {
+	return a.W == b.W && a.C == b.C && a.Phrase == b.Phrase && a.POS == b.POS && a.Notes == b.Notes &&
+		reflect.DeepEqual(a.Senses, b.Senses) && reflect.DeepEqual(a.Tags, b.Tags)
+}
+
+// newWordID 生成单词ID
+func newWordID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		// 随机数不可用时退化为时间戳
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
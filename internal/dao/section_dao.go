@@ -2,9 +2,14 @@ package dao
 
 import (
 	"context"
+	"errors"
+
 	"github.com/ct-zh/englishLearn/model"
 )
 
+// ErrSectionConflict 章节在读取之后被其他goroutine或进程修改，本次更新被拒绝
+var ErrSectionConflict = errors.New("章节已被修改")
+
 // SectionDAOInterface 章节DAO接口
 type SectionDAOInterface interface {
 	// CreateSection 创建章节
@@ -30,4 +35,19 @@ type SectionDAOInterface interface {
 	
 	// RemoveWordFromSection 从章节移除单词
 	RemoveWordFromSection(ctx context.Context, sectionName string, wordText string) error
+
+	// UpdateWord 更新章节中的单词，word.W与wordText不同时表示修改拼写；保留单词的ID和创建时间
+	UpdateWord(ctx context.Context, sectionName string, wordText string, word model.WordEntity) error
+
+	// MoveWord 将单词移动到另一个章节，保留ID和复习状态
+	MoveWord(ctx context.Context, from string, to string, wordText string) error
+
+	// CopyWord 将单词复制到另一个章节，副本使用新的ID，不包含复习状态和错题记录
+	CopyWord(ctx context.Context, from string, to string, wordText string) error
+
+	// QueryWords 按条件查询单词，支持排序和分页；限定的章节不存在时返回错误
+	QueryWords(ctx context.Context, query *model.WordQuery) (*model.WordQueryResult, error)
+
+	// QuerySections 分页查询章节，按章节顺序返回
+	QuerySections(ctx context.Context, query *model.SectionQuery) (*model.SectionQueryResult, error)
 }
\ No newline at end of file
@@ -1,251 +1,189 @@
 package dao
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/ct-zh/englishLearn/model"
+	"github.com/ct-zh/englishLearn/pkg/utils"
 )
 
 // SectionDAOImpl 章节DAO实现
 type SectionDAOImpl struct {
+	sectionOps
+	*fileStore
 	filePath string
-	mutex    sync.RWMutex
+	mutex    sync.Mutex
 }
 
-// NewSectionDAO 创建新的SectionDAO实例
-func NewSectionDAO(dataDir string) SectionDAOInterface {
-	return &SectionDAOImpl{
-		filePath: filepath.Join(dataDir, "sections.json"),
+// NewSectionDAO 创建新的SectionDAO实例，直接读写filePath指定的数据文件
+// 每次修改都保存修改记录，见HistoryDirPath
+func NewSectionDAO(filePath string) SectionDAOInterface {
+	base := filepath.Base(filePath)
+	s := &SectionDAOImpl{
+		fileStore: newFileStore(filepath.Dir(filePath), base, "."+base+".tmp-*"),
+		filePath:  filePath,
 	}
+	s.sectionOps = sectionOps{store: s, history: newHistoryStore(HistoryDirPath(filePath))}
+	return s
 }
 
-// loadData 加载JSON数据
-func (s *SectionDAOImpl) loadData() (model.WordsDataDAO, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
-
-	// 检查文件是否存在
-	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
-		// 文件不存在，返回空数据
-		return make(model.WordsDataDAO), nil
-	}
-
-	data, err := os.ReadFile(s.filePath)
+// view 在锁内加载数据并执行只读操作
+func (s *SectionDAOImpl) view(fn func(data *model.WordsFileDAO) error) error {
+	unlock, err := s.lock(false)
 	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %w", err)
-	}
-
-	var wordsData model.WordsDataDAO
-	if len(data) == 0 {
-		// 文件为空，返回空数据
-		return make(model.WordsDataDAO), nil
+		return err
 	}
+	defer unlock()
 
-	if err := json.Unmarshal(data, &wordsData); err != nil {
-		return nil, fmt.Errorf("解析JSON失败: %w", err)
+	data, err := s.loadData()
+	if err != nil {
+		return err
 	}
-
-	return wordsData, nil
+	return fn(data)
 }
 
-// saveData 保存JSON数据
-func (s *SectionDAOImpl) saveData(data model.WordsDataDAO) error {
-	s.mutex.Lock()
-	defer s.mutex.Unlock()
-
-	// 确保目录存在
-	dir := filepath.Dir(s.filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建目录失败: %w", err)
-	}
-
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// update 在锁内完成“加载-修改-保存”，保证同一进程的多个goroutine和多个进程之间不会互相覆盖修改
+// fn返回错误时不保存
+func (s *SectionDAOImpl) update(fn func(data *model.WordsFileDAO) error) error {
+	unlock, err := s.lock(true)
 	if err != nil {
-		return fmt.Errorf("序列化JSON失败: %w", err)
-	}
-
-	if err := os.WriteFile(s.filePath, jsonData, 0644); err != nil {
-		return fmt.Errorf("写入文件失败: %w", err)
+		return err
 	}
+	defer unlock()
 
-	return nil
-}
-
-// CreateSection 创建章节
-func (s *SectionDAOImpl) CreateSection(ctx context.Context, section *model.SectionEntity) error {
 	data, err := s.loadData()
 	if err != nil {
 		return err
 	}
-
-	// 检查章节是否已存在
-	if _, exists := data[section.Name]; exists {
-		return fmt.Errorf("章节 '%s' 已存在", section.Name)
-	}
-
-	// 添加新章节
-	data[section.Name] = section.Words
-	if data[section.Name] == nil {
-		data[section.Name] = make([]model.WordEntity, 0)
+	if err := fn(data); err != nil {
+		return err
 	}
-
 	return s.saveData(data)
 }
 
-// GetSection 根据名称获取章节
-func (s *SectionDAOImpl) GetSection(ctx context.Context, name string) (*model.SectionEntity, error) {
-	data, err := s.loadData()
-	if err != nil {
-		return nil, err
-	}
+// lock 获取进程内的互斥锁和数据文件旁的锁文件
+// 只读操作在数据文件和预写日志都不存在时不创建锁文件，避免在错误的路径下留下多余的文件
+func (s *SectionDAOImpl) lock(write bool) (func(), error) {
+	s.mutex.Lock()
 
-	words, exists := data[name]
-	if !exists {
-		return nil, fmt.Errorf("章节 '%s' 不存在", name)
+	if !write && !utils.FileExists(s.filePath) && !s.hasPendingJournal() {
+		return s.mutex.Unlock, nil
 	}
 
-	return &model.SectionEntity{
-		Name:  name,
-		Words: words,
+	release, err := acquireFileLock(s.lockPath(), lockTimeout)
+	if err != nil {
+		s.mutex.Unlock()
+		return nil, err
+	}
+	return func() {
+		release()
+		s.mutex.Unlock()
 	}, nil
 }
 
-// UpdateSection 更新章节
-func (s *SectionDAOImpl) UpdateSection(ctx context.Context, name string, section *model.SectionEntity) error {
-	data, err := s.loadData()
-	if err != nil {
-		return err
+// loadData 加载JSON数据，旧格式的文件会自动迁移并写回
+// 加载前先检查并恢复上次未完成的写入，调用方需持有锁
+func (s *SectionDAOImpl) loadData() (*model.WordsFileDAO, error) {
+	if err := s.recover(); err != nil {
+		return nil, err
 	}
 
-	// 检查章节是否存在
-	if _, exists := data[name]; !exists {
-		return fmt.Errorf("章节 '%s' 不存在", name)
+	data, raw, migrated, err := s.readData()
+	if err != nil {
+		return nil, err
 	}
 
-	// 如果需要重命名章节
-	if section.Name != name {
-		// 检查新名称是否已存在
-		if _, exists := data[section.Name]; exists {
-			return fmt.Errorf("章节 '%s' 已存在", section.Name)
+	if migrated {
+		if err := s.backupLegacy(raw); err != nil {
+			return nil, err
+		}
+		if err := s.saveData(data); err != nil {
+			return nil, fmt.Errorf("保存迁移后的数据失败: %w", err)
 		}
-		// 删除旧名称，添加新名称
-		delete(data, name)
-		data[section.Name] = section.Words
-	} else {
-		// 只更新单词列表
-		data[name] = section.Words
 	}
 
-	return s.saveData(data)
+	return data, nil
 }
 
-// DeleteSection 删除章节
-func (s *SectionDAOImpl) DeleteSection(ctx context.Context, name string) error {
-	data, err := s.loadData()
+// readData 读取并解析数据文件，旧格式的数据只在内存中迁移，返回文件的原始内容和是否需要写回
+func (s *SectionDAOImpl) readData() (*model.WordsFileDAO, []byte, bool, error) {
+	raw, err := s.readFile()
 	if err != nil {
-		return err
+		return nil, nil, false, err
 	}
-
-	// 检查章节是否存在
-	if _, exists := data[name]; !exists {
-		return fmt.Errorf("章节 '%s' 不存在", name)
+	if len(raw) == 0 {
+		// 文件不存在或为空，返回空数据
+		return &model.WordsFileDAO{
+			Version:  model.CurrentSchemaVersion,
+			Sections: make([]model.SectionDAO, 0),
+		}, nil, false, nil
 	}
 
-	// 删除章节
-	delete(data, name)
-
-	return s.saveData(data)
-}
-
-// ListSections 列出所有章节
-func (s *SectionDAOImpl) ListSections(ctx context.Context) ([]model.SectionEntity, error) {
-	data, err := s.loadData()
+	data, migrated, err := decodeWordsFile(raw, time.Now())
 	if err != nil {
-		return nil, err
+		return nil, nil, false, err
 	}
-
-	sections := make([]model.SectionEntity, 0, len(data))
-	for name, words := range data {
-		sections = append(sections, model.SectionEntity{
-			Name:  name,
-			Words: words,
-		})
-	}
-
-	return sections, nil
+	return data, raw, migrated, nil
 }
 
-// SectionExists 检查章节是否存在
-func (s *SectionDAOImpl) SectionExists(ctx context.Context, name string) (bool, error) {
-	data, err := s.loadData()
-	if err != nil {
-		return false, err
+// readOnly 不加锁读取数据，不恢复未完成的写入，迁移后的数据也不写回
+func (s *SectionDAOImpl) readOnly() (*model.WordsFileDAO, error) {
+	if s.hasPendingJournal() {
+		return nil, errPendingJournal(s.filePath)
 	}
-
-	_, exists := data[name]
-	return exists, nil
+	data, _, _, err := s.readData()
+	return data, err
 }
 
-// AddWordToSection 向章节添加单词
-func (s *SectionDAOImpl) AddWordToSection(ctx context.Context, sectionName string, word model.WordEntity) error {
-	data, err := s.loadData()
-	if err != nil {
-		return err
-	}
-
-	// 检查章节是否存在
-	words, exists := data[sectionName]
-	if !exists {
-		return fmt.Errorf("章节 '%s' 不存在", sectionName)
+// readFile 读取数据文件的原始内容，文件不存在时返回nil
+func (s *SectionDAOImpl) readFile() ([]byte, error) {
+	// 检查文件是否存在
+	if _, err := os.Stat(s.filePath); os.IsNotExist(err) {
+		return nil, nil
 	}
 
-	// 检查单词是否已存在
-	for _, existingWord := range words {
-		if existingWord.W == word.W {
-			return fmt.Errorf("单词 '%s' 在章节 '%s' 中已存在", word.W, sectionName)
-		}
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
 	}
-
-	// 添加单词
-	data[sectionName] = append(words, word)
-
-	return s.saveData(data)
+	return data, nil
 }
 
-// RemoveWordFromSection 从章节移除单词
-func (s *SectionDAOImpl) RemoveWordFromSection(ctx context.Context, sectionName string, wordText string) error {
-	data, err := s.loadData()
-	if err != nil {
+// backupLegacy 迁移前备份旧版本的数据文件，已有备份时不覆盖
+func (s *SectionDAOImpl) backupLegacy(raw []byte) error {
+	version, err := detectSchemaVersion(raw)
+	if err != nil || version == model.CurrentSchemaVersion {
 		return err
 	}
 
-	// 检查章节是否存在
-	words, exists := data[sectionName]
-	if !exists {
-		return fmt.Errorf("章节 '%s' 不存在", sectionName)
+	backupPath := fmt.Sprintf("%s.v%d.bak", s.filePath, version)
+	if _, err := os.Stat(backupPath); err == nil {
+		return nil
 	}
-
-	// 查找并移除单词
-	newWords := make([]model.WordEntity, 0, len(words))
-	found := false
-	for _, word := range words {
-		if word.W != wordText {
-			newWords = append(newWords, word)
-		} else {
-			found = true
-		}
+	if err := os.WriteFile(backupPath, raw, 0644); err != nil {
+		return fmt.Errorf("备份旧版本数据文件失败: %w", err)
 	}
+	return nil
+}
 
-	if !found {
-		return fmt.Errorf("单词 '%s' 在章节 '%s' 中不存在", wordText, sectionName)
+// saveData 保存JSON数据，调用方需持有锁
+func (s *SectionDAOImpl) saveData(data *model.WordsFileDAO) error {
+	// 确保目录存在
+	dir := filepath.Dir(s.filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
 	}
 
-	data[sectionName] = newWords
+	data.Version = model.CurrentSchemaVersion
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化JSON失败: %w", err)
+	}
 
-	return s.saveData(data)
-}
\ No newline at end of file
+	return s.commit([]fileChange{{Path: filepath.Base(s.filePath), Data: jsonData}})
+}
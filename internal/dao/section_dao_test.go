@@ -1,10 +1,19 @@
 package dao
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ct-zh/englishLearn/model"
 )
@@ -14,7 +23,7 @@ func TestSectionDAO(t *testing.T) {
 	tempDir := t.TempDir()
 	
 	// 创建DAO实例
-	dao := NewSectionDAO(tempDir)
+	dao := NewSectionDAO(filepath.Join(tempDir, "sections.json"))
 	ctx := context.Background()
 
 	// 测试数据
@@ -184,7 +193,17 @@ func TestSectionDAOWithRealData(t *testing.T) {
 		t.Skip("跳过真实数据测试：数据文件不存在")
 	}
 	
-	dao := NewSectionDAO(dataDir)
+	// 复制到临时目录，避免加载时的格式迁移修改项目中的数据文件
+	raw, err := os.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("读取数据文件失败: %v", err)
+	}
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "sections.json"), raw, 0644); err != nil {
+		t.Fatalf("复制数据文件失败: %v", err)
+	}
+
+	dao := NewSectionDAO(filepath.Join(tempDir, "sections.json"))
 	ctx := context.Background()
 	
 	// 测试列出现有章节
@@ -201,4 +220,501 @@ func TestSectionDAOWithRealData(t *testing.T) {
 			}
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestSchemaMigration(t *testing.T) {
+	tempDir := t.TempDir()
+	dataFile := filepath.Join(tempDir, "sections.json")
+	legacy := `{
+  "day2": [{"W": "bid", "C": "中标", "Phrase": "They won the bid."}],
+  "day1": [{"W": "dam", "C": "水坝", "Phrase": ""}, {"W": "", "C": "", "Phrase": ""}]
+}`
+	if err := os.WriteFile(dataFile, []byte(legacy), 0644); err != nil {
+		t.Fatalf("写入旧格式数据失败: %v", err)
+	}
+
+	dao := NewSectionDAO(filepath.Join(tempDir, "sections.json"))
+	ctx := context.Background()
+
+	sections, err := dao.ListSections(ctx)
+	if err != nil {
+		t.Fatalf("加载旧格式数据失败: %v", err)
+	}
+	if len(sections) != 2 || sections[0].Name != "day1" || sections[1].Name != "day2" {
+		t.Fatalf("迁移后的章节不正确: %+v", sections)
+	}
+
+	bid := sections[1].Words[0]
+	if bid.ID == "" || bid.CreatedAt.IsZero() || !bid.UpdatedAt.Equal(bid.CreatedAt) {
+		t.Errorf("迁移后应补全ID和时间戳: %+v", bid)
+	}
+	if len(bid.Senses) != 1 || bid.Senses[0].Meaning != "中标" || len(bid.Senses[0].Examples) != 1 {
+		t.Errorf("迁移后应由释义和例句生成义项: %+v", bid.Senses)
+	}
+	if dam := sections[0].Words[0]; len(dam.Senses) != 1 || len(dam.Senses[0].Examples) != 0 {
+		t.Errorf("没有例句时义项不应包含空例句: %+v", dam.Senses)
+	}
+
+	// 迁移结果写回文件，并保留旧文件备份
+	raw, err := os.ReadFile(dataFile)
+	if err != nil {
+		t.Fatalf("读取数据文件失败: %v", err)
+	}
+	if version, err := detectSchemaVersion(raw); err != nil || version != model.CurrentSchemaVersion {
+		t.Errorf("期望文件版本为%d，实际为%d (%v)", model.CurrentSchemaVersion, version, err)
+	}
+	backup, err := os.ReadFile(dataFile + ".v1.bak")
+	if err != nil || string(backup) != legacy {
+		t.Errorf("旧格式文件备份不正确: %v", err)
+	}
+
+	// 再次加载时ID保持不变
+	section, err := dao.GetSection(ctx, "day2")
+	if err != nil {
+		t.Fatalf("获取章节失败: %v", err)
+	}
+	if section.Words[0].ID != bid.ID {
+		t.Errorf("ID应保持稳定: %s != %s", section.Words[0].ID, bid.ID)
+	}
+
+	// 复习状态变化不更新修改时间，内容变化才更新
+	section.Words[0].Review = &model.ReviewState{Reviews: 1}
+	if err := dao.UpdateSection(ctx, "day2", section); err != nil {
+		t.Fatalf("更新章节失败: %v", err)
+	}
+	section, _ = dao.GetSection(ctx, "day2")
+	if !section.Words[0].UpdatedAt.Equal(bid.UpdatedAt) {
+		t.Error("复习状态变化不应更新修改时间")
+	}
+	section.Words[0].Tags = []string{"business"}
+	if err := dao.UpdateSection(ctx, "day2", section); err != nil {
+		t.Fatalf("更新章节失败: %v", err)
+	}
+	section, _ = dao.GetSection(ctx, "day2")
+	if !section.Words[0].UpdatedAt.After(bid.UpdatedAt) {
+		t.Error("内容变化后应更新修改时间")
+	}
+
+	// 没有创建时间的章节取最早的单词创建时间，并写回文件
+	v2 := `{"version": 2, "sections": [{"name": "old", "words": [
+  {"id": "a", "W": "dam", "created_at": "2025-03-24T09:30:00Z", "updated_at": "2025-03-24T09:30:00Z"},
+  {"id": "b", "W": "bid", "created_at": "2025-03-20T09:30:00Z", "updated_at": "2025-03-20T09:30:00Z"}]}]}`
+	if err := os.WriteFile(dataFile, []byte(v2), 0644); err != nil {
+		t.Fatalf("写入数据失败: %v", err)
+	}
+	section, err = dao.GetSection(ctx, "old")
+	if err != nil || section.CreatedAt.Format(time.RFC3339) != "2025-03-20T09:30:00Z" {
+		t.Errorf("章节的创建时间不正确: %v, %v", err, section)
+	}
+	if raw, _ := os.ReadFile(dataFile); !strings.Contains(string(raw), `"created_at": "2025-03-20T09:30:00Z",`) {
+		t.Errorf("补全的创建时间应写回文件:\n%s", raw)
+	}
+
+	// 高于当前版本的文件拒绝加载
+	if err := os.WriteFile(dataFile, []byte(`{"version": 99, "sections": []}`), 0644); err != nil {
+		t.Fatalf("写入数据失败: %v", err)
+	}
+	if _, err := dao.ListSections(ctx); err == nil {
+		t.Error("不应加载高于当前版本的数据文件")
+	}
+}
+
+func TestNormalizeWord(t *testing.T) {
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		word    model.WordEntity
+		changed bool
+		c       string
+	}{
+		{"完整的单词", model.WordEntity{ID: "a", W: "dam", C: "水坝", Senses: []model.Sense{{Meaning: "水坝"}}}, false, "水坝"},
+		{"由义项补全释义", model.WordEntity{ID: "a", W: "dam", Senses: []model.Sense{{Meaning: "水坝"}}}, true, "水坝"},
+		// 义项的释义也为空时没有可补全的内容，不能每次加载都视为修改
+		{"义项释义为空", model.WordEntity{ID: "a", W: "dam", Senses: []model.Sense{{Meaning: ""}, {Meaning: "拦住"}}}, false, ""},
+		{"由释义生成义项", model.WordEntity{ID: "a", W: "dam", C: "水坝"}, true, "水坝"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			word := tt.word
+			word.CreatedAt, word.UpdatedAt = now, now
+			if changed := normalizeWord(&word, now); changed != tt.changed || word.C != tt.c {
+				t.Errorf("返回%v，释义为%q；期望%v，%q", changed, word.C, tt.changed, tt.c)
+			}
+		})
+	}
+}
+
+func TestParseNameDate(t *testing.T) {
+	cases := map[string]string{
+		"day3 2025 Feb. 22":       "2025-02-22",
+		"Day 5 - 2025.3.25":       "2025-03-25",
+		"2024-01-01":              "2024-01-01",
+		"2025年3月5日 复习":            "2025-03-05",
+		"Sept 9, 2024":            "2024-09-09",
+		"unit 7 (3rd March 2025)": "2025-03-03",
+		"day3 2025 Feb. 30":       "",
+		"day10":                   "",
+		"2025 may":                "",
+	}
+	for name, expected := range cases {
+		date, ok := parseNameDate(name)
+		actual := ""
+		if ok {
+			actual = date.Format("2006-01-02")
+		}
+		if actual != expected {
+			t.Errorf("parseNameDate(%q) = %q，期望 %q", name, actual, expected)
+		}
+	}
+}
+
+func TestCompareNatural(t *testing.T) {
+	names := []string{"Day10", "day2", "day 1", "Day2b", "day02", "unit"}
+	sort.SliceStable(names, func(i, j int) bool { return compareNatural(names[i], names[j]) < 0 })
+	if strings.Join(names, ",") != "day 1,day2,day02,Day2b,Day10,unit" {
+		t.Errorf("排序结果不正确: %v", names)
+	}
+}
+
+// failingWrite 返回一个写入函数：第n次调用时只写入一半数据并报错，模拟磁盘写满或进程崩溃
+func failingWrite(n int) func(w io.Writer, data []byte) error {
+	calls := 0
+	return func(w io.Writer, data []byte) error {
+		calls++
+		if calls == n {
+			w.Write(data[:len(data)/2])
+			return errors.New("no space left on device")
+		}
+		return writeAll(w, data)
+	}
+}
+
+func TestCrashSafeWrites(t *testing.T) {
+	ctx := context.Background()
+	words := []model.WordEntity{{W: "dam", C: "水坝"}, {W: "bid", C: "中标"}}
+
+	// setup 创建包含一个章节的数据文件，返回DAO和写入后的文件内容
+	setup := func(t *testing.T) (*SectionDAOImpl, string, []byte) {
+		tempDir := t.TempDir()
+		dao := NewSectionDAO(filepath.Join(tempDir, "sections.json")).(*SectionDAOImpl)
+		if err := dao.CreateSection(ctx, &model.SectionEntity{Name: "day1", Words: words}); err != nil {
+			t.Fatalf("创建测试章节失败: %v", err)
+		}
+		raw, err := os.ReadFile(dao.filePath)
+		if err != nil {
+			t.Fatalf("读取数据文件失败: %v", err)
+		}
+		return dao, tempDir, raw
+	}
+
+	// assertIntact 检查数据文件未被破坏，且没有残留的日志和临时文件（锁文件会一直保留）
+	// 失败的修改不应留下修改记录，修改历史中只有创建章节时的一条
+	assertIntact := func(t *testing.T, dao *SectionDAOImpl, tempDir string, expected []byte) {
+		t.Helper()
+		fresh := NewSectionDAO(filepath.Join(tempDir, "sections.json"))
+		section, err := fresh.GetSection(ctx, "day1")
+		if err != nil {
+			t.Fatalf("重新加载数据失败: %v", err)
+		}
+		if len(section.Words) != len(words) {
+			t.Errorf("期望%d个单词，实际%d个", len(words), len(section.Words))
+		}
+		raw, err := os.ReadFile(dao.filePath)
+		if err != nil {
+			t.Fatalf("读取数据文件失败: %v", err)
+		}
+		if !bytes.Equal(raw, expected) {
+			t.Error("数据文件内容被修改")
+		}
+		entries, _ := os.ReadDir(tempDir)
+		for _, entry := range entries {
+			if entry.Name() != "sections.json" && entry.Name() != "sections.json.lock" && entry.Name() != "sections.history" {
+				t.Errorf("残留文件: %s", entry.Name())
+			}
+		}
+		if history, err := fresh.(*SectionDAOImpl).ListHistory(ctx); err != nil || len(history) != 1 {
+			t.Errorf("期望1条修改记录，实际%d条: %v", len(history), err)
+		}
+	}
+
+	t.Run("DataWriteFailsHalfway", func(t *testing.T) {
+		dao, tempDir, original := setup(t)
+		dao.write = failingWrite(2) // 第1次写日志，第2次写临时文件
+
+		err := dao.AddWordToSection(ctx, "day1", model.WordEntity{W: "noxious", C: "有毒的"})
+		if err == nil {
+			t.Fatal("写入失败时应返回错误")
+		}
+		assertIntact(t, dao, tempDir, original)
+	})
+
+	t.Run("JournalWriteFailsHalfway", func(t *testing.T) {
+		dao, tempDir, original := setup(t)
+		dao.write = failingWrite(1)
+
+		if err := dao.RemoveWordFromSection(ctx, "day1", "dam"); err == nil {
+			t.Fatal("写入失败时应返回错误")
+		}
+		assertIntact(t, dao, tempDir, original)
+	})
+
+	t.Run("ReplayCompleteJournal", func(t *testing.T) {
+		dao, tempDir, original := setup(t)
+
+		// 模拟崩溃：日志已完整写入，数据文件写到一半
+		updated := bytes.Replace(original, []byte("水坝"), []byte("大坝"), 1)
+		if err := dao.writeJournal(dao.journalPath(), []fileChange{{Path: "sections.json", Data: updated}}); err != nil {
+			t.Fatalf("写入预写日志失败: %v", err)
+		}
+		if err := os.WriteFile(dao.filePath, original[:len(original)/2], 0644); err != nil {
+			t.Fatalf("截断数据文件失败: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(tempDir, ".sections.json.tmp-123"), updated[:10], 0600); err != nil {
+			t.Fatalf("写入临时文件失败: %v", err)
+		}
+
+		assertIntact(t, dao, tempDir, updated)
+		section, _ := NewSectionDAO(filepath.Join(tempDir, "sections.json")).GetSection(ctx, "day1")
+		if section.Words[0].C != "大坝" {
+			t.Errorf("应重放日志中的修改，实际释义为%s", section.Words[0].C)
+		}
+	})
+
+	t.Run("DiscardTornJournal", func(t *testing.T) {
+		dao, tempDir, original := setup(t)
+
+		// 模拟崩溃：日志只写入了一半，数据文件尚未修改
+		dao.write = failingWrite(1)
+		updated := bytes.Replace(original, []byte("水坝"), []byte("大坝"), 1)
+		if err := dao.writeJournal(dao.journalPath(), []fileChange{{Path: "sections.json", Data: updated}}); err == nil {
+			t.Fatal("期望写入日志失败")
+		}
+		if _, err := os.Stat(dao.journalPath()); err != nil {
+			t.Fatalf("期望残留不完整的日志: %v", err)
+		}
+
+		assertIntact(t, dao, tempDir, original)
+	})
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx := context.Background()
+
+	// 两个DAO实例各自有独立的进程内锁，只能依靠锁文件互斥，模拟两个进程同时修改
+	first := NewSectionDAO(filepath.Join(tempDir, "sections.json"))
+	second := NewSectionDAO(filepath.Join(tempDir, "sections.json"))
+	if err := first.CreateSection(ctx, &model.SectionEntity{Name: "day1"}); err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	t.Run("NoLostUpdates", func(t *testing.T) {
+		const perWriter = 15
+		var wg sync.WaitGroup
+		errs := make(chan error, 2*perWriter)
+		for w, dao := range []SectionDAOInterface{first, second} {
+			wg.Add(1)
+			go func(w int, dao SectionDAOInterface) {
+				defer wg.Done()
+				for i := 0; i < perWriter; i++ {
+					word := model.WordEntity{W: fmt.Sprintf("word-%d-%d", w, i), C: "释义"}
+					if err := dao.AddWordToSection(ctx, "day1", word); err != nil {
+						errs <- err
+					}
+				}
+			}(w, dao)
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("添加单词失败: %v", err)
+		}
+
+		section, err := first.GetSection(ctx, "day1")
+		if err != nil {
+			t.Fatalf("获取章节失败: %v", err)
+		}
+		if len(section.Words) != 2*perWriter {
+			t.Errorf("期望%d个单词，实际%d个，存在丢失的修改", 2*perWriter, len(section.Words))
+		}
+	})
+
+	t.Run("DetectConflict", func(t *testing.T) {
+		stale, err := first.GetSection(ctx, "day1")
+		if err != nil {
+			t.Fatalf("获取章节失败: %v", err)
+		}
+
+		// 另一个进程在读取之后修改了章节
+		if err := second.AddWordToSection(ctx, "day1", model.WordEntity{W: "dam", C: "水坝"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+
+		stale.Words = stale.Words[1:]
+		err = first.UpdateSection(ctx, "day1", stale)
+		if !errors.Is(err, ErrSectionConflict) {
+			t.Fatalf("期望返回ErrSectionConflict，实际为%v", err)
+		}
+
+		// 重新读取后可以正常更新，且同一实体可以连续更新
+		fresh, err := first.GetSection(ctx, "day1")
+		if err != nil {
+			t.Fatalf("获取章节失败: %v", err)
+		}
+		fresh.Words = fresh.Words[1:]
+		if err := first.UpdateSection(ctx, "day1", fresh); err != nil {
+			t.Fatalf("更新章节失败: %v", err)
+		}
+		fresh.Words = fresh.Words[1:]
+		if err := first.UpdateSection(ctx, "day1", fresh); err != nil {
+			t.Fatalf("连续更新章节失败: %v", err)
+		}
+	})
+}
+
+func TestDirSectionDAO(t *testing.T) {
+	ctx := context.Background()
+
+	readFile := func(t *testing.T, path string) []byte {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("读取文件失败: %v", err)
+		}
+		return raw
+	}
+	readIndex := func(t *testing.T, dir string) model.LibraryIndexDAO {
+		var index model.LibraryIndexDAO
+		if err := json.Unmarshal(readFile(t, filepath.Join(dir, model.LibraryIndexFileName)), &index); err != nil {
+			t.Fatalf("解析索引文件失败: %v", err)
+		}
+		return index
+	}
+
+	// setup 创建包含两个章节的词库目录
+	setup := func(t *testing.T) (*DirSectionDAOImpl, string) {
+		dir := t.TempDir()
+		dao := NewDirSectionDAO(dir).(*DirSectionDAOImpl)
+		if err := dao.CreateSection(ctx, &model.SectionEntity{Name: "Day 5 - 2025.3.25", Words: []model.WordEntity{{W: "dam", C: "水坝"}}}); err != nil {
+			t.Fatalf("创建章节失败: %v", err)
+		}
+		if err := dao.CreateSection(ctx, &model.SectionEntity{Name: "第二天"}); err != nil {
+			t.Fatalf("创建章节失败: %v", err)
+		}
+		return dao, dir
+	}
+
+	t.Run("Layout", func(t *testing.T) {
+		_, dir := setup(t)
+
+		index := readIndex(t, dir)
+		if len(index.Sections) != 2 || index.Version != model.CurrentSchemaVersion {
+			t.Fatalf("索引内容不正确: %+v", index)
+		}
+		if index.Sections[0].File != "day-5-2025-3-25.json" || index.Sections[1].File != "第二天.json" {
+			t.Errorf("章节文件名不正确: %s, %s", index.Sections[0].File, index.Sections[1].File)
+		}
+
+		var section model.SectionDAO
+		if err := json.Unmarshal(readFile(t, filepath.Join(dir, index.Sections[0].File)), &section); err != nil {
+			t.Fatalf("解析章节文件失败: %v", err)
+		}
+		if section.Name != "Day 5 - 2025.3.25" || len(section.Words) != 1 || section.Words[0].ID == "" {
+			t.Errorf("章节文件内容不正确: %+v", section)
+		}
+
+		// 新的实例按索引顺序读取
+		sections, err := NewDirSectionDAO(dir).ListSections(ctx)
+		if err != nil || len(sections) != 2 || sections[1].Name != "第二天" {
+			t.Fatalf("重新加载词库失败: %v, %+v", err, sections)
+		}
+	})
+
+	t.Run("OnlyChangedFilesWritten", func(t *testing.T) {
+		dao, dir := setup(t)
+		indexBefore := readFile(t, filepath.Join(dir, model.LibraryIndexFileName))
+		otherBefore := readFile(t, filepath.Join(dir, "day-5-2025-3-25.json"))
+
+		if err := dao.AddWordToSection(ctx, "第二天", model.WordEntity{W: "bid", C: "中标"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+
+		if !bytes.Equal(indexBefore, readFile(t, filepath.Join(dir, model.LibraryIndexFileName))) {
+			t.Error("添加单词不应修改索引文件")
+		}
+		if !bytes.Equal(otherBefore, readFile(t, filepath.Join(dir, "day-5-2025-3-25.json"))) {
+			t.Error("添加单词不应修改其他章节的文件")
+		}
+		section, err := dao.GetSection(ctx, "第二天")
+		if err != nil || len(section.Words) != 1 {
+			t.Fatalf("单词未保存: %v", err)
+		}
+	})
+
+	t.Run("RenameAndDelete", func(t *testing.T) {
+		dao, dir := setup(t)
+
+		section, err := dao.GetSection(ctx, "第二天")
+		if err != nil {
+			t.Fatalf("获取章节失败: %v", err)
+		}
+		section.Name = "Day 6"
+		if err := dao.UpdateSection(ctx, "第二天", section); err != nil {
+			t.Fatalf("重命名章节失败: %v", err)
+		}
+		if err := dao.DeleteSection(ctx, "Day 5 - 2025.3.25"); err != nil {
+			t.Fatalf("删除章节失败: %v", err)
+		}
+
+		index := readIndex(t, dir)
+		if len(index.Sections) != 1 || index.Sections[0].Name != "Day 6" || index.Sections[0].File != "day-6.json" {
+			t.Fatalf("索引内容不正确: %+v", index.Sections)
+		}
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if name := entry.Name(); name != "day-6.json" && name != model.LibraryIndexFileName && name != model.LibraryIndexFileName+".lock" {
+				t.Errorf("不应残留文件 %s", name)
+			}
+		}
+	})
+
+	t.Run("CompletePartialCommit", func(t *testing.T) {
+		dao, dir := setup(t)
+
+		// 第1次写日志，之后依次写章节文件和索引；第4次写入时失败，此时已有两个章节文件被修改
+		dao.write = failingWrite(4)
+		err := dao.update(func(data *model.WordsFileDAO) error {
+			data.Sections[0].Words[0].C = "大坝"
+			data.Sections[1].Words = append(data.Sections[1].Words, model.WordEntity{W: "bid", C: "中标"})
+			data.Sections = append(data.Sections, model.SectionDAO{Name: "day7", Words: []model.WordEntity{}})
+			return nil
+		})
+		if err == nil {
+			t.Fatal("写入失败时应返回错误")
+		}
+
+		// 下次加载时由预写日志完成剩余的修改
+		sections, err := NewDirSectionDAO(dir).ListSections(ctx)
+		if err != nil {
+			t.Fatalf("恢复词库失败: %v", err)
+		}
+		if len(sections) != 3 || sections[0].Words[0].C != "大坝" || len(sections[1].Words) != 1 {
+			t.Errorf("未完成剩余的修改: %+v", sections)
+		}
+		if _, err := os.Stat(dao.journalPath()); !os.IsNotExist(err) {
+			t.Error("恢复后应删除预写日志")
+		}
+	})
+
+	t.Run("Factory", func(t *testing.T) {
+		_, dir := setup(t)
+
+		factory := NewDAOFactory(dir)
+		exists, err := factory.GetSectionDAO().SectionExists(ctx, "第二天")
+		if err != nil || !exists {
+			t.Fatalf("工厂应使用目录词库: %v", err)
+		}
+		if got := SessionLogPath(dir); got != filepath.Clean(dir)+".sessions.jsonl" {
+			t.Errorf("会话日志应放在词库目录旁边，实际为%s", got)
+		}
+	})
+}
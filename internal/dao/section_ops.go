@@ -0,0 +1,610 @@
+package dao
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// sectionStore 章节数据的存储方式，负责加锁、加载和保存整个词库
+type sectionStore interface {
+	// view 在锁内加载数据并执行只读操作
+	view(fn func(data *model.WordsFileDAO) error) error
+	// update 在锁内完成“加载-修改-保存”，fn返回错误时不保存
+	update(fn func(data *model.WordsFileDAO) error) error
+}
+
+// sectionOps 基于sectionStore实现SectionDAOInterface的章节操作，单文件和目录两种存储共用
+type sectionOps struct {
+	store   sectionStore
+	history *historyStore // 保存修改历史，为nil时不保存
+}
+
+// mutate 在store.update的基础上保存修改记录
+// fn在返回之前调用record传入修改的描述和变化，参数的含义见historyRecorder.record
+// ctx来自WithHistoryBatch时，同一批修改合并为一条记录
+func (s *sectionOps) mutate(ctx context.Context, fn func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error) error {
+	recorder := newHistoryRecorder(ctx, s.history)
+	return recorder.finish(s.store.update(func(data *model.WordsFileDAO) error {
+		return fn(data, recorder.record)
+	}))
+}
+
+// sectionAction 修改历史中章节修改的描述，错题本等自动维护的章节的修改不保存记录
+func sectionAction(name, action string) string {
+	if model.IsManagedSection(name) {
+		return ""
+	}
+	return action
+}
+
+// describeSectionUpdate 描述UpdateSection做了哪些修改，只更新了复习进度时返回空
+func describeSectionUpdate(old, updated *model.SectionDAO) string {
+	var changes []string
+	if old.Name != updated.Name {
+		changes = append(changes, fmt.Sprintf("重命名章节 %s 为 %s", old.Name, updated.Name))
+	}
+	if old.Archived != updated.Archived {
+		if updated.Archived {
+			changes = append(changes, fmt.Sprintf("归档章节 %s", updated.Name))
+		} else {
+			changes = append(changes, fmt.Sprintf("取消归档章节 %s", updated.Name))
+		}
+	}
+	if wordsChanged(old.Words, updated.Words) {
+		changes = append(changes, fmt.Sprintf("修改章节 %s 的单词（%d 个 -> %d 个）", updated.Name, len(old.Words), len(updated.Words)))
+	}
+	return sectionAction(updated.Name, strings.Join(changes, "，"))
+}
+
+// createAction 等函数生成修改历史中各种修改的描述，各存储实现使用相同的描述
+func createAction(name string) string {
+	return sectionAction(name, fmt.Sprintf("创建章节 %s", name))
+}
+
+func deleteAction(name string, words int) string {
+	return fmt.Sprintf("删除章节 %s（%d 个单词）", name, words)
+}
+
+func addWordAction(section, word string) string {
+	return fmt.Sprintf("向章节 %s 添加单词 %s", section, word)
+}
+
+func removeWordAction(section, word string) string {
+	return fmt.Sprintf("从章节 %s 删除单词 %s", section, word)
+}
+
+func editWordAction(section, word string) string {
+	return fmt.Sprintf("编辑章节 %s 中的单词 %s", section, word)
+}
+
+func moveWordAction(word, from, to string) string {
+	return fmt.Sprintf("将单词 %s 从章节 %s 移动到 %s", word, from, to)
+}
+
+func copyWordAction(word, from, to string) string {
+	return fmt.Sprintf("将单词 %s 从章节 %s 复制到 %s", word, from, to)
+}
+
+func revertAction(action string) string {
+	return fmt.Sprintf("撤销“%s”", action)
+}
+
+func restoreAction(action string) string {
+	return fmt.Sprintf("撤销到“%s”之前", action)
+}
+
+// wordsChanged 判断单词列表的内容是否变化，不比较复习状态和错题记录
+func wordsChanged(old, updated []model.WordEntity) bool {
+	if len(old) != len(updated) {
+		return true
+	}
+	for i := range old {
+		if old[i].ID != updated[i].ID || !sameContent(&old[i], &updated[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// errSectionExists 章节已存在的错误，各存储实现返回相同的错误信息
+func errSectionExists(name string) error {
+	return fmt.Errorf("章节 '%s' 已存在", name)
+}
+
+// errSectionNotFound 章节不存在的错误
+func errSectionNotFound(name string) error {
+	return fmt.Errorf("章节 '%s' 不存在", name)
+}
+
+// errSectionConflict 章节在读取后已被修改的错误
+func errSectionConflict(name string) error {
+	return fmt.Errorf("%w: 章节 '%s' 在读取后已被修改，请重新加载后再试", ErrSectionConflict, name)
+}
+
+// errWordExists 单词已存在的错误
+func errWordExists(word, section string) error {
+	return fmt.Errorf("单词 '%s' 在章节 '%s' 中已存在", word, section)
+}
+
+// errWordNotFound 单词不存在的错误
+func errWordNotFound(word, section string) error {
+	return fmt.Errorf("单词 '%s' 在章节 '%s' 中不存在", word, section)
+}
+
+// findSection 查找章节的下标，不存在时返回-1
+func findSection(data *model.WordsFileDAO, name string) int {
+	for i, section := range data.Sections {
+		if section.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// toEntity 将章节转换为实体，并记录读取时的版本用于检测并发修改
+func toEntity(section model.SectionDAO) model.SectionEntity {
+	return model.SectionEntity{
+		Name:      section.Name,
+		Words:     section.Words,
+		CreatedAt: section.CreatedAt,
+		Archived:  section.Archived,
+		Revision:  sectionRevision(section.Words),
+	}
+}
+
+// sectionCreatedAt 新建章节的创建时间，实体中未指定时使用当前时间
+func sectionCreatedAt(section *model.SectionEntity, now time.Time) time.Time {
+	if section.CreatedAt.IsZero() {
+		return now
+	}
+	return section.CreatedAt
+}
+
+// sectionRevision 计算章节内容的版本标识
+func sectionRevision(words []model.WordEntity) string {
+	raw, err := json.Marshal(words)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:8])
+}
+
+// CreateSection 创建章节
+func (s *sectionOps) CreateSection(ctx context.Context, section *model.SectionEntity) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		// 检查章节是否已存在
+		if findSection(data, section.Name) >= 0 {
+			return errSectionExists(section.Name)
+		}
+
+		// 添加新章节
+		words := section.Words
+		if words == nil {
+			words = make([]model.WordEntity, 0)
+		}
+		now := time.Now()
+		stampWords(nil, words, now)
+		created := model.SectionDAO{
+			Name:      section.Name,
+			CreatedAt: sectionCreatedAt(section, now),
+			Words:     words,
+			Archived:  section.Archived,
+		}
+		if err := record(createAction(section.Name), createChanges(len(data.Sections), &created)); err != nil {
+			return err
+		}
+		data.Sections = append(data.Sections, created)
+		return nil
+	})
+}
+
+// GetSection 根据名称获取章节
+func (s *sectionOps) GetSection(ctx context.Context, name string) (*model.SectionEntity, error) {
+	var result *model.SectionEntity
+	err := s.store.view(func(data *model.WordsFileDAO) error {
+		index := findSection(data, name)
+		if index < 0 {
+			return errSectionNotFound(name)
+		}
+		entity := toEntity(data.Sections[index])
+		result = &entity
+		return nil
+	})
+	return result, err
+}
+
+// UpdateSection 更新章节
+// section.Revision不为空时，如果章节在读取之后被其他goroutine或进程修改过，返回ErrSectionConflict
+func (s *sectionOps) UpdateSection(ctx context.Context, name string, section *model.SectionEntity) error {
+	var revision string
+	err := s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		// 检查章节是否存在
+		index := findSection(data, name)
+		if index < 0 {
+			return errSectionNotFound(name)
+		}
+
+		if section.Revision != "" && section.Revision != sectionRevision(data.Sections[index].Words) {
+			return errSectionConflict(name)
+		}
+
+		// 如果需要重命名章节，检查新名称是否已存在
+		if section.Name != name && findSection(data, section.Name) >= 0 {
+			return errSectionExists(section.Name)
+		}
+
+		words := section.Words
+		if words == nil {
+			words = make([]model.WordEntity, 0)
+		}
+		old := &data.Sections[index]
+		stampWords(old.Words, words, time.Now())
+		updated := model.SectionDAO{
+			Name:      section.Name,
+			CreatedAt: old.CreatedAt,
+			Words:     words,
+			Archived:  section.Archived,
+		}
+		if err := record(describeSectionUpdate(old, &updated), updateChanges(old, &updated)); err != nil {
+			return err
+		}
+		data.Sections[index] = updated
+		revision = sectionRevision(words)
+		return nil
+	})
+	if err == nil {
+		// 更新版本，调用方可以继续使用同一个实体进行下一次修改
+		section.Revision = revision
+	}
+	return err
+}
+
+// DeleteSection 删除章节
+func (s *sectionOps) DeleteSection(ctx context.Context, name string) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		// 检查章节是否存在
+		index := findSection(data, name)
+		if index < 0 {
+			return errSectionNotFound(name)
+		}
+
+		// 删除章节
+		deleted := &data.Sections[index]
+		if err := record(deleteAction(name, len(deleted.Words)), deleteChanges(index, deleted)); err != nil {
+			return err
+		}
+		data.Sections = append(data.Sections[:index], data.Sections[index+1:]...)
+		return nil
+	})
+}
+
+// ListSections 列出所有章节，按文件中的顺序返回
+func (s *sectionOps) ListSections(ctx context.Context) ([]model.SectionEntity, error) {
+	var sections []model.SectionEntity
+	err := s.store.view(func(data *model.WordsFileDAO) error {
+		sections = make([]model.SectionEntity, 0, len(data.Sections))
+		for _, section := range data.Sections {
+			sections = append(sections, toEntity(section))
+		}
+		return nil
+	})
+	return sections, err
+}
+
+// SectionExists 检查章节是否存在
+func (s *sectionOps) SectionExists(ctx context.Context, name string) (bool, error) {
+	exists := false
+	err := s.store.view(func(data *model.WordsFileDAO) error {
+		exists = findSection(data, name) >= 0
+		return nil
+	})
+	return exists, err
+}
+
+// AddWordToSection 向章节添加单词
+func (s *sectionOps) AddWordToSection(ctx context.Context, sectionName string, word model.WordEntity) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		// 检查章节是否存在
+		index := findSection(data, sectionName)
+		if index < 0 {
+			return errSectionNotFound(sectionName)
+		}
+		words := data.Sections[index].Words
+
+		// 检查单词是否已存在
+		for _, existingWord := range words {
+			if existingWord.W == word.W {
+				return errWordExists(word.W, sectionName)
+			}
+		}
+
+		// 添加单词
+		normalizeWord(&word, time.Now())
+		if err := record(addWordAction(sectionName, word.W), []historyChange{wordChange(sectionName, len(words), nil, &word)}); err != nil {
+			return err
+		}
+		data.Sections[index].Words = append(words, word)
+		return nil
+	})
+}
+
+// RemoveWordFromSection 从章节移除单词
+func (s *sectionOps) RemoveWordFromSection(ctx context.Context, sectionName string, wordText string) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		// 检查章节是否存在
+		index := findSection(data, sectionName)
+		if index < 0 {
+			return errSectionNotFound(sectionName)
+		}
+		words := data.Sections[index].Words
+
+		// 查找并移除单词
+		newWords := make([]model.WordEntity, 0, len(words))
+		var changes []historyChange
+		for i := range words {
+			if words[i].W != wordText {
+				newWords = append(newWords, words[i])
+			} else {
+				changes = append(changes, wordChange(sectionName, i-len(changes), &words[i], nil))
+			}
+		}
+
+		if len(changes) == 0 {
+			return errWordNotFound(wordText, sectionName)
+		}
+
+		if err := record(removeWordAction(sectionName, wordText), changes); err != nil {
+			return err
+		}
+		data.Sections[index].Words = newWords
+		return nil
+	})
+}
+
+// UpdateWord 更新章节中的单词
+func (s *sectionOps) UpdateWord(ctx context.Context, sectionName string, wordText string, word model.WordEntity) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		index := findSection(data, sectionName)
+		if index < 0 {
+			return errSectionNotFound(sectionName)
+		}
+		words := data.Sections[index].Words
+
+		pos := findWord(words, wordText)
+		if pos < 0 {
+			return errWordNotFound(wordText, sectionName)
+		}
+		if word.W != wordText && findWord(words, word.W) >= 0 {
+			return errWordExists(word.W, sectionName)
+		}
+
+		edited := editedWord(words[pos], word, time.Now())
+		if err := record(editWordAction(sectionName, wordText), []historyChange{wordChange(sectionName, pos, &words[pos], &edited)}); err != nil {
+			return err
+		}
+		words[pos] = edited
+		return nil
+	})
+}
+
+// MoveWord 将单词移动到另一个章节
+func (s *sectionOps) MoveWord(ctx context.Context, from string, to string, wordText string) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		source, target, pos, err := locateTransfer(data, from, to, wordText)
+		if err != nil {
+			return err
+		}
+
+		word := source.Words[pos]
+		if err := record(moveWordAction(wordText, from, to), []historyChange{
+			wordChange(from, pos, &word, nil),
+			wordChange(to, len(target.Words), nil, &word),
+		}); err != nil {
+			return err
+		}
+		source.Words = append(source.Words[:pos:pos], source.Words[pos+1:]...)
+		target.Words = append(target.Words, word)
+		return nil
+	})
+}
+
+// CopyWord 将单词复制到另一个章节
+func (s *sectionOps) CopyWord(ctx context.Context, from string, to string, wordText string) error {
+	return s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		source, target, pos, err := locateTransfer(data, from, to, wordText)
+		if err != nil {
+			return err
+		}
+
+		copied := copiedWord(source.Words[pos], time.Now())
+		if err := record(copyWordAction(wordText, from, to), []historyChange{wordChange(to, len(target.Words), nil, &copied)}); err != nil {
+			return err
+		}
+		target.Words = append(target.Words, copied)
+		return nil
+	})
+}
+
+// locateTransfer 检查移动或复制单词的源章节、目标章节和单词，返回两个章节和单词在源章节中的下标
+func locateTransfer(data *model.WordsFileDAO, from, to, wordText string) (*model.SectionDAO, *model.SectionDAO, int, error) {
+	sourceIndex := findSection(data, from)
+	if sourceIndex < 0 {
+		return nil, nil, -1, errSectionNotFound(from)
+	}
+	targetIndex := findSection(data, to)
+	if targetIndex < 0 {
+		return nil, nil, -1, errSectionNotFound(to)
+	}
+	source, target := &data.Sections[sourceIndex], &data.Sections[targetIndex]
+
+	pos := findWord(source.Words, wordText)
+	if pos < 0 {
+		return nil, nil, -1, errWordNotFound(wordText, from)
+	}
+	// 目标章节与源章节相同时也会在这里报错
+	if findWord(target.Words, wordText) >= 0 {
+		return nil, nil, -1, errWordExists(wordText, to)
+	}
+	return source, target, pos, nil
+}
+
+// findWord 查找单词的下标，不存在时返回-1
+func findWord(words []model.WordEntity, text string) int {
+	for i := range words {
+		if words[i].W == text {
+			return i
+		}
+	}
+	return -1
+}
+
+// editedWord 根据原单词和修改后的内容生成要保存的单词：保留ID和创建时间，内容变化时更新修改时间
+func editedWord(existing, word model.WordEntity, now time.Time) model.WordEntity {
+	word.ID = existing.ID
+	word.CreatedAt = existing.CreatedAt
+	word.UpdatedAt = existing.UpdatedAt
+	normalizeWord(&word, now)
+	if !sameContent(&existing, &word) {
+		word.UpdatedAt = now
+	}
+	return word
+}
+
+// copiedWord 生成单词的副本：使用新的ID和时间，复习状态和错题记录从头开始
+func copiedWord(word model.WordEntity, now time.Time) model.WordEntity {
+	word = cloneWord(word)
+	word.ID = ""
+	word.CreatedAt = time.Time{}
+	word.UpdatedAt = time.Time{}
+	word.Review = nil
+	word.Mistake = nil
+	normalizeWord(&word, now)
+	return word
+}
+
+// QueryWords 按条件查询单词
+func (s *sectionOps) QueryWords(ctx context.Context, query *model.WordQuery) (*model.WordQueryResult, error) {
+	filter, err := newWordFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *model.WordQueryResult
+	err = s.store.view(func(data *model.WordsFileDAO) error {
+		if err := filter.checkSections(func(name string) bool { return findSection(data, name) >= 0 }); err != nil {
+			return err
+		}
+
+		var hits []wordHit
+		for i := range data.Sections {
+			section := &data.Sections[i]
+			if !filter.includes(section.Name) {
+				continue
+			}
+			for j := range section.Words {
+				word := &section.Words[j]
+				if filter.hasText() {
+					if text := newSearchText(word); !filter.match(&text) {
+						continue
+					}
+				}
+				hits = append(hits, wordHit{section: section.Name, word: word})
+			}
+		}
+		result = buildWordResult(hits, query)
+		return nil
+	})
+	return result, err
+}
+
+// QuerySections 分页查询章节
+func (s *sectionOps) QuerySections(ctx context.Context, query *model.SectionQuery) (*model.SectionQueryResult, error) {
+	if err := checkPage(query.Offset, query.Limit); err != nil {
+		return nil, err
+	}
+	if err := checkSectionSort(query.Sort); err != nil {
+		return nil, err
+	}
+
+	var result *model.SectionQueryResult
+	err := s.store.view(func(data *model.WordsFileDAO) error {
+		var matched []model.SectionDAO
+		var keys []sectionKey
+		for _, section := range data.Sections {
+			if section.Archived == query.Archived {
+				matched = append(matched, section)
+				keys = append(keys, sectionKey{name: section.Name, createdAt: section.CreatedAt})
+			}
+		}
+
+		order := sortSections(keys, query.Sort)
+		start, end := pageBounds(len(order), query.Offset, query.Limit)
+		result = &model.SectionQueryResult{
+			Sections: make([]model.SectionEntity, 0, end-start),
+			Total:    len(matched),
+		}
+		for _, i := range order[start:end] {
+			result.Sections = append(result.Sections, toEntity(matched[i]))
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ListHistory 列出保存的修改记录，最近的修改在前
+func (s *sectionOps) ListHistory(ctx context.Context) ([]model.HistoryEntry, error) {
+	if s.history == nil {
+		return nil, ErrHistoryUnsupported
+	}
+	return s.history.list()
+}
+
+// RevertHistory 只撤销id对应的一次修改；撤销同样记录在修改历史中，因此撤销也可以撤销
+func (s *sectionOps) RevertHistory(ctx context.Context, id string) (*model.HistoryEntry, error) {
+	if s.history == nil {
+		return nil, ErrHistoryUnsupported
+	}
+
+	var entry *model.HistoryEntry
+	err := s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		reverted, changes, err := s.history.revertOne(data, id)
+		if err != nil {
+			return err
+		}
+		entry = reverted
+		return record(revertAction(reverted.Action), changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// RestoreHistory 从最近一次修改开始依次撤销，直到id对应的修改；恢复同样记录在修改历史中，因此恢复也可以撤销
+func (s *sectionOps) RestoreHistory(ctx context.Context, id string) (*model.HistoryEntry, error) {
+	if s.history == nil {
+		return nil, ErrHistoryUnsupported
+	}
+
+	var entry *model.HistoryEntry
+	err := s.mutate(ctx, func(data *model.WordsFileDAO, record func(action string, changes []historyChange) error) error {
+		restored, changes, err := s.history.revertTo(data, id)
+		if err != nil {
+			return err
+		}
+		entry = restored
+		return record(restoreAction(restored.Action), changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
@@ -0,0 +1,179 @@
+package dao
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// sectionKey 章节排序用到的字段
+type sectionKey struct {
+	name      string
+	createdAt time.Time
+}
+
+// checkSectionSort 校验章节排序方式
+func checkSectionSort(mode model.SectionSortMode) error {
+	switch mode {
+	case model.SectionSortStored, model.SectionSortCreated, model.SectionSortName, model.SectionSortDate:
+		return nil
+	}
+	return fmt.Errorf("不支持的章节排序方式 '%s'", mode)
+}
+
+// sortSections 按排序方式返回章节的下标顺序，排序键相同时保持保存的顺序
+func sortSections(keys []sectionKey, mode model.SectionSortMode) []int {
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+
+	var less func(a, b int) bool
+	switch mode {
+	case model.SectionSortCreated:
+		less = func(a, b int) bool { return keys[a].createdAt.Before(keys[b].createdAt) }
+	case model.SectionSortName:
+		less = func(a, b int) bool { return compareNatural(keys[a].name, keys[b].name) < 0 }
+	case model.SectionSortDate:
+		dates := make([]time.Time, len(keys))
+		for i, key := range keys {
+			dates[i], _ = parseNameDate(key.name)
+		}
+		less = func(a, b int) bool {
+			x, y := dates[a], dates[b]
+			if x.IsZero() || y.IsZero() {
+				return !x.IsZero() && y.IsZero()
+			}
+			return x.Before(y)
+		}
+	default:
+		return order
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return less(order[i], order[j]) })
+	return order
+}
+
+// compareNatural 不区分大小写地比较两个名称，连续的数字按数值比较，返回-1、0或1
+func compareNatural(a, b string) int {
+	x, y := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	for len(x) > 0 && len(y) > 0 {
+		if unicode.IsDigit(x[0]) && unicode.IsDigit(y[0]) {
+			var m, n []rune
+			m, x = splitDigits(x)
+			n, y = splitDigits(y)
+			// 去掉前导0后先比较位数，再逐位比较，避免数字过长时溢出
+			m, n = trimZeros(m), trimZeros(n)
+			if len(m) != len(n) {
+				return compareResult(len(m) < len(n), len(m) > len(n))
+			}
+			if c := strings.Compare(string(m), string(n)); c != 0 {
+				return c
+			}
+			continue
+		}
+		if x[0] != y[0] {
+			return compareResult(x[0] < y[0], x[0] > y[0])
+		}
+		x, y = x[1:], y[1:]
+	}
+	return compareResult(len(x) < len(y), len(x) > len(y))
+}
+
+// splitDigits 拆分出开头连续的数字
+func splitDigits(s []rune) ([]rune, []rune) {
+	i := 0
+	for i < len(s) && unicode.IsDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// trimZeros 去掉数字的前导0
+func trimZeros(digits []rune) []rune {
+	for len(digits) > 1 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	return digits
+}
+
+// 章节名称中常见的日期写法
+var (
+	numericDatePattern = regexp.MustCompile(`(\d{4})\s*[-./年]\s*(\d{1,2})\s*[-./月]\s*(\d{1,2})`)       // 2025-03-25、2025.3.25、2025年3月25日
+	yearMonthPattern   = regexp.MustCompile(`(\d{4})[\s,]+([a-z]+)\.?[\s,]*(\d{1,2})(?:st|nd|rd|th)?`) // 2025 Feb. 22
+	monthDayPattern    = regexp.MustCompile(`([a-z]+)\.?\s*(\d{1,2})(?:st|nd|rd|th)?[\s,]+(\d{4})`)    // Feb. 22, 2025
+	dayMonthPattern    = regexp.MustCompile(`(\d{1,2})(?:st|nd|rd|th)?\s+([a-z]+)\.?[\s,]+(\d{4})`)    // 22 Feb 2025
+)
+
+// monthNames 英文月份名称，名称中的月份可以是至少三个字母的前缀（如 Feb、Sept）
+var monthNames = []string{"january", "february", "march", "april", "may", "june",
+	"july", "august", "september", "october", "november", "december"}
+
+// parseNameDate 从章节名称中解析日期，如 "day3 2025 Feb. 22"、"Day 5 - 2025.3.25"
+func parseNameDate(name string) (time.Time, bool) {
+	name = strings.ToLower(name)
+
+	for _, m := range numericDatePattern.FindAllStringSubmatch(name, -1) {
+		if date, ok := makeDate(m[1], monthNumber(m[2]), m[3]); ok {
+			return date, true
+		}
+	}
+	for _, m := range yearMonthPattern.FindAllStringSubmatch(name, -1) {
+		if date, ok := makeDate(m[1], monthOf(m[2]), m[3]); ok {
+			return date, true
+		}
+	}
+	for _, m := range monthDayPattern.FindAllStringSubmatch(name, -1) {
+		if date, ok := makeDate(m[3], monthOf(m[1]), m[2]); ok {
+			return date, true
+		}
+	}
+	for _, m := range dayMonthPattern.FindAllStringSubmatch(name, -1) {
+		if date, ok := makeDate(m[3], monthOf(m[2]), m[1]); ok {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// monthNumber 解析数字月份，无效时返回0
+func monthNumber(s string) int {
+	month, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return month
+}
+
+// monthOf 解析英文月份名称，无效时返回0
+func monthOf(word string) int {
+	if len(word) < 3 {
+		return 0
+	}
+	for i, name := range monthNames {
+		if strings.HasPrefix(name, word) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// makeDate 组合年月日，日期不存在（如2月30日）时返回false
+func makeDate(yearText string, month int, dayText string) (time.Time, bool) {
+	year, err1 := strconv.Atoi(yearText)
+	day, err2 := strconv.Atoi(dayText)
+	if err1 != nil || err2 != nil || month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Month(month) || date.Day() != day {
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,16 @@
+package dao
+
+import (
+	"context"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// SessionDAOInterface 练习会话日志DAO接口
+type SessionDAOInterface interface {
+	// AppendSession 追加一条练习会话记录，ID相同的记录属于同一会话
+	AppendSession(ctx context.Context, session *model.SessionEntity) error
+
+	// ListSessions 按记录顺序列出所有练习会话，同一会话的多条记录合并为一条
+	ListSessions(ctx context.Context) ([]model.SessionEntity, error)
+}
@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ct-zh/englishLearn/config"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// sessionLogSuffix 会话日志文件后缀，与数据文件放在同一目录
+const sessionLogSuffix = ".sessions.jsonl"
+
+// SessionDAOImpl 练习会话日志DAO实现
+// 日志为只追加的JSON Lines文件，每行一条会话记录；练习中逐次写入的作答记录带有相同的会话ID
+type SessionDAOImpl struct {
+	filePath string
+	mutex    sync.Mutex
+}
+
+// NewSessionDAO 创建新的SessionDAO实例
+func NewSessionDAO(filePath string) SessionDAOInterface {
+	return &SessionDAOImpl{
+		filePath: filePath,
+	}
+}
+
+// SessionLogPath 根据数据文件路径得到会话日志路径，如 data/sections.json -> data/sections.sessions.jsonl
+// 词库目录的日志放在目录旁边，如 vocab/ -> vocab.sessions.jsonl，个人的练习记录不会混入词库目录
+func SessionLogPath(dataFilePath string) string {
+	if config.IsLibraryDir(dataFilePath) {
+		return filepath.Clean(dataFilePath) + sessionLogSuffix
+	}
+	return strings.TrimSuffix(dataFilePath, filepath.Ext(dataFilePath)) + sessionLogSuffix
+}
+
+// AppendSession 追加一条练习会话记录，ID相同的记录属于同一会话
+func (s *SessionDAOImpl) AppendSession(ctx context.Context, session *model.SessionEntity) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	line, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("序列化会话记录失败: %w", err)
+	}
+
+	file, err := os.OpenFile(s.filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开会话日志失败: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("写入会话日志失败: %w", err)
+	}
+
+	return file.Sync()
+}
+
+// ListSessions 按记录顺序列出所有练习会话，同一会话的多条记录合并为一条
+func (s *SessionDAOImpl) ListSessions(ctx context.Context) ([]model.SessionEntity, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	file, err := os.Open(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []model.SessionEntity{}, nil
+		}
+		return nil, fmt.Errorf("打开会话日志失败: %w", err)
+	}
+	defer file.Close()
+
+	sessions := make([]model.SessionEntity, 0)
+	byID := make(map[string]int) // 会话ID -> 在sessions中的位置
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var session model.SessionEntity
+		if err := json.Unmarshal([]byte(line), &session); err != nil {
+			// 进程中断可能留下不完整的最后一行，跳过即可，不影响之前的记录
+			continue
+		}
+		if i, ok := byID[session.ID]; ok && session.ID != "" {
+			sessions[i].Answers = append(sessions[i].Answers, session.Answers...)
+			if session.EndedAt.After(sessions[i].EndedAt) {
+				sessions[i].EndedAt = session.EndedAt
+			}
+			continue
+		}
+		byID[session.ID] = len(sessions)
+		sessions = append(sessions, session)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取会话日志第%d行失败: %w", lineNo+1, err)
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,82 @@
+package history
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// DefaultListLimit 查看修改历史时默认列出的条数
+const DefaultListLimit = 20
+
+// Service 修改历史业务逻辑服务
+type Service struct {
+	historyDAO dao.HistoryDAOInterface
+}
+
+// NewService 创建新的修改历史服务实例
+func NewService(historyDAO dao.HistoryDAOInterface) *Service {
+	return &Service{
+		historyDAO: historyDAO,
+	}
+}
+
+// ProvideService 提供修改历史服务实例 (Wire Provider)
+func ProvideService(historyDAO dao.HistoryDAOInterface) *Service {
+	return NewService(historyDAO)
+}
+
+// ListHistory 列出最近的修改，最近的在前
+func (s *Service) ListHistory(req *model.ListHistoryRequest) (*model.ListHistoryResponse, error) {
+	entries, err := s.historyDAO.ListHistory(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.ListHistoryResponse{Entries: entries, Total: len(entries)}
+	if req.Limit > 0 && len(entries) > req.Limit {
+		resp.Entries = entries[:req.Limit]
+	}
+	return resp, nil
+}
+
+// Undo 撤销第req.Index条修改（1为最近一次）
+// 默认只撤销这一条，之后的修改保持不变，之后的修改改动过同样的单词时返回dao.ErrHistoryConflict；
+// req.All为true时从最近一次开始依次撤销到这一条，恢复到这次修改之前的状态。撤销本身也会记录在修改历史中，撤销错了可以再撤销一次
+func (s *Service) Undo(req *model.UndoRequest) (*model.UndoResponse, error) {
+	index := req.Index
+	if index == 0 {
+		index = 1
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("无效的修改序号: %d", index)
+	}
+
+	ctx := context.Background()
+	entries, err := s.historyDAO.ListHistory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("没有可以撤销的修改")
+	}
+	if index > len(entries) {
+		return nil, fmt.Errorf("只保存了 %d 条修改记录，无法撤销第 %d 条", len(entries), index)
+	}
+
+	if req.All {
+		entry, err := s.historyDAO.RestoreHistory(ctx, entries[index-1].ID)
+		if err != nil {
+			return nil, fmt.Errorf("撤销修改失败: %w", err)
+		}
+		return &model.UndoResponse{Entry: *entry, Reverted: index}, nil
+	}
+
+	entry, err := s.historyDAO.RevertHistory(ctx, entries[index-1].ID)
+	if err != nil {
+		return nil, fmt.Errorf("撤销修改失败: %w", err)
+	}
+	return &model.UndoResponse{Entry: *entry, Reverted: 1}, nil
+}
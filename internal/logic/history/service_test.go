@@ -0,0 +1,140 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+func TestUndo(t *testing.T) {
+	ctx := context.Background()
+
+	for _, name := range []string{"sections.json", "library", "sections" + model.LogStoreExt} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if filepath.Ext(name) == "" {
+				// 已存在的目录使用目录词库
+				if err := os.Mkdir(path, 0755); err != nil {
+					t.Fatalf("创建词库目录失败: %v", err)
+				}
+			}
+			daoFactory := dao.NewDAOFactory(path)
+			sectionDAO := daoFactory.GetSectionDAO()
+			service := NewService(daoFactory.GetHistoryDAO())
+
+			words := []model.WordEntity{{W: "dam", C: "水坝"}, {W: "bid", C: "中标"}}
+			if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: "day1", Words: words}); err != nil {
+				t.Fatalf("创建章节失败: %v", err)
+			}
+			if err := sectionDAO.AddWordToSection(ctx, "day1", model.WordEntity{W: "noxious", C: "有毒的"}); err != nil {
+				t.Fatalf("添加单词失败: %v", err)
+			}
+
+			// 只更新复习进度不记录到修改历史
+			section, _ := sectionDAO.GetSection(ctx, "day1")
+			section.Words[0].Review = &model.ReviewState{Reviews: 3}
+			if err := sectionDAO.UpdateSection(ctx, "day1", section); err != nil {
+				t.Fatalf("更新复习进度失败: %v", err)
+			}
+			if err := sectionDAO.DeleteSection(ctx, "day1"); err != nil {
+				t.Fatalf("删除章节失败: %v", err)
+			}
+
+			history, err := service.ListHistory(&model.ListHistoryRequest{})
+			if err != nil {
+				t.Fatalf("查看修改历史失败: %v", err)
+			}
+			var actions []string
+			for _, entry := range history.Entries {
+				actions = append(actions, entry.Action)
+			}
+			expected := []string{"删除章节 day1（3 个单词）", "向章节 day1 添加单词 noxious", "创建章节 day1"}
+			if len(actions) != len(expected) {
+				t.Fatalf("期望修改历史为%v，实际为%v", expected, actions)
+			}
+			for i := range expected {
+				if actions[i] != expected[i] {
+					t.Errorf("第%d条修改期望为%s，实际为%s", i+1, expected[i], actions[i])
+				}
+			}
+
+			// 撤销删除：章节恢复，复习进度保留删除前的状态
+			resp, err := service.Undo(&model.UndoRequest{})
+			if err != nil {
+				t.Fatalf("撤销失败: %v", err)
+			}
+			if resp.Entry.Action != expected[0] || resp.Reverted != 1 {
+				t.Errorf("撤销的修改不正确: %+v", resp)
+			}
+			restored, err := sectionDAO.GetSection(ctx, "day1")
+			if err != nil {
+				t.Fatalf("撤销后应恢复章节: %v", err)
+			}
+			if len(restored.Words) != 3 || restored.Words[0].Review == nil || restored.Words[0].Review.Reviews != 3 {
+				t.Errorf("恢复的章节内容不正确: %+v", restored.Words)
+			}
+
+			// 撤销本身也可以撤销
+			if _, err := service.Undo(&model.UndoRequest{Index: 1}); err != nil {
+				t.Fatalf("撤销“撤销”失败: %v", err)
+			}
+			if exists, _ := sectionDAO.SectionExists(ctx, "day1"); exists {
+				t.Error("再次撤销后章节应重新被删除")
+			}
+
+			// 一次撤销多条修改：回到添加单词之前
+			history, _ = service.ListHistory(&model.ListHistoryRequest{})
+			index := 0
+			for i, entry := range history.Entries {
+				if entry.Action == expected[1] {
+					index = i + 1
+				}
+			}
+			if resp, err := service.Undo(&model.UndoRequest{Index: index, All: true}); err != nil || resp.Reverted != index {
+				t.Fatalf("撤销多条修改失败: %+v, %v", resp, err)
+			}
+			restored, err = sectionDAO.GetSection(ctx, "day1")
+			if err != nil || len(restored.Words) != 2 {
+				t.Fatalf("期望恢复到添加单词之前的状态: %+v, %v", restored, err)
+			}
+
+			// 只撤销较早的一条修改，之后的修改保持不变
+			if err := sectionDAO.AddWordToSection(ctx, "day1", model.WordEntity{W: "cue", C: "提示"}); err != nil {
+				t.Fatalf("添加单词失败: %v", err)
+			}
+			if err := sectionDAO.UpdateWord(ctx, "day1", "dam", model.WordEntity{W: "dam", C: "水坝;堤坝"}); err != nil {
+				t.Fatalf("编辑单词失败: %v", err)
+			}
+			if _, err := service.Undo(&model.UndoRequest{Index: 2}); err != nil {
+				t.Fatalf("撤销添加单词失败: %v", err)
+			}
+			restored, _ = sectionDAO.GetSection(ctx, "day1")
+			if len(restored.Words) != 2 || restored.Words[0].C != "水坝;堤坝" {
+				t.Errorf("只应撤销添加单词，保留之后的编辑: %+v", restored.Words)
+			}
+
+			// 之后又编辑过同一个单词时报告冲突，不做任何修改
+			if err := sectionDAO.UpdateWord(ctx, "day1", "bid", model.WordEntity{W: "bid", C: "出价"}); err != nil {
+				t.Fatalf("编辑单词失败: %v", err)
+			}
+			if err := sectionDAO.UpdateWord(ctx, "day1", "bid", model.WordEntity{W: "bid", C: "出价；投标"}); err != nil {
+				t.Fatalf("编辑单词失败: %v", err)
+			}
+			if _, err := service.Undo(&model.UndoRequest{Index: 2}); !errors.Is(err, dao.ErrHistoryConflict) {
+				t.Errorf("撤销被之后的修改覆盖的编辑应报告冲突，实际为%v", err)
+			}
+			if bid, _ := sectionDAO.GetSection(ctx, "day1"); bid.Words[1].C != "出价；投标" {
+				t.Errorf("冲突时不应修改数据: %+v", bid.Words[1])
+			}
+
+			if _, err := service.Undo(&model.UndoRequest{Index: 100}); err == nil {
+				t.Error("序号超出修改记录数时应返回错误")
+			}
+		})
+	}
+}
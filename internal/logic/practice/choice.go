@@ -0,0 +1,243 @@
+package practice
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// 选择题方向
+const (
+	DirectionEnToCn = "en2cn" // 看英文选中文释义
+	DirectionCnToEn = "cn2en" // 看中文释义选英文
+	DirectionMixed  = "mixed" // 两种方向随机出现
+)
+
+const (
+	// choiceOptions 每道题的选项数
+	choiceOptions = 4
+	// distractorShortlist 从最相近的若干候选中随机挑选干扰项，避免每次都是同样的组合
+	distractorShortlist = 4
+)
+
+// ChoiceQuestion 选择题
+type ChoiceQuestion struct {
+	Section   string
+	Word      model.WordEntity
+	Direction string
+	Prompt    string   // 题干
+	Options   []string // 选项
+	Answer    int      // 正确选项的下标
+}
+
+// Check 检查作答是否正确，并换算为SM-2评分
+func (q *ChoiceQuestion) Check(choice int) (bool, int) {
+	if choice == q.Answer {
+		return true, review.QualityGood
+	}
+	return false, review.QualityAgain
+}
+
+// BuildChoiceQuestions 为目标单词生成四选一选择题
+// 干扰项从题库（可包含其他章节）中挑选，优先选择拼写相近、词性相同的单词，让选项更难区分
+// 缺少释义或题库不足以凑齐选项的单词会被跳过
+func BuildChoiceQuestions(targets, pool []model.ReviewQueueItem, direction string, rng *rand.Rand) []ChoiceQuestion {
+	questions := make([]ChoiceQuestion, 0, len(targets))
+	for _, target := range targets {
+		if strings.TrimSpace(target.Word.W) == "" || strings.TrimSpace(target.Word.C) == "" {
+			continue
+		}
+
+		dir := direction
+		if dir == DirectionMixed {
+			dir = DirectionEnToCn
+			if rng.Intn(2) == 1 {
+				dir = DirectionCnToEn
+			}
+		}
+
+		distractors := pickDistractors(target, pool, rng)
+		if len(distractors) < choiceOptions-1 {
+			continue
+		}
+
+		question := ChoiceQuestion{
+			Section:   target.Section,
+			Word:      target.Word,
+			Direction: dir,
+		}
+		options := make([]string, 0, choiceOptions)
+		if dir == DirectionCnToEn {
+			question.Prompt = target.Word.C
+			options = append(options, target.Word.W)
+			for _, d := range distractors {
+				options = append(options, d.W)
+			}
+		} else {
+			question.Prompt = target.Word.W
+			options = append(options, target.Word.C)
+			for _, d := range distractors {
+				options = append(options, d.C)
+			}
+		}
+
+		// 打乱选项顺序，并记录正确答案的位置
+		perm := rng.Perm(len(options))
+		question.Options = make([]string, len(options))
+		for i, p := range perm {
+			question.Options[i] = options[p]
+			if p == 0 {
+				question.Answer = i
+			}
+		}
+		questions = append(questions, question)
+	}
+	return questions
+}
+
+// pickDistractors 为目标单词挑选干扰项
+func pickDistractors(target model.ReviewQueueItem, pool []model.ReviewQueueItem, rng *rand.Rand) []model.WordEntity {
+	type candidate struct {
+		word  model.WordEntity
+		score float64
+	}
+
+	targetW := NormalizeAnswer(target.Word.W)
+	targetPOS := guessPOS(target.Word)
+	usedW := map[string]bool{targetW: true}
+	usedC := map[string]bool{strings.TrimSpace(target.Word.C): true}
+
+	var candidates []candidate
+	for _, item := range pool {
+		w := NormalizeAnswer(item.Word.W)
+		c := strings.TrimSpace(item.Word.C)
+		if w == "" || c == "" || usedW[w] || usedC[c] {
+			continue
+		}
+		usedW[w] = true
+		usedC[c] = true
+
+		score := spellingSimilarity(targetW, w)
+		if pos := guessPOS(item.Word); pos != "" && pos == targetPOS {
+			score += 0.5
+		}
+		if item.Section == target.Section {
+			score += 0.1
+		}
+		candidates = append(candidates, candidate{word: item.Word, score: score})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	// 在最相近的候选中随机挑选
+	shortlist := candidates
+	if len(shortlist) > distractorShortlist {
+		shortlist = shortlist[:distractorShortlist]
+	}
+	rng.Shuffle(len(shortlist), func(i, j int) {
+		shortlist[i], shortlist[j] = shortlist[j], shortlist[i]
+	})
+
+	result := make([]model.WordEntity, 0, choiceOptions-1)
+	for _, c := range shortlist {
+		if len(result) == choiceOptions-1 {
+			break
+		}
+		result = append(result, c.word)
+	}
+	return result
+}
+
+// spellingSimilarity 计算两个单词的拼写相似度（0-1.5），编辑距离越小、前后缀越相同得分越高
+func spellingSimilarity(a, b string) float64 {
+	longest := len([]rune(a))
+	if l := len([]rune(b)); l > longest {
+		longest = l
+	}
+	if longest == 0 {
+		return 0
+	}
+
+	score := 1 - float64(CheckSpelling(a, b).Distance)/float64(longest)
+	if commonPrefix(a, b) >= 3 {
+		score += 0.25
+	}
+	if commonPrefix(reverse(a), reverse(b)) >= 3 {
+		score += 0.25
+	}
+	return score
+}
+
+// commonPrefix 计算公共前缀的长度
+func commonPrefix(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	n := 0
+	for n < len(ra) && n < len(rb) && ra[n] == rb[n] {
+		n++
+	}
+	return n
+}
+
+// reverse 反转字符串
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// posSuffixes 根据英文后缀推测词性
+var posSuffixes = []struct {
+	suffix string
+	pos    string
+}{
+	{"tion", "n"}, {"sion", "n"}, {"ment", "n"}, {"ness", "n"}, {"ity", "n"}, {"ism", "n"},
+	{"ous", "adj"}, {"ful", "adj"}, {"ive", "adj"}, {"able", "adj"}, {"ible", "adj"}, {"al", "adj"}, {"ic", "adj"},
+	{"ly", "adv"},
+	{"ize", "v"}, {"ise", "v"}, {"ate", "v"}, {"ify", "v"},
+}
+
+// posAliases 词性标注的不同写法，统一为推测词性时使用的写法
+var posAliases = map[string]string{
+	"noun": "n", "verb": "v", "vt": "v", "vi": "v",
+	"a": "adj", "adjective": "adj",
+	"ad": "adv", "adverb": "adv",
+}
+
+// guessPOS 推测单词词性，无法判断时返回空字符串
+// 单词标注了词性时使用标注的第一个词性，否则优先根据中文释义（“…的”多为形容词，“…地”多为副词），其次根据英文后缀
+func guessPOS(word model.WordEntity) string {
+	if fields := strings.FieldsFunc(strings.ToLower(word.POS), func(r rune) bool {
+		return r == ' ' || r == '/' || r == ',' || r == '&' || r == '.'
+	}); len(fields) > 0 {
+		if pos, ok := posAliases[fields[0]]; ok {
+			return pos
+		}
+		return fields[0]
+	}
+
+	meaning := strings.TrimSpace(word.C)
+	switch {
+	case strings.HasSuffix(meaning, "的"):
+		return "adj"
+	case strings.HasSuffix(meaning, "地"):
+		return "adv"
+	}
+
+	w := NormalizeAnswer(word.W)
+	if strings.Contains(w, " ") {
+		return ""
+	}
+	for _, s := range posSuffixes {
+		if strings.HasSuffix(w, s.suffix) && len(w) > len(s.suffix)+2 {
+			return s.pos
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+package practice
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+func TestBuildChoiceQuestions(t *testing.T) {
+	pool := []model.ReviewQueueItem{
+		{Section: "day1", Word: model.WordEntity{W: "dictate", C: "口述、命令"}},
+		{Section: "day1", Word: model.WordEntity{W: "dedicate", C: "奉献"}},
+		{Section: "day1", Word: model.WordEntity{W: "indicate", C: "表明"}},
+		{Section: "day2", Word: model.WordEntity{W: "predicate", C: "谓语"}},
+		{Section: "day2", Word: model.WordEntity{W: "bleach", C: "漂白"}},
+		{Section: "day2", Word: model.WordEntity{W: "dam", C: "水坝"}},
+		{Section: "day2", Word: model.WordEntity{W: "sprint", C: "冲刺"}},
+		{Section: "day2", Word: model.WordEntity{W: "hectic", C: "忙乱的"}},
+		{Section: "day2", Word: model.WordEntity{W: "dot", C: "点"}},
+		{Section: "day2", Word: model.WordEntity{W: "noxious", C: ""}},
+	}
+	rng := rand.New(rand.NewSource(7))
+
+	t.Run("EnToCn", func(t *testing.T) {
+		questions := BuildChoiceQuestions(pool[:1], pool, DirectionEnToCn, rng)
+		if len(questions) != 1 {
+			t.Fatalf("期望生成1道题，实际%d道", len(questions))
+		}
+		q := questions[0]
+		if q.Prompt != "dictate" || len(q.Options) != 4 || q.Options[q.Answer] != "口述、命令" {
+			t.Fatalf("题目不正确: %+v", q)
+		}
+
+		// 拼写相近的单词应作为干扰项
+		similar := map[string]bool{"奉献": true, "表明": true, "谓语": true}
+		hits := 0
+		for i, option := range q.Options {
+			if i != q.Answer && similar[option] {
+				hits++
+			}
+		}
+		if hits < 2 {
+			t.Errorf("期望干扰项以拼写相近的单词为主，实际选项: %v", q.Options)
+		}
+		if correct, _ := q.Check(q.Answer); !correct {
+			t.Error("正确选项应判定为答对")
+		}
+	})
+
+	t.Run("CnToEn", func(t *testing.T) {
+		questions := BuildChoiceQuestions(pool[5:6], pool, DirectionCnToEn, rng)
+		if len(questions) != 1 {
+			t.Fatalf("期望生成1道题，实际%d道", len(questions))
+		}
+		q := questions[0]
+		if q.Prompt != "水坝" || q.Options[q.Answer] != "dam" {
+			t.Errorf("题目不正确: %+v", q)
+		}
+		seen := make(map[string]bool)
+		for _, option := range q.Options {
+			if seen[option] || option == "noxious" {
+				t.Errorf("选项重复或包含缺少释义的单词: %v", q.Options)
+			}
+			seen[option] = true
+		}
+	})
+
+	t.Run("SkipWithoutMeaning", func(t *testing.T) {
+		if questions := BuildChoiceQuestions(pool[9:], pool, DirectionMixed, rng); len(questions) != 0 {
+			t.Errorf("缺少释义的单词不应出题: %+v", questions)
+		}
+		if questions := BuildChoiceQuestions(pool[:1], pool[:3], DirectionEnToCn, rng); len(questions) != 0 {
+			t.Errorf("题库不足时不应出题: %+v", questions)
+		}
+	})
+}
+
+func TestGuessPOS(t *testing.T) {
+	tests := []struct {
+		word model.WordEntity
+		want string
+	}{
+		{model.WordEntity{W: "hectic", C: "忙乱的"}, "adj"},
+		{model.WordEntity{W: "noxious", C: "有毒"}, "adj"},
+		{model.WordEntity{W: "synthesization", C: "合成"}, "n"},
+		{model.WordEntity{W: "dictate", C: "口述、命令"}, "v"},
+		{model.WordEntity{W: "out of stock", C: "缺货"}, ""},
+		// 标注的词性优先于推测
+		{model.WordEntity{W: "national", C: "国民", POS: "n."}, "n"},
+		{model.WordEntity{W: "dam", C: "筑坝拦住", POS: "vt./n."}, "v"},
+		{model.WordEntity{W: "above", C: "在上面", POS: "prep."}, "prep"},
+	}
+	for _, tt := range tests {
+		if got := guessPOS(tt.word); got != tt.want {
+			t.Errorf("%s 期望词性 %q，实际 %q", tt.word.W, tt.want, got)
+		}
+	}
+}
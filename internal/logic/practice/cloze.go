@@ -0,0 +1,74 @@
+package practice
+
+import (
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// ClozeExercise 完形填空题
+type ClozeExercise struct {
+	Section string
+	Word    model.WordEntity
+	Prompt  string // 挖空后的例句
+	Answer  string // 例句中实际出现的形式，如 dictate 在例句中为 dictated
+}
+
+// ClozeSkip 无法生成完形填空题的单词
+type ClozeSkip struct {
+	Section string
+	Word    string
+	Reason  string
+}
+
+// ClozeResult 完形填空作答结果
+type ClozeResult struct {
+	Correct   bool
+	WrongForm bool            // 写出了原形但词形不对，如应填 dictated 却填了 dictate
+	Spelling  *SpellingResult // 与例句中形式的拼写对比
+	Quality   int             // 换算后的SM-2评分
+}
+
+// BuildClozeExercises 根据单词的例句生成完形填空题，例句中找不到目标单词的条目会被跳过并说明原因
+func BuildClozeExercises(items []model.ReviewQueueItem) ([]ClozeExercise, []ClozeSkip) {
+	exercises := make([]ClozeExercise, 0, len(items))
+	var skipped []ClozeSkip
+	for _, item := range items {
+		if strings.TrimSpace(item.Word.Phrase) == "" {
+			skipped = append(skipped, ClozeSkip{Section: item.Section, Word: item.Word.W, Reason: "没有例句"})
+			continue
+		}
+
+		start, end, ok := FindWord(item.Word.Phrase, item.Word.W)
+		if !ok {
+			skipped = append(skipped, ClozeSkip{Section: item.Section, Word: item.Word.W, Reason: "例句中未找到该单词"})
+			continue
+		}
+
+		exercises = append(exercises, ClozeExercise{
+			Section: item.Section,
+			Word:    item.Word,
+			Prompt:  item.Word.Phrase[:start] + maskPlaceholder + item.Word.Phrase[end:],
+			Answer:  item.Word.Phrase[start:end],
+		})
+	}
+	return exercises, skipped
+}
+
+// Check 检查完形填空的作答
+func (e *ClozeExercise) Check(answer string) *ClozeResult {
+	spelling := CheckSpelling(e.Answer, answer)
+	result := &ClozeResult{
+		Correct:  spelling.Correct,
+		Spelling: spelling,
+		Quality:  SpellingQuality(spelling),
+	}
+
+	// 词义记住了但词形错误，视为勉强答对
+	if !spelling.Correct && NormalizeAnswer(answer) == NormalizeAnswer(e.Word.W) {
+		result.WrongForm = true
+		result.Quality = review.QualityHard
+	}
+	return result
+}
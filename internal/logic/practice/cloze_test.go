@@ -0,0 +1,83 @@
+package practice
+
+import (
+	"testing"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+func TestInflections(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"dictate", []string{"dictates", "dictated", "dictating"}},
+		{"bid", []string{"bids", "bidding"}},
+		{"bleach", []string{"bleaches", "bleached"}},
+		{"lofty", []string{"loftier", "loftiest"}},
+		{"take", []string{"took", "taken", "taking"}},
+		{"clear out", []string{"cleared out", "clears out"}},
+	}
+
+	for _, tt := range tests {
+		forms := make(map[string]bool)
+		for _, form := range Inflections(tt.word) {
+			forms[form] = true
+		}
+		for _, want := range tt.want {
+			if !forms[want] {
+				t.Errorf("%s 的变化形式中缺少 %s", tt.word, want)
+			}
+		}
+	}
+}
+
+func TestBuildClozeExercises(t *testing.T) {
+	items := []model.ReviewQueueItem{
+		{Section: "day1", Word: model.WordEntity{W: "dictate", Phrase: "He dictated a letter to his secretary."}},
+		{Section: "day1", Word: model.WordEntity{W: "dam", Phrase: "The dam keeps the river from flooding."}},
+		{Section: "day1", Word: model.WordEntity{W: "cleared out", Phrase: "They cleared out the garage."}},
+		{Section: "day1", Word: model.WordEntity{W: "take in", Phrase: "This couple took the lost backpacker in."}},
+		{Section: "day1", Word: model.WordEntity{W: "dot"}},
+	}
+
+	exercises, skipped := BuildClozeExercises(items)
+	if len(exercises) != 3 {
+		t.Fatalf("期望生成3道题，实际%d道", len(exercises))
+	}
+	if exercises[0].Prompt != "He ____ a letter to his secretary." || exercises[0].Answer != "dictated" {
+		t.Errorf("挖空结果不正确: %+v", exercises[0])
+	}
+	if exercises[2].Answer != "cleared out" {
+		t.Errorf("多词条目挖空结果不正确: %+v", exercises[2])
+	}
+
+	if len(skipped) != 2 {
+		t.Fatalf("期望跳过2个单词，实际%d个", len(skipped))
+	}
+	if skipped[0].Word != "take in" || skipped[1].Reason != "没有例句" {
+		t.Errorf("跳过原因不正确: %+v", skipped)
+	}
+}
+
+func TestClozeCheck(t *testing.T) {
+	exercise := &ClozeExercise{
+		Word:   model.WordEntity{W: "dictate"},
+		Prompt: "He ____ a letter.",
+		Answer: "dictated",
+	}
+
+	if result := exercise.Check("Dictated"); !result.Correct || result.Quality != review.QualityEasy {
+		t.Errorf("完全正确的作答判断错误: %+v", result)
+	}
+
+	result := exercise.Check("dictate")
+	if result.Correct || !result.WrongForm || result.Quality != review.QualityHard {
+		t.Errorf("词形错误的作答判断错误: %+v", result)
+	}
+
+	if result := exercise.Check("dicated"); result.Correct || result.Spelling.Distance != 1 {
+		t.Errorf("拼写错误的作答判断错误: %+v", result)
+	}
+}
@@ -0,0 +1,179 @@
+package practice
+
+import (
+	"sort"
+	"strings"
+)
+
+// irregularForms 常见不规则动词的变化形式
+var irregularForms = map[string][]string{
+	"be":         {"am", "is", "are", "was", "were", "been", "being"},
+	"become":     {"became"},
+	"begin":      {"began", "begun"},
+	"break":      {"broke", "broken"},
+	"bring":      {"brought"},
+	"build":      {"built"},
+	"buy":        {"bought"},
+	"catch":      {"caught"},
+	"choose":     {"chose", "chosen"},
+	"come":       {"came"},
+	"do":         {"does", "did", "done"},
+	"draw":       {"drew", "drawn"},
+	"drink":      {"drank", "drunk"},
+	"drive":      {"drove", "driven"},
+	"eat":        {"ate", "eaten"},
+	"fall":       {"fell", "fallen"},
+	"feel":       {"felt"},
+	"find":       {"found"},
+	"fly":        {"flew", "flown"},
+	"forget":     {"forgot", "forgotten"},
+	"get":        {"got", "gotten"},
+	"give":       {"gave", "given"},
+	"go":         {"went", "gone"},
+	"grow":       {"grew", "grown"},
+	"have":       {"has", "had"},
+	"hear":       {"heard"},
+	"hold":       {"held"},
+	"keep":       {"kept"},
+	"know":       {"knew", "known"},
+	"lay":        {"laid"},
+	"lead":       {"led"},
+	"leave":      {"left"},
+	"lend":       {"lent"},
+	"lie":        {"lay", "lain", "lying"},
+	"lose":       {"lost"},
+	"make":       {"made"},
+	"mean":       {"meant"},
+	"meet":       {"met"},
+	"pay":        {"paid"},
+	"ride":       {"rode", "ridden"},
+	"rise":       {"rose", "risen"},
+	"run":        {"ran"},
+	"say":        {"said"},
+	"see":        {"saw", "seen"},
+	"seek":       {"sought"},
+	"sell":       {"sold"},
+	"send":       {"sent"},
+	"shake":      {"shook", "shaken"},
+	"shine":      {"shone"},
+	"sing":       {"sang", "sung"},
+	"sit":        {"sat"},
+	"sleep":      {"slept"},
+	"speak":      {"spoke", "spoken"},
+	"spend":      {"spent"},
+	"stand":      {"stood"},
+	"steal":      {"stole", "stolen"},
+	"strike":     {"struck"},
+	"swim":       {"swam", "swum"},
+	"take":       {"took", "taken"},
+	"teach":      {"taught"},
+	"tear":       {"tore", "torn"},
+	"tell":       {"told"},
+	"think":      {"thought"},
+	"throw":      {"threw", "thrown"},
+	"understand": {"understood"},
+	"wake":       {"woke", "woken"},
+	"wear":       {"wore", "worn"},
+	"win":        {"won"},
+	"write":      {"wrote", "written"},
+}
+
+// Inflections 生成单词的常见屈折变化形式（含原形），用于在例句中匹配目标单词
+// 多词条目只变化第一个单词，如 "clear out" -> "cleared out"
+func Inflections(word string) []string {
+	parts := strings.Fields(strings.ToLower(word))
+	if len(parts) == 0 {
+		return nil
+	}
+
+	rest := ""
+	if len(parts) > 1 {
+		rest = " " + strings.Join(parts[1:], " ")
+	}
+
+	forms := inflectToken(parts[0])
+	result := make([]string, 0, len(forms))
+	for _, form := range forms {
+		result = append(result, form+rest)
+	}
+	return result
+}
+
+// inflectToken 生成单个单词的变化形式，结果按长度从长到短排序，保证正则优先匹配较长的形式
+func inflectToken(w string) []string {
+	seen := map[string]bool{w: true}
+	add := func(forms ...string) {
+		for _, form := range forms {
+			seen[form] = true
+		}
+	}
+
+	n := len(w)
+	last := byte(0)
+	if n > 0 {
+		last = w[n-1]
+	}
+
+	// 名词复数、第三人称单数
+	switch {
+	case strings.HasSuffix(w, "s"), strings.HasSuffix(w, "x"), strings.HasSuffix(w, "z"),
+		strings.HasSuffix(w, "ch"), strings.HasSuffix(w, "sh"), strings.HasSuffix(w, "o"):
+		add(w + "es")
+	case last == 'y' && n > 1 && !isVowel(w[n-2]):
+		add(w[:n-1] + "ies")
+	default:
+		add(w + "s")
+	}
+
+	// 过去式、现在分词、比较级
+	switch {
+	case last == 'e':
+		add(w+"d", w[:n-1]+"ing", w+"r", w+"st")
+		if strings.HasSuffix(w, "ie") {
+			add(w[:n-2] + "ying")
+		}
+	case last == 'y' && n > 1 && !isVowel(w[n-2]):
+		stem := w[:n-1]
+		add(stem+"ied", w+"ing", stem+"ier", stem+"iest", stem+"ily")
+	default:
+		add(w+"ed", w+"ing", w+"er", w+"est")
+		if endsWithCVC(w) {
+			double := w + string(last)
+			add(double+"ed", double+"ing", double+"er", double+"est")
+		}
+	}
+
+	// 副词
+	if last != 'y' {
+		add(w + "ly")
+	}
+
+	add(irregularForms[w]...)
+
+	forms := make([]string, 0, len(seen))
+	for form := range seen {
+		forms = append(forms, form)
+	}
+	sort.Slice(forms, func(i, j int) bool {
+		if len(forms[i]) != len(forms[j]) {
+			return len(forms[i]) > len(forms[j])
+		}
+		return forms[i] < forms[j]
+	})
+	return forms
+}
+
+// isVowel 判断字母是否为元音
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+// endsWithCVC 判断单词是否以“辅音-元音-辅音”结尾（如 bid, dot），这类单词加后缀时常双写末尾辅音
+func endsWithCVC(w string) bool {
+	n := len(w)
+	if n < 3 {
+		return false
+	}
+	c1, v, c2 := w[n-3], w[n-2], w[n-1]
+	return !isVowel(c1) && isVowel(v) && !isVowel(c2) && strings.IndexByte("wxy", c2) < 0
+}
@@ -0,0 +1,50 @@
+package practice
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maskPlaceholder 遮盖单词时使用的占位符
+const maskPlaceholder = "____"
+
+// MaskWord 将例句中出现的目标单词（不区分大小写，按整词匹配，包括常见的屈折变化）替换为占位符
+// 返回遮盖后的例句以及是否找到了目标单词
+func MaskWord(phrase, word string) (string, bool) {
+	pattern := wordPattern(word)
+	if pattern == nil || phrase == "" || !pattern.MatchString(phrase) {
+		return phrase, false
+	}
+	return pattern.ReplaceAllString(phrase, maskPlaceholder), true
+}
+
+// FindWord 查找例句中第一次出现目标单词（含屈折变化）的位置，返回[start, end)
+func FindWord(phrase, word string) (int, int, bool) {
+	pattern := wordPattern(word)
+	if pattern == nil {
+		return 0, 0, false
+	}
+	loc := pattern.FindStringIndex(phrase)
+	if loc == nil {
+		return 0, 0, false
+	}
+	return loc[0], loc[1], true
+}
+
+// wordPattern 构建按整词匹配目标单词及其变化形式的正则，多词条目之间允许任意空白
+func wordPattern(word string) *regexp.Regexp {
+	forms := Inflections(word)
+	if len(forms) == 0 {
+		return nil
+	}
+
+	alternatives := make([]string, 0, len(forms))
+	for _, form := range forms {
+		parts := strings.Fields(form)
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		alternatives = append(alternatives, strings.Join(parts, `\s+`))
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(alternatives, "|") + `)\b`)
+}
@@ -0,0 +1,179 @@
+package practice
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+)
+
+// EditOp 编辑操作类型
+type EditOp int
+
+const (
+	OpMatch      EditOp = iota // 字符相同
+	OpSubstitute               // 写错了字符
+	OpInsert                   // 漏写了字符
+	OpDelete                   // 多写了字符
+	OpTranspose                // 相邻两个字符顺序颠倒
+)
+
+// Edit 一步编辑，Expected为正确答案中的字符，Actual为用户输入的字符
+type Edit struct {
+	Op       EditOp
+	Expected string
+	Actual   string
+}
+
+// SpellingResult 拼写检查结果
+type SpellingResult struct {
+	Expected string // 规范化后的正确答案
+	Actual   string // 规范化后的用户输入
+	Distance int    // Damerau-Levenshtein距离（相邻交换计为1）
+	Correct  bool
+	Edits    []Edit // 从用户输入到正确答案的逐字符对比
+}
+
+// NormalizeAnswer 规范化答案：去除首尾空白，合并连续空白，转为小写
+func NormalizeAnswer(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// CheckSpelling 比较用户输入与正确答案，支持包含空格的多词条目
+func CheckSpelling(expected, actual string) *SpellingResult {
+	expected = NormalizeAnswer(expected)
+	actual = NormalizeAnswer(actual)
+
+	edits := alignRunes([]rune(expected), []rune(actual))
+	distance := 0
+	for _, edit := range edits {
+		if edit.Op != OpMatch {
+			distance++
+		}
+	}
+
+	return &SpellingResult{
+		Expected: expected,
+		Actual:   actual,
+		Distance: distance,
+		Correct:  distance == 0,
+		Edits:    edits,
+	}
+}
+
+// alignRunes 计算受限Damerau-Levenshtein距离（OSA）并回溯出编辑序列
+func alignRunes(expected, actual []rune) []Edit {
+	n, m := len(actual), len(expected)
+	dist := make([][]int, n+1)
+	for i := range dist {
+		dist[i] = make([]int, m+1)
+		dist[i][0] = i
+	}
+	for j := 0; j <= m; j++ {
+		dist[0][j] = j
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			cost := 1
+			if actual[i-1] == expected[j-1] {
+				cost = 0
+			}
+			best := dist[i-1][j-1] + cost
+			if d := dist[i-1][j] + 1; d < best {
+				best = d
+			}
+			if d := dist[i][j-1] + 1; d < best {
+				best = d
+			}
+			if i > 1 && j > 1 && actual[i-1] == expected[j-2] && actual[i-2] == expected[j-1] {
+				if d := dist[i-2][j-2] + 1; d < best {
+					best = d
+				}
+			}
+			dist[i][j] = best
+		}
+	}
+
+	// 从右下角回溯，得到逆序的编辑序列
+	var reversed []Edit
+	i, j := n, m
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && actual[i-1] == expected[j-1] && dist[i][j] == dist[i-1][j-1]:
+			reversed = append(reversed, Edit{Op: OpMatch, Expected: string(expected[j-1]), Actual: string(actual[i-1])})
+			i, j = i-1, j-1
+		case i > 1 && j > 1 && actual[i-1] == expected[j-2] && actual[i-2] == expected[j-1] && dist[i][j] == dist[i-2][j-2]+1:
+			reversed = append(reversed, Edit{
+				Op:       OpTranspose,
+				Expected: string(expected[j-2 : j]),
+				Actual:   string(actual[i-2 : i]),
+			})
+			i, j = i-2, j-2
+		case i > 0 && j > 0 && dist[i][j] == dist[i-1][j-1]+1:
+			reversed = append(reversed, Edit{Op: OpSubstitute, Expected: string(expected[j-1]), Actual: string(actual[i-1])})
+			i, j = i-1, j-1
+		case j > 0 && dist[i][j] == dist[i][j-1]+1:
+			reversed = append(reversed, Edit{Op: OpInsert, Expected: string(expected[j-1])})
+			j--
+		default:
+			reversed = append(reversed, Edit{Op: OpDelete, Actual: string(actual[i-1])})
+			i--
+		}
+	}
+
+	edits := make([]Edit, len(reversed))
+	for k, edit := range reversed {
+		edits[len(reversed)-1-k] = edit
+	}
+	return edits
+}
+
+// Hint 以正确答案为基础标出错误位置，例如:
+// clear[+e]d 漏写, cl[-x]eared 多写, c[a→l]eared 写错, cl[ae→ea]red 顺序颠倒
+func (r *SpellingResult) Hint() string {
+	var b strings.Builder
+	for _, edit := range r.Edits {
+		switch edit.Op {
+		case OpMatch:
+			b.WriteString(edit.Expected)
+		case OpSubstitute:
+			b.WriteString("[" + visible(edit.Actual) + "→" + visible(edit.Expected) + "]")
+		case OpInsert:
+			b.WriteString("[+" + visible(edit.Expected) + "]")
+		case OpDelete:
+			b.WriteString("[-" + visible(edit.Actual) + "]")
+		case OpTranspose:
+			b.WriteString("[" + edit.Actual + "→" + edit.Expected + "]")
+		}
+	}
+	return b.String()
+}
+
+// visible 将空格显示为可见字符，方便看出多写或漏写的空格
+func visible(s string) string {
+	if strings.IndexFunc(s, unicode.IsSpace) >= 0 {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return '␣'
+			}
+			return r
+		}, s)
+	}
+	return s
+}
+
+// SpellingQuality 将拼写结果换算为SM-2评分
+// 完全正确为5分，只差一处（如漏写或颠倒一个字母）视为答错但眼熟，空白作答视为完全不记得
+func SpellingQuality(result *SpellingResult) int {
+	switch {
+	case result.Correct:
+		return review.QualityEasy
+	case result.Actual == "":
+		return review.QualityBlackout
+	case result.Distance == 1:
+		return review.QualityWrong
+	default:
+		return review.QualityAgain
+	}
+}
@@ -0,0 +1,73 @@
+package practice
+
+import (
+	"testing"
+
+	"github.com/ct-zh/englishLearn/internal/logic/review"
+)
+
+func TestCheckSpelling(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		distance int
+		hint     string
+	}{
+		{"Exact", "dam", "dam", 0, "dam"},
+		{"IgnoreCaseAndSpaces", "cleared out", "  Cleared   OUT ", 0, "cleared out"},
+		{"Missing", "cleared out", "cleard out", 1, "clear[+e]d out"},
+		{"Extra", "dam", "damn", 1, "dam[-n]"},
+		{"Substitute", "bid", "bad", 1, "b[a→i]d"},
+		{"Transpose", "palatable", "palatalbe", 1, "palata[lb→bl]e"},
+		{"MissingSpace", "take in", "takein", 1, "take[+␣]in"},
+		{"Empty", "dot", "", 3, "[+d][+o][+t]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckSpelling(tt.expected, tt.actual)
+			if result.Distance != tt.distance {
+				t.Errorf("期望距离%d，实际%d", tt.distance, result.Distance)
+			}
+			if result.Correct != (tt.distance == 0) {
+				t.Errorf("正确性判断错误: %v", result.Correct)
+			}
+			if hint := result.Hint(); hint != tt.hint {
+				t.Errorf("期望提示 %q，实际 %q", tt.hint, hint)
+			}
+		})
+	}
+}
+
+func TestSpellingQuality(t *testing.T) {
+	if q := SpellingQuality(CheckSpelling("dam", "dam")); q != review.QualityEasy {
+		t.Errorf("完全正确期望评分%d，实际%d", review.QualityEasy, q)
+	}
+	if q := SpellingQuality(CheckSpelling("dam", "dma")); q != review.QualityWrong {
+		t.Errorf("一处错误期望评分%d，实际%d", review.QualityWrong, q)
+	}
+	if q := SpellingQuality(CheckSpelling("noxious", "noshus")); q != review.QualityAgain {
+		t.Errorf("多处错误期望评分%d，实际%d", review.QualityAgain, q)
+	}
+	if q := SpellingQuality(CheckSpelling("dam", "")); q != review.QualityBlackout {
+		t.Errorf("空白作答期望评分%d，实际%d", review.QualityBlackout, q)
+	}
+}
+
+func TestMaskWord(t *testing.T) {
+	masked, ok := MaskWord("The dam keeps the river from flooding the town.", "dam")
+	if !ok || masked != "The ____ keeps the river from flooding the town." {
+		t.Errorf("遮盖结果不正确: %q", masked)
+	}
+
+	masked, ok = MaskWord("The shop has Cleared  out the old stock.", "cleared out")
+	if !ok || masked != "The shop has ____ the old stock." {
+		t.Errorf("多词条目遮盖结果不正确: %q", masked)
+	}
+
+	// 只匹配整词
+	if _, ok := MaskWord("Damage was done.", "dam"); ok {
+		t.Error("不应匹配单词的一部分")
+	}
+}
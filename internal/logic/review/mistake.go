@@ -0,0 +1,104 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// DefaultMistakeClearStreak 错题连续答对多少次后自动移出错题本
+const DefaultMistakeClearStreak = 3
+
+// SetMistakeClearStreak 设置错题移出错题本所需的连续答对次数
+func (s *Service) SetMistakeClearStreak(streak int) {
+	if streak > 0 {
+		s.mistakeClearStreak = streak
+	}
+}
+
+// trackMistake 根据作答结果维护错题本
+// 答错的单词加入错题本（已存在则累加错误次数），已在错题本中的单词连续答对足够次数后移除
+func (s *Service) trackMistake(sourceSection string, word model.WordEntity, correct bool) error {
+	ctx := context.Background()
+
+	exists, err := s.sectionDAO.SectionExists(ctx, model.MistakeSectionName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if correct {
+			return nil
+		}
+		if err := s.sectionDAO.CreateSection(ctx, &model.SectionEntity{
+			Name:  model.MistakeSectionName,
+			Words: []model.WordEntity{},
+		}); err != nil {
+			return err
+		}
+	}
+
+	notebook, err := s.sectionDAO.GetSection(ctx, model.MistakeSectionName)
+	if err != nil {
+		return err
+	}
+
+	added := false
+	index := -1
+	for i, w := range notebook.Words {
+		if w.W == word.W {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		if correct {
+			return nil
+		}
+		// 错题本中的单词单独安排复习，不沿用原章节的复习状态
+		notebook.Words = append(notebook.Words, model.WordEntity{
+			W:       word.W,
+			C:       word.C,
+			Phrase:  word.Phrase,
+			POS:     word.POS,
+			Senses:  word.Senses,
+			Mistake: &model.MistakeRecord{Source: sourceSection},
+		})
+		index = len(notebook.Words) - 1
+		added = true
+	}
+
+	cleared := s.applyMistake(&notebook.Words[index], correct)
+	if cleared {
+		notebook.Words = append(notebook.Words[:index], notebook.Words[index+1:]...)
+	}
+
+	if err := s.sectionDAO.UpdateSection(ctx, model.MistakeSectionName, notebook); err != nil {
+		return err
+	}
+
+	switch {
+	case added:
+		fmt.Printf("'%s' 已加入错题本\n", word.W)
+	case cleared:
+		fmt.Printf("✓ '%s' 已连续答对 %d 次，从错题本移除\n", word.W, s.mistakeClearStreak)
+	}
+	return nil
+}
+
+// applyMistake 更新单词的错题记录，返回是否应当移出错题本
+func (s *Service) applyMistake(word *model.WordEntity, correct bool) bool {
+	if word.Mistake == nil {
+		word.Mistake = &model.MistakeRecord{}
+	}
+
+	if !correct {
+		word.Mistake.WrongCount++
+		word.Mistake.LastWrong = s.now()
+		word.Mistake.CorrectStreak = 0
+		return false
+	}
+
+	word.Mistake.CorrectStreak++
+	return word.Mistake.CorrectStreak >= s.mistakeClearStreak
+}
@@ -0,0 +1,92 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// DefaultNewLimit 每日新词上限的默认值
+const DefaultNewLimit = 20
+
+// BuildQueue 汇总所有章节中今天到期的单词，生成复习队列
+// 到期单词按逾期时间从长到短排列，之后追加不超过每日上限的新词
+func (s *Service) BuildQueue(req *model.ReviewQueueRequest) (*model.ReviewQueueResponse, error) {
+	ctx := context.Background()
+
+	allSections, err := s.sectionDAO.ListSections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取所有章节失败: %w", err)
+	}
+
+	// 章节列表顺序不固定，按名称排序保证新词的引入顺序稳定
+	sort.Slice(allSections, func(i, j int) bool {
+		return allSections[i].Name < allSections[j].Name
+	})
+
+	now := s.now()
+	today := StartOfDay(now)
+	endOfToday := today.AddDate(0, 0, 1)
+
+	var dueItems, newItems []model.ReviewQueueItem
+	introducedToday := 0
+	for _, section := range allSections {
+		// 错题本中的单词是其他章节单词的副本，不重复加入复习队列
+		if section.Name == model.MistakeSectionName {
+			continue
+		}
+		for _, word := range section.Words {
+			if !isPracticable(word) {
+				continue
+			}
+			if word.Review.IsNew() {
+				newItems = append(newItems, model.ReviewQueueItem{
+					Section: section.Name,
+					Word:    word,
+					IsNew:   true,
+				})
+				continue
+			}
+
+			if !word.Review.FirstReview.Before(today) {
+				introducedToday++
+			}
+			if word.Review.Due.Before(endOfToday) {
+				overdue := 0
+				if word.Review.Due.Before(today) {
+					overdue = DaysBetween(word.Review.Due, today)
+				}
+				dueItems = append(dueItems, model.ReviewQueueItem{
+					Section:     section.Name,
+					Word:        word,
+					OverdueDays: overdue,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(dueItems, func(i, j int) bool {
+		return dueItems[i].Word.Review.Due.Before(dueItems[j].Word.Review.Due)
+	})
+
+	// 今天已经学过的新词计入上限
+	newQuota := req.NewLimit - introducedToday
+	if newQuota < 0 {
+		newQuota = 0
+	}
+	if len(newItems) > newQuota {
+		newItems = newItems[:newQuota]
+	}
+
+	items := make([]model.ReviewQueueItem, 0, len(dueItems)+len(newItems))
+	items = append(items, dueItems...)
+	items = append(items, newItems...)
+
+	return &model.ReviewQueueResponse{
+		Items:    items,
+		DueCount: len(dueItems),
+		NewCount: len(newItems),
+	}, nil
+}
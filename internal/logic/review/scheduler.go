@@ -0,0 +1,133 @@
+package review
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// SM-2评分，0-5分，3分及以上视为答对
+const (
+	QualityBlackout = 0 // 完全不记得
+	QualityAgain    = 1 // 答错，看到答案后想起
+	QualityWrong    = 2 // 答错，但答案很眼熟
+	QualityHard     = 3 // 答对，但很吃力
+	QualityGood     = 4 // 答对，稍有犹豫
+	QualityEasy     = 5 // 轻松答对
+
+	// passQuality 答对的最低评分
+	passQuality = QualityHard
+)
+
+const (
+	// DefaultEaseFactor 新单词的初始难度系数
+	DefaultEaseFactor = 2.5
+	// MinEaseFactor 难度系数下限
+	MinEaseFactor = 1.3
+)
+
+// Scheduler SM-2复习调度器
+type Scheduler struct{}
+
+// NewScheduler 创建SM-2调度器
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// ValidateQuality 校验评分是否在0-5之间
+func ValidateQuality(quality int) error {
+	if quality < QualityBlackout || quality > QualityEasy {
+		return fmt.Errorf("评分必须在%d-%d之间，当前为%d", QualityBlackout, QualityEasy, quality)
+	}
+	return nil
+}
+
+// IsCorrect 判断评分是否视为答对
+func IsCorrect(quality int) bool {
+	return quality >= passQuality
+}
+
+// Schedule 根据本次评分计算新的复习状态，不修改传入的状态
+func (s *Scheduler) Schedule(state *model.ReviewState, quality int, now time.Time) (*model.ReviewState, error) {
+	if err := ValidateQuality(quality); err != nil {
+		return nil, err
+	}
+
+	next := model.ReviewState{EaseFactor: DefaultEaseFactor}
+	if state != nil {
+		next = *state
+	}
+	if next.EaseFactor == 0 {
+		next.EaseFactor = DefaultEaseFactor
+	}
+	if next.FirstReview.IsZero() {
+		next.FirstReview = now
+	}
+
+	if IsCorrect(quality) {
+		switch next.Repetitions {
+		case 0:
+			next.Interval = 1
+		case 1:
+			next.Interval = 6
+		default:
+			next.Interval = int(math.Round(float64(next.Interval) * next.EaseFactor))
+		}
+		next.Repetitions++
+	} else {
+		// 答错后重新开始学习，但保留难度系数的调整
+		next.Repetitions = 0
+		next.Interval = 1
+		next.Lapses++
+	}
+
+	// EF' = EF + (0.1 - (5-q) * (0.08 + (5-q) * 0.02))
+	diff := float64(QualityEasy - quality)
+	next.EaseFactor += 0.1 - diff*(0.08+diff*0.02)
+	if next.EaseFactor < MinEaseFactor {
+		next.EaseFactor = MinEaseFactor
+	}
+
+	next.Reviews++
+	next.LastReview = now
+	next.Due = StartOfDay(now).AddDate(0, 0, next.Interval)
+
+	return &next, nil
+}
+
+// IsDue 判断单词在指定时间是否到期，未复习过的单词不算到期
+func IsDue(state *model.ReviewState, now time.Time) bool {
+	if state.IsNew() {
+		return false
+	}
+	return !state.Due.After(now)
+}
+
+// StartOfDay 返回指定时间当天的零点
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// DaysBetween 计算两个时间之间相差的自然日数
+func DaysBetween(from, to time.Time) int {
+	return int(math.Round(StartOfDay(to).Sub(StartOfDay(from)).Hours() / 24))
+}
+
+// ParseSelfGrade 将自评等级（1-4或again/hard/good/easy）转换为SM-2评分
+func ParseSelfGrade(input string) (int, bool) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "1", "again":
+		return QualityAgain, true
+	case "2", "hard":
+		return QualityHard, true
+	case "3", "good":
+		return QualityGood, true
+	case "4", "easy":
+		return QualityEasy, true
+	}
+	return 0, false
+}
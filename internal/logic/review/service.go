@@ -0,0 +1,125 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// Service 复习业务逻辑服务
+type Service struct {
+	sectionDAO dao.SectionDAOInterface
+	sessionDAO dao.SessionDAOInterface
+	scheduler  *Scheduler
+	now        func() time.Time // 当前时间，便于测试替换
+
+	mistakeClearStreak int // 连续答对多少次后从错题本移除
+}
+
+// NewService 创建新的复习服务实例
+func NewService(sectionDAO dao.SectionDAOInterface, sessionDAO dao.SessionDAOInterface) *Service {
+	return &Service{
+		sectionDAO: sectionDAO,
+		sessionDAO: sessionDAO,
+		scheduler:  NewScheduler(),
+		now:        time.Now,
+
+		mistakeClearStreak: DefaultMistakeClearStreak,
+	}
+}
+
+// ProvideService 提供复习服务实例 (Wire Provider)
+func ProvideService(sectionDAO dao.SectionDAOInterface, sessionDAO dao.SessionDAOInterface) *Service {
+	return NewService(sectionDAO, sessionDAO)
+}
+
+// maxConflictRetries 章节被其他进程修改时重新读取并重试的次数
+const maxConflictRetries = 3
+
+// RecordAnswer 记录一次作答结果，并按SM-2算法更新单词的复习状态
+func (s *Service) RecordAnswer(req *model.RecordAnswerRequest) (*model.ReviewState, error) {
+	var (
+		state   *model.ReviewState
+		word    model.WordEntity
+		cleared bool
+	)
+	err := retryOnConflict(func() error {
+		var err error
+		state, word, cleared, err = s.applyAnswer(req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	correct := IsCorrect(req.Quality)
+	if cleared {
+		fmt.Printf("✓ '%s' 已连续答对 %d 次，从错题本移除\n", word.W, s.mistakeClearStreak)
+	}
+
+	if req.Section != model.MistakeSectionName {
+		if err := retryOnConflict(func() error {
+			return s.trackMistake(req.Section, word, correct)
+		}); err != nil {
+			return nil, fmt.Errorf("更新错题本失败: %w", err)
+		}
+	}
+
+	return state, nil
+}
+
+// applyAnswer 读取章节、更新单词的复习状态并保存，cleared表示单词已从错题本中移除
+func (s *Service) applyAnswer(req *model.RecordAnswerRequest) (state *model.ReviewState, word model.WordEntity, cleared bool, err error) {
+	ctx := context.Background()
+
+	section, err := s.sectionDAO.GetSection(ctx, req.Section)
+	if err != nil {
+		return nil, word, false, fmt.Errorf("获取章节失败: %w", err)
+	}
+
+	index := -1
+	for i, w := range section.Words {
+		if w.W == req.Word {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, word, false, fmt.Errorf("单词 '%s' 在章节 '%s' 中不存在", req.Word, req.Section)
+	}
+
+	state, err = s.scheduler.Schedule(section.Words[index].Review, req.Quality, s.now())
+	if err != nil {
+		return nil, word, false, err
+	}
+	section.Words[index].Review = state
+	word = section.Words[index]
+
+	// 在错题本中练习时直接更新错题记录，避免重复读写文件
+	if req.Section == model.MistakeSectionName {
+		if s.applyMistake(&section.Words[index], IsCorrect(req.Quality)) {
+			section.Words = append(section.Words[:index], section.Words[index+1:]...)
+			cleared = true
+		}
+	}
+
+	if err := s.sectionDAO.UpdateSection(ctx, req.Section, section); err != nil {
+		return nil, word, false, fmt.Errorf("保存复习状态失败: %w", err)
+	}
+	return state, word, cleared, nil
+}
+
+// retryOnConflict 执行“读取-修改-保存”操作，章节在读取后被其他进程修改时重新执行
+func retryOnConflict(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		if err = fn(); !errors.Is(err, dao.ErrSectionConflict) {
+			return err
+		}
+	}
+	return err
+}
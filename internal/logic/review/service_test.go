@@ -0,0 +1,431 @@
+package review
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// newTestService 创建使用临时目录的复习服务
+func newTestService(t *testing.T) (dao.SectionDAOInterface, *Service) {
+	tempDir := t.TempDir()
+	dataFile := filepath.Join(tempDir, "sections.json")
+	sectionDAO := dao.NewSectionDAO(dataFile)
+	sessionDAO := dao.NewSessionDAO(dao.SessionLogPath(dataFile))
+	return sectionDAO, NewService(sectionDAO, sessionDAO)
+}
+
+func TestScheduler(t *testing.T) {
+	scheduler := NewScheduler()
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.Local)
+
+	t.Run("NewWordGood", func(t *testing.T) {
+		state, err := scheduler.Schedule(nil, QualityGood, now)
+		if err != nil {
+			t.Fatalf("调度失败: %v", err)
+		}
+		if state.Interval != 1 || state.Repetitions != 1 {
+			t.Errorf("期望间隔1天、连续答对1次，实际间隔%d天、连续答对%d次", state.Interval, state.Repetitions)
+		}
+		if state.EaseFactor != DefaultEaseFactor {
+			t.Errorf("评分4时难度系数不应变化，实际为%.2f", state.EaseFactor)
+		}
+		expectedDue := time.Date(2025, 3, 26, 0, 0, 0, 0, time.Local)
+		if !state.Due.Equal(expectedDue) {
+			t.Errorf("期望到期日为%v，实际为%v", expectedDue, state.Due)
+		}
+		if !state.FirstReview.Equal(now) {
+			t.Errorf("首次复习时间未记录")
+		}
+	})
+
+	t.Run("IntervalProgression", func(t *testing.T) {
+		var state *model.ReviewState
+		expected := []int{1, 6, 15, 38}
+		for i, interval := range expected {
+			next, err := scheduler.Schedule(state, QualityGood, now)
+			if err != nil {
+				t.Fatalf("调度失败: %v", err)
+			}
+			if next.Interval != interval {
+				t.Errorf("第%d次复习期望间隔%d天，实际%d天", i+1, interval, next.Interval)
+			}
+			state = next
+		}
+		if state.Reviews != len(expected) {
+			t.Errorf("期望累计作答%d次，实际%d次", len(expected), state.Reviews)
+		}
+	})
+
+	t.Run("Lapse", func(t *testing.T) {
+		state := &model.ReviewState{EaseFactor: 2.5, Interval: 15, Repetitions: 3, Reviews: 3}
+		next, err := scheduler.Schedule(state, QualityAgain, now)
+		if err != nil {
+			t.Fatalf("调度失败: %v", err)
+		}
+		if next.Repetitions != 0 || next.Interval != 1 || next.Lapses != 1 {
+			t.Errorf("答错后应重置: %+v", next)
+		}
+		if next.EaseFactor >= 2.5 {
+			t.Errorf("答错后难度系数应降低，实际为%.2f", next.EaseFactor)
+		}
+		if state.Repetitions != 3 {
+			t.Error("调度不应修改传入的状态")
+		}
+	})
+
+	t.Run("MinEaseFactor", func(t *testing.T) {
+		state := &model.ReviewState{EaseFactor: MinEaseFactor}
+		next, err := scheduler.Schedule(state, QualityBlackout, now)
+		if err != nil {
+			t.Fatalf("调度失败: %v", err)
+		}
+		if next.EaseFactor != MinEaseFactor {
+			t.Errorf("难度系数不应低于%.1f，实际为%.2f", MinEaseFactor, next.EaseFactor)
+		}
+	})
+
+	t.Run("InvalidQuality", func(t *testing.T) {
+		if _, err := scheduler.Schedule(nil, 6, now); err == nil {
+			t.Error("期望评分超出范围时返回错误")
+		}
+	})
+}
+
+func TestRecordAnswer(t *testing.T) {
+	sectionDAO, service := newTestService(t)
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	err := sectionDAO.CreateSection(ctx, &model.SectionEntity{
+		Name:  "day1",
+		Words: []model.WordEntity{{W: "dam", C: "水坝"}, {W: "bid", C: "中标"}},
+	})
+	if err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	state, err := service.RecordAnswer(&model.RecordAnswerRequest{Section: "day1", Word: "dam", Quality: QualityEasy})
+	if err != nil {
+		t.Fatalf("记录作答失败: %v", err)
+	}
+	if state.Repetitions != 1 {
+		t.Errorf("期望连续答对1次，实际%d次", state.Repetitions)
+	}
+
+	// 复习状态应被持久化
+	section, err := sectionDAO.GetSection(ctx, "day1")
+	if err != nil {
+		t.Fatalf("获取章节失败: %v", err)
+	}
+	if section.Words[0].Review == nil || !section.Words[0].Review.Due.Equal(state.Due) {
+		t.Errorf("复习状态未保存: %+v", section.Words[0].Review)
+	}
+	if section.Words[1].Review != nil {
+		t.Error("未作答的单词不应有复习状态")
+	}
+
+	if _, err := service.RecordAnswer(&model.RecordAnswerRequest{Section: "day1", Word: "missing", Quality: QualityGood}); err == nil {
+		t.Error("期望单词不存在时返回错误")
+	}
+}
+
+func TestBuildQueue(t *testing.T) {
+	sectionDAO, service := newTestService(t)
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	day := func(offset int) time.Time {
+		return time.Date(2025, 3, 25+offset, 0, 0, 0, 0, time.Local)
+	}
+
+	ctx := context.Background()
+	sectionsData := []*model.SectionEntity{
+		{Name: "day1", Words: []model.WordEntity{
+			{W: "dam", C: "水坝", Review: &model.ReviewState{Reviews: 2, Due: day(-1), FirstReview: day(-10)}},
+			{W: "bid", C: "中标", Review: &model.ReviewState{Reviews: 3, Due: day(3), FirstReview: day(-10)}},
+			{W: "lofty", C: "崇高的"},
+		}},
+		{Name: "day2", Words: []model.WordEntity{
+			{W: "dot", C: "点", Review: &model.ReviewState{Reviews: 1, Due: day(-5), FirstReview: day(-6)}},
+			{W: "sprint", C: "冲刺", Review: &model.ReviewState{Reviews: 1, Due: day(0), FirstReview: now}},
+			{W: "hectic", C: "忙乱的"},
+			{W: "solemn", C: "庄严的"},
+		}},
+	}
+	for _, section := range sectionsData {
+		if err := sectionDAO.CreateSection(ctx, section); err != nil {
+			t.Fatalf("创建测试章节失败: %v", err)
+		}
+	}
+
+	t.Run("DueOrderedByOverdue", func(t *testing.T) {
+		resp, err := service.BuildQueue(&model.ReviewQueueRequest{NewLimit: 0})
+		if err != nil {
+			t.Fatalf("构建复习队列失败: %v", err)
+		}
+		expected := []string{"dot", "dam", "sprint"}
+		if len(resp.Items) != len(expected) {
+			t.Fatalf("期望%d个到期单词，实际%d个", len(expected), len(resp.Items))
+		}
+		for i, w := range expected {
+			if resp.Items[i].Word.W != w {
+				t.Errorf("第%d项期望为%s，实际为%s", i+1, w, resp.Items[i].Word.W)
+			}
+		}
+		if resp.Items[0].OverdueDays != 5 || resp.Items[2].OverdueDays != 0 {
+			t.Errorf("逾期天数计算错误: %d, %d", resp.Items[0].OverdueDays, resp.Items[2].OverdueDays)
+		}
+		if resp.Items[0].Section != "day2" {
+			t.Errorf("期望记录来源章节day2，实际为%s", resp.Items[0].Section)
+		}
+	})
+
+	t.Run("NewLimitCountsIntroducedToday", func(t *testing.T) {
+		// sprint今天首次复习，占用一个新词名额
+		resp, err := service.BuildQueue(&model.ReviewQueueRequest{NewLimit: 2})
+		if err != nil {
+			t.Fatalf("构建复习队列失败: %v", err)
+		}
+		if resp.NewCount != 1 {
+			t.Fatalf("期望1个新词，实际%d个", resp.NewCount)
+		}
+		last := resp.Items[len(resp.Items)-1]
+		if !last.IsNew || last.Word.W != "lofty" {
+			t.Errorf("新词应排在到期单词之后，实际为%+v", last)
+		}
+	})
+}
+
+func TestSession(t *testing.T) {
+	sectionDAO, service := newTestService(t)
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	err := sectionDAO.CreateSection(ctx, &model.SectionEntity{
+		Name:  "day1",
+		Words: []model.WordEntity{{W: "dam", C: "水坝"}, {W: "bid", C: "中标"}},
+	})
+	if err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	// 没有作答的会话不写入日志
+	if err := service.StartSession("day1", model.SessionModeFlashcard).Finish(); err != nil {
+		t.Fatalf("结束会话失败: %v", err)
+	}
+
+	session := service.StartSession("day1", model.SessionModeFlashcard)
+	if _, err := session.Answer("day1", "dam", QualityGood, 1500*time.Millisecond); err != nil {
+		t.Fatalf("记录作答失败: %v", err)
+	}
+	// 作答后立即写入日志，程序中途退出也不会丢失
+	if sessions, _ := service.sessionDAO.ListSessions(ctx); len(sessions) != 1 || len(sessions[0].Answers) != 1 {
+		t.Fatalf("作答后应立即写入会话日志: %+v", sessions)
+	}
+	if _, err := session.Answer("day1", "bid", QualityAgain, 4*time.Second); err != nil {
+		t.Fatalf("记录作答失败: %v", err)
+	}
+	if err := session.Finish(); err != nil {
+		t.Fatalf("结束会话失败: %v", err)
+	}
+
+	sessions, err := service.sessionDAO.ListSessions(ctx)
+	if err != nil {
+		t.Fatalf("读取会话日志失败: %v", err)
+	}
+	// 逐次写入的记录合并为同一会话
+	if len(sessions) != 1 {
+		t.Fatalf("期望1条会话记录，实际%d条", len(sessions))
+	}
+	answers := sessions[0].Answers
+	if len(answers) != 2 || !answers[0].Correct || answers[1].Correct {
+		t.Errorf("作答记录不正确: %+v", answers)
+	}
+	if len(answers) == 2 && (answers[0].ResponseMS != 1500 || answers[1].ResponseMS != 4000) {
+		t.Errorf("作答用时不正确: %+v", answers)
+	}
+	if sessions[0].Mode != model.SessionModeFlashcard || sessions[0].Section != "day1" {
+		t.Errorf("会话信息不正确: %+v", sessions[0])
+	}
+}
+
+func TestMistakeNotebook(t *testing.T) {
+	sectionDAO, service := newTestService(t)
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+	service.SetMistakeClearStreak(2)
+
+	ctx := context.Background()
+	err := sectionDAO.CreateSection(ctx, &model.SectionEntity{
+		Name:  "day1",
+		Words: []model.WordEntity{{W: "dam", C: "水坝"}, {W: "bid", C: "中标"}},
+	})
+	if err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	answer := func(section, word string, quality int) {
+		t.Helper()
+		if _, err := service.RecordAnswer(&model.RecordAnswerRequest{Section: section, Word: word, Quality: quality}); err != nil {
+			t.Fatalf("记录作答失败: %v", err)
+		}
+	}
+	notebookWords := func() []model.WordEntity {
+		t.Helper()
+		exists, err := sectionDAO.SectionExists(ctx, model.MistakeSectionName)
+		if err != nil {
+			t.Fatalf("检查错题本失败: %v", err)
+		}
+		if !exists {
+			return nil
+		}
+		section, err := sectionDAO.GetSection(ctx, model.MistakeSectionName)
+		if err != nil {
+			t.Fatalf("获取错题本失败: %v", err)
+		}
+		return section.Words
+	}
+
+	// 答对不会创建错题本
+	answer("day1", "bid", QualityGood)
+	if words := notebookWords(); len(words) != 0 {
+		t.Fatalf("答对的单词不应加入错题本: %+v", words)
+	}
+
+	// 答错两次，错误次数累加
+	answer("day1", "dam", QualityAgain)
+	now = now.Add(time.Hour)
+	answer("day1", "dam", QualityBlackout)
+	words := notebookWords()
+	if len(words) != 1 || words[0].W != "dam" || words[0].Mistake == nil {
+		t.Fatalf("错题本内容不正确: %+v", words)
+	}
+	if words[0].Mistake.WrongCount != 2 || !words[0].Mistake.LastWrong.Equal(now) || words[0].Mistake.Source != "day1" {
+		t.Errorf("错题记录不正确: %+v", words[0].Mistake)
+	}
+	if words[0].Review != nil {
+		t.Error("错题本中的单词应单独安排复习")
+	}
+
+	// 在错题本中答对一次后又答错，连续答对次数重置
+	answer(model.MistakeSectionName, "dam", QualityGood)
+	answer(model.MistakeSectionName, "dam", QualityAgain)
+	words = notebookWords()
+	if len(words) != 1 || words[0].Mistake.WrongCount != 3 || words[0].Mistake.CorrectStreak != 0 {
+		t.Fatalf("错题记录不正确: %+v", words)
+	}
+
+	// 在原章节和错题本中各答对一次，达到连续答对次数后移除
+	answer("day1", "dam", QualityGood)
+	answer(model.MistakeSectionName, "dam", QualityEasy)
+	if words := notebookWords(); len(words) != 0 {
+		t.Errorf("连续答对后应从错题本移除: %+v", words)
+	}
+
+	// 错题本不参与今日复习队列
+	answer("day1", "bid", QualityAgain)
+	resp, err := service.BuildQueue(&model.ReviewQueueRequest{NewLimit: DefaultNewLimit})
+	if err != nil {
+		t.Fatalf("构建复习队列失败: %v", err)
+	}
+	for _, item := range resp.Items {
+		if item.Section == model.MistakeSectionName {
+			t.Errorf("复习队列中不应包含错题本: %+v", item)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	_, service := newTestService(t)
+	now := time.Date(2025, 3, 25, 9, 30, 0, 0, time.Local)
+	service.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	answer := func(daysAgo int, section, word string, correct bool, responseMS int64) model.AnswerRecord {
+		return model.AnswerRecord{
+			Section:    section,
+			Word:       word,
+			Correct:    correct,
+			ResponseMS: responseMS,
+			AnsweredAt: now.AddDate(0, 0, -daysAgo),
+		}
+	}
+	sessions := []*model.SessionEntity{
+		{Mode: model.SessionModeDictation, Answers: []model.AnswerRecord{
+			answer(3, "day1", "dam", false, 5000),
+			answer(3, "day1", "bid", true, 1000),
+		}},
+		{Mode: model.SessionModeChoice, Answers: []model.AnswerRecord{
+			answer(1, "day1", "dam", false, 3000),
+			answer(1, "day2", "clear out", true, 0),
+		}},
+		{Mode: model.SessionModeFlashcard, Answers: []model.AnswerRecord{
+			answer(0, "day1", "dam", true, 2000),
+			answer(0, "day1", "dam", true, 2000),
+			answer(0, "day1", "bid", false, 4000),
+		}},
+	}
+	for _, session := range sessions {
+		if err := service.sessionDAO.AppendSession(ctx, session); err != nil {
+			t.Fatalf("写入会话日志失败: %v", err)
+		}
+	}
+
+	resp, err := service.Stats(&model.StatsRequest{Days: 4, Top: 2})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+
+	if resp.Sessions != 3 || resp.Answers != 7 || resp.Correct != 4 {
+		t.Errorf("总计不正确: %+v", resp)
+	}
+
+	if len(resp.Sections) != 2 || resp.Sections[0].Section != "day1" || resp.Sections[0].Correct != 3 || resp.Sections[0].Answers != 6 {
+		t.Errorf("章节统计不正确: %+v", resp.Sections)
+	}
+
+	if len(resp.HardestWords) != 2 {
+		t.Fatalf("期望列出2个单词，实际%d个", len(resp.HardestWords))
+	}
+	hardest := resp.HardestWords[0]
+	if hardest.Word != "dam" || hardest.Wrong != 2 || hardest.Answers != 4 || hardest.AvgResponse != 3000 {
+		t.Errorf("最难的单词不正确: %+v", hardest)
+	}
+
+	expectedDaily := []model.DailyStats{
+		{Date: "2025-03-22", Words: 2, Answers: 2, Correct: 1},
+		{Date: "2025-03-23"},
+		{Date: "2025-03-24", Words: 2, Answers: 2, Correct: 1},
+		{Date: "2025-03-25", Words: 2, Answers: 3, Correct: 2},
+	}
+	if len(resp.Daily) != len(expectedDaily) {
+		t.Fatalf("期望统计%d天，实际%d天", len(expectedDaily), len(resp.Daily))
+	}
+	for i, day := range expectedDaily {
+		if resp.Daily[i] != day {
+			t.Errorf("第%d天统计不正确: 期望%+v，实际%+v", i+1, day, resp.Daily[i])
+		}
+	}
+
+	// 3月23日没有练习，连续学习天数从24日算起
+	if resp.Streak != 2 {
+		t.Errorf("期望连续学习2天，实际%d天", resp.Streak)
+	}
+
+	// 今天还没练习时，连续天数从昨天算起
+	now = now.AddDate(0, 0, 1)
+	resp, err = service.Stats(&model.StatsRequest{})
+	if err != nil {
+		t.Fatalf("统计失败: %v", err)
+	}
+	if resp.Streak != 2 {
+		t.Errorf("今天还没练习时期望连续学习2天，实际%d天", resp.Streak)
+	}
+}
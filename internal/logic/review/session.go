@@ -0,0 +1,167 @@
+package review
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// Session 一次练习会话，每次作答后立即写入会话日志，程序中途退出也不会丢失已作答的记录
+type Session struct {
+	service *Service
+	entity  model.SessionEntity
+	saved   int // 已写入会话日志的作答数
+}
+
+// StartSession 开始一次练习会话，section为空表示跨章节练习
+func (s *Service) StartSession(section, mode string) *Session {
+	return &Session{
+		service: s,
+		entity: model.SessionEntity{
+			ID:        newSessionID(),
+			StartedAt: s.now(),
+			Section:   section,
+			Mode:      mode,
+			Answers:   make([]model.AnswerRecord, 0),
+		},
+	}
+}
+
+// Answer 记录一次作答，同时更新单词的复习状态
+// elapsed为从出题到作答所用的时间
+func (sess *Session) Answer(section, word string, quality int, elapsed time.Duration) (*model.ReviewState, error) {
+	state, err := sess.service.RecordAnswer(&model.RecordAnswerRequest{
+		Section: section,
+		Word:    word,
+		Quality: quality,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sess.entity.Answers = append(sess.entity.Answers, model.AnswerRecord{
+		Section:    section,
+		Word:       word,
+		Quality:    quality,
+		Correct:    IsCorrect(quality),
+		ResponseMS: elapsed.Milliseconds(),
+		AnsweredAt: sess.service.now(),
+	})
+	// 写入失败的记录保留在内存中，之后作答或结束会话时再次写入
+	_ = sess.flush()
+	return state, nil
+}
+
+// Answered 返回已作答的记录
+func (sess *Session) Answered() []model.AnswerRecord {
+	return sess.entity.Answers
+}
+
+// Finish 结束会话，写入尚未写入会话日志的作答记录，没有作答的会话不记录
+func (sess *Session) Finish() error {
+	if err := sess.flush(); err != nil {
+		return fmt.Errorf("保存练习记录失败: %w", err)
+	}
+	return nil
+}
+
+// flush 把尚未写入的作答记录作为同一会话的一条记录追加到会话日志
+func (sess *Session) flush() error {
+	if sess.saved == len(sess.entity.Answers) {
+		return nil
+	}
+
+	record := sess.entity
+	record.Answers = sess.entity.Answers[sess.saved:]
+	record.EndedAt = sess.service.now()
+	if err := sess.service.sessionDAO.AppendSession(context.Background(), &record); err != nil {
+		return err
+	}
+	sess.saved = len(sess.entity.Answers)
+	return nil
+}
+
+// newSessionID 生成会话ID
+func newSessionID() string {
+	buf := make([]byte, 8)
+	if _, err := crand.Read(buf); err != nil {
+		// 随机数不可用时退化为时间戳
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// SectionItems 获取章节中的单词并随机排序，用于章节练习，count<=0表示全部
+func (s *Service) SectionItems(sectionName string, count int) ([]model.ReviewQueueItem, error) {
+	section, err := s.sectionDAO.GetSection(context.Background(), sectionName)
+	if err != nil {
+		return nil, fmt.Errorf("获取章节失败: %w", err)
+	}
+	if len(section.Words) == 0 {
+		return nil, fmt.Errorf("章节 '%s' 中没有单词", sectionName)
+	}
+
+	rng := rand.New(rand.NewSource(s.now().UnixNano()))
+	items := make([]model.ReviewQueueItem, 0, len(section.Words))
+	for _, i := range rng.Perm(len(section.Words)) {
+		word := section.Words[i]
+		if !isPracticable(word) {
+			continue
+		}
+		items = append(items, model.ReviewQueueItem{
+			Section: sectionName,
+			Word:    word,
+			IsNew:   word.Review.IsNew(),
+		})
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("章节 '%s' 中没有可练习的单词", sectionName)
+	}
+	if count > 0 && count < len(items) {
+		items = items[:count]
+	}
+	return items, nil
+}
+
+// isPracticable 判断单词是否可以练习，缺少英文的条目无法出题
+func isPracticable(word model.WordEntity) bool {
+	return strings.TrimSpace(word.W) != ""
+}
+
+// AllItems 获取所有章节中可练习的单词，可用作选择题干扰项的题库
+func (s *Service) AllItems() ([]model.ReviewQueueItem, error) {
+	allSections, err := s.sectionDAO.ListSections(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("获取所有章节失败: %w", err)
+	}
+
+	sort.Slice(allSections, func(i, j int) bool {
+		return allSections[i].Name < allSections[j].Name
+	})
+
+	var items []model.ReviewQueueItem
+	for _, section := range allSections {
+		if section.Name == model.MistakeSectionName {
+			continue
+		}
+		for _, word := range section.Words {
+			if !isPracticable(word) {
+				continue
+			}
+			items = append(items, model.ReviewQueueItem{
+				Section: section.Name,
+				Word:    word,
+				IsNew:   word.Review.IsNew(),
+			})
+		}
+	}
+	return items, nil
+}
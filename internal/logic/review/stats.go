@@ -0,0 +1,175 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+const (
+	// DefaultStatsDays 默认统计最近多少天的学习情况
+	DefaultStatsDays = 7
+	// DefaultHardestWords 默认列出最难的多少个单词
+	DefaultHardestWords = 20
+)
+
+// dateLayout 每日统计的日期格式
+const dateLayout = "2006-01-02"
+
+// Stats 汇总会话日志，统计各章节正确率、最难的单词、每日学习量和连续学习天数
+func (s *Service) Stats(req *model.StatsRequest) (*model.StatsResponse, error) {
+	sessions, err := s.sessionDAO.ListSessions(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("读取练习记录失败: %w", err)
+	}
+
+	days := req.Days
+	if days <= 0 {
+		days = DefaultStatsDays
+	}
+	top := req.Top
+	if top <= 0 {
+		top = DefaultHardestWords
+	}
+
+	now := s.now()
+	today := StartOfDay(now)
+
+	type wordKey struct{ section, word string }
+	type wordAcc struct {
+		model.WordStats
+		timed      int
+		responseMS int64
+	}
+
+	resp := &model.StatsResponse{Sessions: len(sessions)}
+	sectionStats := make(map[string]*model.SectionStats)
+	wordStats := make(map[wordKey]*wordAcc)
+	dailyStats := make(map[string]*model.DailyStats)
+	dailyWords := make(map[string]map[wordKey]bool)
+
+	for _, session := range sessions {
+		for _, answer := range session.Answers {
+			resp.Answers++
+			if answer.Correct {
+				resp.Correct++
+			}
+
+			sec := sectionStats[answer.Section]
+			if sec == nil {
+				sec = &model.SectionStats{Section: answer.Section}
+				sectionStats[answer.Section] = sec
+			}
+			sec.Answers++
+			if answer.Correct {
+				sec.Correct++
+			}
+
+			key := wordKey{answer.Section, answer.Word}
+			word := wordStats[key]
+			if word == nil {
+				word = &wordAcc{WordStats: model.WordStats{Section: answer.Section, Word: answer.Word}}
+				wordStats[key] = word
+			}
+			word.Answers++
+			if !answer.Correct {
+				word.Wrong++
+			}
+			if answer.ResponseMS > 0 {
+				word.timed++
+				word.responseMS += answer.ResponseMS
+			}
+
+			// 按当前时区划分日期
+			date := answer.AnsweredAt.In(now.Location()).Format(dateLayout)
+			day := dailyStats[date]
+			if day == nil {
+				day = &model.DailyStats{Date: date}
+				dailyStats[date] = day
+				dailyWords[date] = make(map[wordKey]bool)
+			}
+			day.Answers++
+			if answer.Correct {
+				day.Correct++
+			}
+			if !dailyWords[date][key] {
+				dailyWords[date][key] = true
+				day.Words++
+			}
+		}
+	}
+
+	// 各章节正确率
+	resp.Sections = make([]model.SectionStats, 0, len(sectionStats))
+	for _, sec := range sectionStats {
+		sec.Accuracy = float64(sec.Correct) / float64(sec.Answers)
+		resp.Sections = append(resp.Sections, *sec)
+	}
+	sort.Slice(resp.Sections, func(i, j int) bool {
+		return resp.Sections[i].Section < resp.Sections[j].Section
+	})
+
+	// 最难的单词：错误率高的优先，其次是答错次数多、用时长的
+	hardest := make([]model.WordStats, 0, len(wordStats))
+	for _, word := range wordStats {
+		word.ErrorRate = float64(word.Wrong) / float64(word.Answers)
+		if word.timed > 0 {
+			word.AvgResponse = word.responseMS / int64(word.timed)
+		}
+		hardest = append(hardest, word.WordStats)
+	}
+	sort.Slice(hardest, func(i, j int) bool {
+		a, b := hardest[i], hardest[j]
+		if a.ErrorRate != b.ErrorRate {
+			return a.ErrorRate > b.ErrorRate
+		}
+		if a.Wrong != b.Wrong {
+			return a.Wrong > b.Wrong
+		}
+		if a.AvgResponse != b.AvgResponse {
+			return a.AvgResponse > b.AvgResponse
+		}
+		if a.Word != b.Word {
+			return a.Word < b.Word
+		}
+		return a.Section < b.Section
+	})
+	if len(hardest) > top {
+		hardest = hardest[:top]
+	}
+	resp.HardestWords = hardest
+
+	// 最近几天的学习量，没有练习的日期记为0
+	resp.Daily = make([]model.DailyStats, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format(dateLayout)
+		if day, ok := dailyStats[date]; ok {
+			resp.Daily = append(resp.Daily, *day)
+		} else {
+			resp.Daily = append(resp.Daily, model.DailyStats{Date: date})
+		}
+	}
+
+	resp.Streak = streak(dailyStats, today)
+	return resp, nil
+}
+
+// streak 计算截至今天的连续学习天数，今天还没练习时从昨天开始计算
+func streak(daily map[string]*model.DailyStats, today time.Time) int {
+	day := today
+	if _, ok := daily[day.Format(dateLayout)]; !ok {
+		day = day.AddDate(0, 0, -1)
+	}
+
+	count := 0
+	for {
+		if _, ok := daily[day.Format(dateLayout)]; !ok {
+			return count
+		}
+		count++
+		day = day.AddDate(0, 0, -1)
+	}
+}
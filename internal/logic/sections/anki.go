@@ -0,0 +1,271 @@
+package sections
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// ankiSeparators Anki文件头 #separator 中可用的分隔符名称
+var ankiSeparators = map[string]rune{
+	"tab": '\t', "comma": ',', "semicolon": ';', "space": ' ', "pipe": '|', "colon": ':',
+}
+
+// ankiHeaderKeys Anki笔记文本中可以识别的文件头，用于自动识别文件格式
+var ankiHeaderKeys = map[string]bool{
+	"separator": true, "html": true, "tags": true, "columns": true, "deck": true, "notetype": true,
+	"deck column": true, "tags column": true, "guid column": true, "notetype column": true, "if matches": true,
+}
+
+// ankiDefaultColumns 没有 #columns 文件头时，除牌组、标签等特殊列外各列依次对应的字段
+var ankiDefaultColumns = []model.ColumnField{model.ColumnWord, model.ColumnMeaning, model.ColumnPhrase}
+
+var (
+	ankiLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+	ankiHTMLTag   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// ankiNotes 从Anki笔记文本中读取的内容
+type ankiNotes struct {
+	rows    []tableRow
+	columns []model.ColumnField
+	deck    string   // #deck 指定的牌组，没有牌组列时作为章节
+	tags    []string // #tags 指定的标签，添加到每个单词
+}
+
+// isAnkiNotes 判断文件是否以Anki笔记文本的文件头（如 #separator:tab）开头
+func isAnkiNotes(data []byte) bool {
+	line, _, _ := strings.Cut(strings.TrimPrefix(string(data), utf8BOM), "\n")
+	if !strings.HasPrefix(line, "#") {
+		return false
+	}
+	key, _, ok := strings.Cut(line[1:], ":")
+	return ok && ankiHeaderKeys[strings.ToLower(strings.TrimSpace(key))]
+}
+
+// ankiWords 把Anki笔记文本转换为要导入的单词
+// 牌组列或 #deck 作为章节，两者都没有时使用section；columns不为空时代替 #columns 指定各列的含义
+func ankiWords(data []byte, columns []model.ColumnField, section string) ([]model.ImportedWord, []model.ImportIssue, error) {
+	notes, err := readAnkiNotes(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(columns) == 0 {
+		columns = notes.columns
+	}
+	if section == "" {
+		section = notes.deck
+	}
+
+	words, issues := rowWords(notes.rows, columns, section)
+	for i := range words {
+		words[i].Word.Tags = appendTags(words[i].Word.Tags, notes.tags)
+	}
+	return words, issues, nil
+}
+
+// readAnkiNotes 解析Anki笔记文本：开头以#开始的文件头，之后每行一条笔记
+// html为true时把笔记字段中的<br>转换为换行，去掉其他HTML标签并还原转义字符；标签列中以空格分隔的标签转换为分号分隔
+func readAnkiNotes(data []byte) (*ankiNotes, error) {
+	text := strings.TrimPrefix(string(data), utf8BOM)
+	notes := &ankiNotes{}
+	separator := '\t'
+	isHTML := false
+	columnNames := ""
+	special := make(map[int]model.ColumnField) // 从1开始的列号 -> 字段
+
+	headerLines := 0
+	for strings.HasPrefix(text, "#") {
+		var header string
+		header, text, _ = strings.Cut(text, "\n")
+		headerLines++
+
+		key, value, ok := strings.Cut(strings.TrimRight(header[1:], "\r"), ":")
+		if !ok {
+			continue // 注释
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		switch key {
+		case "separator":
+			value = strings.TrimSpace(value)
+			if sep, ok := ankiSeparators[strings.ToLower(value)]; ok {
+				separator = sep
+			} else if utf8.RuneCountInString(value) == 1 {
+				separator, _ = utf8.DecodeRuneInString(value)
+			} else {
+				return nil, fmt.Errorf("第%d行: 不支持的分隔符 %s", headerLines, value)
+			}
+		case "html":
+			isHTML = strings.EqualFold(strings.TrimSpace(value), "true")
+		case "tags":
+			notes.tags = strings.Fields(value)
+		case "deck":
+			notes.deck = strings.TrimSpace(value)
+		case "columns":
+			columnNames = value
+		case "deck column", "tags column", "guid column", "notetype column":
+			index, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || index < 1 {
+				return nil, fmt.Errorf("第%d行: 无效的列号 %s", headerLines, value)
+			}
+			special[index] = model.ColumnIgnore
+			switch key {
+			case "deck column":
+				special[index] = model.ColumnSection
+			case "tags column":
+				special[index] = model.ColumnTags
+			}
+		}
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	width := 0
+	for {
+		cells, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析Anki笔记失败: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if blankRow(cells) {
+			continue
+		}
+		notes.rows = append(notes.rows, tableRow{line: line + headerLines, cells: cells})
+		if len(cells) > width {
+			width = len(cells)
+		}
+	}
+
+	if columnNames != "" {
+		for _, name := range strings.Split(columnNames, string(separator)) {
+			column, ok := columnAliases[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				column = model.ColumnIgnore
+			}
+			notes.columns = append(notes.columns, column)
+		}
+		if len(notes.columns) > width {
+			width = len(notes.columns)
+		}
+	}
+	columns := make([]model.ColumnField, width)
+	next := 0
+	for i := range columns {
+		switch column, ok := special[i+1]; {
+		case ok:
+			columns[i] = column
+		case i < len(notes.columns):
+			columns[i] = notes.columns[i]
+		case columnNames == "" && next < len(ankiDefaultColumns):
+			columns[i] = ankiDefaultColumns[next]
+			next++
+		default:
+			columns[i] = model.ColumnIgnore
+		}
+	}
+	notes.columns = columns
+
+	// 牌组和标签不是HTML，只转换笔记字段；Anki的标签以空格分隔，导入时与表格一样使用分号分隔
+	for i, column := range columns {
+		for _, row := range notes.rows {
+			if i >= len(row.cells) {
+				continue
+			}
+			switch {
+			case column == model.ColumnTags:
+				row.cells[i] = strings.Join(strings.Fields(row.cells[i]), ";")
+			case column != model.ColumnSection && isHTML:
+				row.cells[i] = ankiText(row.cells[i])
+			}
+		}
+	}
+	return notes, nil
+}
+
+// ankiText 把Anki的HTML字段转换为纯文本
+func ankiText(field string) string {
+	field = ankiLineBreak.ReplaceAllString(field, "\n")
+	field = ankiHTMLTag.ReplaceAllString(field, "")
+	return strings.TrimSpace(html.UnescapeString(field))
+}
+
+// ankiField 把文本转义为Anki的HTML字段，换行转换为<br>
+func ankiField(text string) string {
+	text = strings.ReplaceAll(text, "\t", " ")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// ankiDeck 把章节名称转换为牌组列：牌组不是HTML，只把制表符和换行替换为空格，含引号时按CSV的规则加引号
+func ankiDeck(name string) string {
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool { return r == '\t' || r == '\r' || r == '\n' }), " ")
+	if strings.Contains(name, `"`) {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return name
+}
+
+// ankiTags 把单词的标签转换为Anki以空格分隔的标签，标签中的空格替换为下划线
+func ankiTags(tags []string) string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.Join(strings.Fields(tag), "_"); tag != "" {
+			result = append(result, tag)
+		}
+	}
+	return strings.Join(result, " ")
+}
+
+// appendTags 添加标签，跳过已有的标签
+func appendTags(tags []string, extra []string) []string {
+	for _, tag := range extra {
+		found := false
+		for _, existing := range tags {
+			if existing == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// writeAnki 把章节中的单词写为Anki笔记文本，每个章节作为一个牌组
+// 各列依次为单词、释义、例句、牌组和标签，导入Anki时可以把前三列映射到笔记类型的字段
+// 只有笔记字段按HTML转义，牌组和标签原样写入；每个单词的标签都在标签列中，
+// 不写 #tags 文件头，否则重新导入时每个单词都会多出相同的标签
+func writeAnki(w io.Writer, sections []model.SectionEntity) error {
+	var buf bytes.Buffer
+	buf.WriteString("#separator:tab\n")
+	buf.WriteString("#html:true\n")
+	buf.WriteString("#columns:Word\tMeaning\tPhrase\tDeck\tTags\n")
+	buf.WriteString("#deck column:4\n")
+	buf.WriteString("#tags column:5\n")
+	for _, section := range sections {
+		deck := ankiDeck(section.Name)
+		for _, word := range section.Words {
+			fields := []string{ankiField(word.W), ankiField(word.C), ankiField(word.Phrase), deck, ankiTags(word.Tags)}
+			buf.WriteString(strings.Join(fields, "\t"))
+			buf.WriteByte('\n')
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,313 @@
+package sections
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// ImportWords 从CSV/TSV文件或Anki笔记文本导入单词
+// 先与词库比较，分出新单词、重复（拼写和内容都相同）和冲突（拼写相同但释义或例句不同）的单词；
+// DryRun时只返回预览，否则新建缺少的章节并通过AddWordToSection添加新单词，重复和冲突的单词不会写入
+func (s *Service) ImportWords(req *model.ImportWordsRequest) (*model.ImportWordsResponse, error) {
+	format, err := DetectFormat(req.Format, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	if format == model.FormatHTML || format == model.FormatMD {
+		return nil, fmt.Errorf("不支持从%s文件导入单词", format)
+	}
+	data, err := os.ReadFile(req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取导入文件失败: %w", err)
+	}
+	if req.Format == "" {
+		format = contentFormat(format, data)
+		if format == model.FormatText && (len(req.Columns) > 0 || req.Header) {
+			// 指定了列或表头说明文件是表格，不按单词列表解析
+			format = model.FormatTSV
+		}
+	}
+
+	var words []model.ImportedWord
+	var invalid []model.ImportIssue
+	switch format {
+	case model.FormatAnki:
+		words, invalid, err = ankiWords(data, req.Columns, req.Section)
+	case model.FormatText:
+		words, invalid = parsedImport(ParseWordList(string(data)).Words, req.Section)
+	default:
+		var rows []tableRow
+		if rows, err = readTable(bytes.NewReader(data), format); err == nil {
+			words, invalid, err = tableWords(rows, req.Columns, req.Header, req.Section)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.importWords(words, invalid, filepath.Base(req.Path), req.DryRun)
+}
+
+// ImportParsedWords 导入解析并修改后的单词列表，章节不存在时新建
+// 与ImportWords一样跳过重复和冲突的单词，DryRun时只返回预览
+func (s *Service) ImportParsedWords(req *model.ImportParsedWordsRequest) (*model.ImportWordsResponse, error) {
+	section := strings.TrimSpace(req.Section)
+	if section == "" {
+		return nil, fmt.Errorf("请指定导入到的章节")
+	}
+	source := req.Source
+	if source == "" {
+		source = "单词列表"
+	}
+	words, invalid := parsedImport(req.Words, section)
+	return s.importWords(words, invalid, source, req.DryRun)
+}
+
+// DetectFileFormat 按扩展名和文件内容判断要导入的文件格式
+func DetectFileFormat(path string) (model.ExchangeFormat, error) {
+	format, err := DetectFormat("", path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取导入文件失败: %w", err)
+	}
+	return contentFormat(format, data), nil
+}
+
+// contentFormat 根据文件内容修正按扩展名得到的格式：以Anki文件头开始的为Anki笔记，
+// 按扩展名为TSV但多数行没有制表符的（如 .txt 中粘贴的单词列表）为自由格式的单词列表
+func contentFormat(format model.ExchangeFormat, data []byte) model.ExchangeFormat {
+	switch {
+	case isAnkiNotes(data):
+		return model.FormatAnki
+	case format == model.FormatTSV && !isTabular(string(data)):
+		return model.FormatText
+	}
+	return format
+}
+
+// isTabular 判断文本是否为制表符分隔的表格：与detectDelimiter一样按多数判断，
+// 个别缺少制表符的行仍按表格导入，并报告为无法导入的行
+func isTabular(text string) bool {
+	lines, tabbed := 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines++
+		if strings.Contains(line, "\t") {
+			tabbed++
+		}
+	}
+	return tabbed*2 >= lines
+}
+
+// parsedImport 把解析后的单词转换为要导入的单词，重新检查后仍有问题的行作为无法导入的行
+func parsedImport(parsed []model.ParsedWord, section string) ([]model.ImportedWord, []model.ImportIssue) {
+	var words []model.ImportedWord
+	var invalid []model.ImportIssue
+	for _, item := range parsed {
+		CheckParsedWord(&item)
+		if item.Issue != "" {
+			invalid = append(invalid, model.ImportIssue{Line: item.Line, Reason: fmt.Sprintf("%s: %s", item.Issue, item.Text)})
+			continue
+		}
+		words = append(words, model.ImportedWord{Line: item.Line, Section: section, Word: item.Word})
+	}
+	return words, invalid
+}
+
+// importWords 比较要导入的单词和词库，dryRun为false时写入新单词；source为修改历史中显示的来源
+// 一次导入在修改历史中只记录为一条修改，撤销一次即可回到导入之前
+func (s *Service) importWords(words []model.ImportedWord, invalid []model.ImportIssue, source string, dryRun bool) (*model.ImportWordsResponse, error) {
+	ctx := context.Background()
+	resp, err := s.planImport(ctx, words, invalid)
+	if err != nil || dryRun || len(resp.New) == 0 {
+		return resp, err
+	}
+
+	ctx = dao.WithHistoryBatch(ctx, fmt.Sprintf("从 %s 导入 %d 个单词", source, len(resp.New)))
+	for _, name := range resp.NewSections {
+		if err := s.sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: name}); err != nil {
+			return resp, fmt.Errorf("创建章节失败: %w", err)
+		}
+	}
+	for _, item := range resp.New {
+		if err := s.sectionDAO.AddWordToSection(ctx, item.Section, item.Word); err != nil {
+			return resp, fmt.Errorf("导入第 %d 行失败（已导入 %d 个单词）: %w", item.Line, resp.Added, err)
+		}
+		resp.Added++
+	}
+	return resp, nil
+}
+
+// planImport 将要导入的单词分为新单词、重复和冲突的单词，同一文件中重复出现的单词与第一次出现时比较
+func (s *Service) planImport(ctx context.Context, words []model.ImportedWord, invalid []model.ImportIssue) (*model.ImportWordsResponse, error) {
+	resp := &model.ImportWordsResponse{Total: len(words) + len(invalid)}
+	known := make(map[string]map[string]model.WordEntity) // 章节 -> 单词 -> 已有或先导入的单词
+
+	for _, item := range words {
+		if item.Section == "" {
+			invalid = append(invalid, model.ImportIssue{Line: item.Line, Reason: fmt.Sprintf("单词 %s 未指定章节", item.Word.W)})
+			continue
+		}
+		if model.IsManagedSection(item.Section) {
+			invalid = append(invalid, model.ImportIssue{Line: item.Line, Reason: fmt.Sprintf("章节 '%s' 由程序自动维护，不能导入单词", item.Section)})
+			continue
+		}
+
+		existing, ok := known[item.Section]
+		if !ok {
+			var err error
+			if existing, err = s.sectionWords(ctx, item.Section); err != nil {
+				return nil, err
+			}
+			if existing == nil {
+				resp.NewSections = append(resp.NewSections, item.Section)
+				existing = make(map[string]model.WordEntity)
+			}
+			known[item.Section] = existing
+		}
+
+		if word, found := existing[item.Word.W]; found {
+			if sameImportContent(&word, &item.Word) {
+				resp.Duplicates = append(resp.Duplicates, item)
+			} else {
+				resp.Conflicts = append(resp.Conflicts, model.ImportConflict{ImportedWord: item, Existing: word})
+			}
+			continue
+		}
+		existing[item.Word.W] = item.Word
+		resp.New = append(resp.New, item)
+	}
+
+	sort.SliceStable(invalid, func(i, j int) bool {
+		return invalid[i].Line < invalid[j].Line
+	})
+	resp.Invalid = invalid
+	return resp, nil
+}
+
+// sectionWords 按拼写索引章节中的单词，章节不存在时返回nil
+func (s *Service) sectionWords(ctx context.Context, name string) (map[string]model.WordEntity, error) {
+	exists, err := s.sectionDAO.SectionExists(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("检查章节存在性失败: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+	section, err := s.sectionDAO.GetSection(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("获取章节失败: %w", err)
+	}
+	words := make(map[string]model.WordEntity, len(section.Words))
+	for _, word := range section.Words {
+		words[word.W] = word
+	}
+	return words, nil
+}
+
+// sameImportContent 判断导入的单词与已有单词的释义和例句是否相同
+func sameImportContent(existing, incoming *model.WordEntity) bool {
+	return strings.TrimSpace(existing.C) == strings.TrimSpace(incoming.C) &&
+		strings.TrimSpace(existing.Phrase) == strings.TrimSpace(incoming.Phrase)
+}
+
+// ExportWords 将章节导出为CSV/TSV文件、Anki笔记文本或可打印的HTML/Markdown练习纸，未指定文件时输出到标准输出
+func (s *Service) ExportWords(req *model.ExportWordsRequest) (*model.ExportWordsResponse, error) {
+	format, err := DetectFormat(req.Format, req.Path)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case model.FormatHTML, model.FormatMD:
+		return s.exportWorksheet(format, req)
+	case model.FormatText:
+		return nil, fmt.Errorf("不支持导出为%s格式", format)
+	}
+	sections, err := s.exportSections(req.Sections)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := req.Columns
+	if len(columns) == 0 {
+		columns = defaultExportColumns
+		if len(sections) != 1 {
+			columns = append(columns[:len(columns):len(columns)], model.ColumnSection)
+		}
+	}
+
+	resp := &model.ExportWordsResponse{Sections: len(sections)}
+	for _, section := range sections {
+		resp.Words += len(section.Words)
+	}
+	err = writeExport(req.Path, func(w io.Writer) error {
+		if format == model.FormatAnki {
+			return writeAnki(w, sections)
+		}
+		return writeTable(w, format, columns, sections, !req.NoHeader, req.BOM)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// exportSections 获取要导出的章节，未指定时为全部章节（不含错题本等自动维护的章节）
+func (s *Service) exportSections(names []string) ([]model.SectionEntity, error) {
+	ctx := context.Background()
+	if len(names) == 0 {
+		all, err := s.sectionDAO.ListSections(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取章节列表失败: %w", err)
+		}
+		sections := make([]model.SectionEntity, 0, len(all))
+		for _, section := range all {
+			if !model.IsManagedSection(section.Name) {
+				sections = append(sections, section)
+			}
+		}
+		return sections, nil
+	}
+
+	sections := make([]model.SectionEntity, 0, len(names))
+	for _, name := range names {
+		section, err := s.sectionDAO.GetSection(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("获取章节失败: %w", err)
+		}
+		sections = append(sections, *section)
+	}
+	return sections, nil
+}
+
+// writeExport 将导出内容写入文件，path为空时写到标准输出
+func writeExport(path string, write func(w io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建导出文件失败: %w", err)
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// RenameSection 重命名章节，重命名当前章节时当前章节随之更新
+func (s *Service) RenameSection(req *model.RenameSectionRequest) error {
+	ctx := context.Background()
+
+	if req.NewName == "" {
+		return fmt.Errorf("新的章节名称不能为空")
+	}
+	if err := checkManagedSection(req.Name, "重命名"); err != nil {
+		return err
+	}
+	if err := checkManagedSection(req.NewName, "手动创建"); err != nil {
+		return err
+	}
+
+	section, err := s.sectionDAO.GetSection(ctx, req.Name)
+	if err != nil {
+		return fmt.Errorf("获取章节失败: %w", err)
+	}
+	section.Name = req.NewName
+	if err := s.sectionDAO.UpdateSection(ctx, req.Name, section); err != nil {
+		return fmt.Errorf("重命名章节失败: %w", err)
+	}
+
+	if s.currentSection == req.Name {
+		s.currentSection = req.NewName
+	}
+	fmt.Printf("✓ 已将章节 %s 重命名为 %s\n", req.Name, req.NewName)
+	return nil
+}
+
+// DeleteSection 删除章节及其中的全部单词，调用方负责向用户确认
+func (s *Service) DeleteSection(req *model.DeleteSectionRequest) error {
+	ctx := context.Background()
+
+	if err := checkManagedSection(req.Name, "删除"); err != nil {
+		return err
+	}
+	if err := s.sectionDAO.DeleteSection(ctx, req.Name); err != nil {
+		return fmt.Errorf("删除章节失败: %w", err)
+	}
+
+	if s.currentSection == req.Name {
+		s.currentSection = ""
+	}
+	fmt.Printf("✓ 已删除章节: %s\n", req.Name)
+	return nil
+}
+
+// MergeSections 将来源章节的单词并入目标章节，然后删除来源章节
+// 先保存目标章节再删除来源章节，中途失败时单词不会丢失，最多在两个章节中各留一份
+func (s *Service) MergeSections(req *model.MergeSectionsRequest) (*model.MergeSectionsResponse, error) {
+	ctx := context.Background()
+
+	policy := req.Policy
+	if policy == "" {
+		policy = model.DuplicateSkip
+	}
+	switch policy {
+	case model.DuplicateSkip, model.DuplicateOverwrite, model.DuplicateNewer:
+	default:
+		return nil, fmt.Errorf("不支持的重复单词处理方式 '%s'，可用: skip、overwrite、newer", policy)
+	}
+	if req.Source == req.Target {
+		return nil, fmt.Errorf("不能将章节合并到自身")
+	}
+	if err := checkManagedSection(req.Source, "合并"); err != nil {
+		return nil, err
+	}
+	if err := checkManagedSection(req.Target, "手动添加单词"); err != nil {
+		return nil, err
+	}
+
+	source, err := s.sectionDAO.GetSection(ctx, req.Source)
+	if err != nil {
+		return nil, fmt.Errorf("获取章节失败: %w", err)
+	}
+	target, err := s.sectionDAO.GetSection(ctx, req.Target)
+	if err != nil {
+		return nil, fmt.Errorf("获取章节失败: %w", err)
+	}
+
+	resp := &model.MergeSectionsResponse{}
+	target.Words = mergeWords(target.Words, source.Words, policy, resp)
+	if err := s.sectionDAO.UpdateSection(ctx, req.Target, target); err != nil {
+		return nil, fmt.Errorf("合并章节失败: %w", err)
+	}
+	if err := s.sectionDAO.DeleteSection(ctx, req.Source); err != nil {
+		return nil, fmt.Errorf("单词已并入章节 %s，但删除章节 %s 失败: %w", req.Target, req.Source, err)
+	}
+
+	if s.currentSection == req.Source {
+		s.currentSection = req.Target
+	}
+	fmt.Printf("✓ 已将章节 %s 合并到 %s: 新增 %d 个单词，覆盖 %d 个，跳过 %d 个\n",
+		req.Source, req.Target, resp.Added, resp.Overwritten, resp.Skipped)
+	return resp, nil
+}
+
+// mergeWords 按处理方式将incoming中的单词并入words，并统计各类单词的数量
+func mergeWords(words, incoming []model.WordEntity, policy model.DuplicatePolicy, stats *model.MergeSectionsResponse) []model.WordEntity {
+	index := make(map[string]int, len(words))
+	for i, word := range words {
+		if _, exists := index[word.W]; !exists {
+			index[word.W] = i
+		}
+	}
+
+	for _, word := range incoming {
+		i, exists := index[word.W]
+		switch {
+		case !exists:
+			index[word.W] = len(words)
+			words = append(words, word)
+			stats.Added++
+		case policy == model.DuplicateOverwrite,
+			policy == model.DuplicateNewer && word.UpdatedAt.After(words[i].UpdatedAt):
+			words[i] = word
+			stats.Overwritten++
+		default:
+			stats.Skipped++
+		}
+	}
+	return words
+}
+
+// SplitSection 拆分章节，单词连同复习状态一起移入新章节
+// 先创建新章节再更新原章节，更新失败时删除已创建的章节
+func (s *Service) SplitSection(req *model.SplitSectionRequest) (*model.SplitSectionResponse, error) {
+	ctx := context.Background()
+
+	if err := checkManagedSection(req.Name, "拆分"); err != nil {
+		return nil, err
+	}
+	section, err := s.sectionDAO.GetSection(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("获取章节失败: %w", err)
+	}
+
+	var kept []model.WordEntity
+	var parts []model.SectionEntity
+	switch {
+	case len(req.Words) > 0:
+		if req.NewName == "" {
+			return nil, fmt.Errorf("请指定新章节的名称")
+		}
+		var picked []model.WordEntity
+		kept, picked, err = pickWords(section, req.Words)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, model.SectionEntity{Name: req.NewName, Words: picked})
+	case req.Size > 0:
+		if len(section.Words) <= req.Size {
+			return nil, fmt.Errorf("章节 '%s' 只有 %d 个单词，无需拆分", req.Name, len(section.Words))
+		}
+		base := req.NewName
+		if base == "" {
+			base = req.Name
+		}
+		kept = section.Words[:req.Size]
+		for start := req.Size; start < len(section.Words); start += req.Size {
+			end := start + req.Size
+			if end > len(section.Words) {
+				end = len(section.Words)
+			}
+			name := fmt.Sprintf("%s-%d", base, len(parts)+2)
+			parts = append(parts, model.SectionEntity{Name: name, Words: section.Words[start:end]})
+		}
+	default:
+		return nil, fmt.Errorf("请指定每个章节的单词数量或要拆分出的单词")
+	}
+
+	// 先检查全部新名称，避免只创建了一部分章节
+	for _, part := range parts {
+		if err := checkManagedSection(part.Name, "手动创建"); err != nil {
+			return nil, err
+		}
+		exists, err := s.sectionDAO.SectionExists(ctx, part.Name)
+		if err != nil {
+			return nil, fmt.Errorf("检查章节存在性失败: %w", err)
+		}
+		if exists {
+			return nil, fmt.Errorf("章节 '%s' 已存在", part.Name)
+		}
+	}
+
+	resp := &model.SplitSectionResponse{}
+	for i := range parts {
+		if err := s.sectionDAO.CreateSection(ctx, &parts[i]); err != nil {
+			s.removeSections(ctx, resp.Created)
+			return nil, fmt.Errorf("创建章节失败: %w", err)
+		}
+		resp.Created = append(resp.Created, parts[i].Name)
+	}
+
+	section.Words = kept
+	if err := s.sectionDAO.UpdateSection(ctx, req.Name, section); err != nil {
+		s.removeSections(ctx, resp.Created)
+		return nil, fmt.Errorf("更新章节失败: %w", err)
+	}
+
+	fmt.Printf("✓ 已拆分章节 %s，保留 %d 个单词，新建章节:\n", req.Name, len(kept))
+	for _, part := range parts {
+		fmt.Printf("  %s (%d 个单词)\n", part.Name, len(part.Words))
+	}
+	return resp, nil
+}
+
+// pickWords 按单词拆分章节，返回保留的单词和选中的单词，选中的单词保持在章节中的顺序
+func pickWords(section *model.SectionEntity, texts []string) ([]model.WordEntity, []model.WordEntity, error) {
+	selected := make(map[string]bool, len(texts))
+	for _, text := range texts {
+		selected[text] = true
+	}
+
+	var kept, picked []model.WordEntity
+	for _, word := range section.Words {
+		if selected[word.W] {
+			picked = append(picked, word)
+			delete(selected, word.W)
+			continue
+		}
+		kept = append(kept, word)
+	}
+	for _, text := range texts {
+		if selected[text] {
+			return nil, nil, fmt.Errorf("单词 '%s' 在章节 '%s' 中不存在", text, section.Name)
+		}
+	}
+	return kept, picked, nil
+}
+
+// removeSections 撤销拆分时已创建的章节
+func (s *Service) removeSections(ctx context.Context, names []string) {
+	for _, name := range names {
+		if err := s.sectionDAO.DeleteSection(ctx, name); err != nil {
+			fmt.Printf("警告: 删除章节 %s 失败: %v\n", name, err)
+		}
+	}
+}
+
+// ArchiveSection 归档或取消归档章节，归档的章节不再显示在章节列表中，但数据仍然保留
+func (s *Service) ArchiveSection(req *model.ArchiveSectionRequest) error {
+	ctx := context.Background()
+
+	if err := checkManagedSection(req.Name, "归档"); err != nil {
+		return err
+	}
+	section, err := s.sectionDAO.GetSection(ctx, req.Name)
+	if err != nil {
+		return fmt.Errorf("获取章节失败: %w", err)
+	}
+
+	if section.Archived != req.Archived {
+		section.Archived = req.Archived
+		if err := s.sectionDAO.UpdateSection(ctx, req.Name, section); err != nil {
+			return fmt.Errorf("更新章节失败: %w", err)
+		}
+	}
+
+	if req.Archived {
+		fmt.Printf("✓ 已归档章节: %s\n", req.Name)
+	} else {
+		fmt.Printf("✓ 已取消归档章节: %s\n", req.Name)
+	}
+	return nil
+}
@@ -0,0 +1,320 @@
+package sections
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/config"
+	"github.com/ct-zh/englishLearn/internal/dao"
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// meaningSeparators 释义中分隔多个意思的符号，合并释义时按这些符号拆分，并使用其中第一个出现的符号连接
+const meaningSeparators = "；;，,、/"
+
+// mergeChange 合并数据文件时需要写入的一个章节
+type mergeChange struct {
+	section *model.SectionEntity
+	create  bool
+}
+
+// MergeDataFile 把另一个数据文件（JSON文件、词库目录或日志存储）合并到当前词库
+// 章节按名称匹配，不存在的章节整个新建；单词按规范化的拼写（忽略大小写和多余空格）匹配，
+// 释义或例句不同时按Policy处理。另一个文件中的复习进度不会合并，错题本等自动维护的章节会被跳过。
+// 另一个文件以只读方式读取，不会被迁移或修改。整个合并在修改历史中记录为一条修改，可以一次撤销
+func (s *Service) MergeDataFile(req *model.MergeFileRequest) (*model.MergeFileResponse, error) {
+	policy := req.Policy
+	if policy == "" {
+		policy = model.ConflictKeepOurs
+	}
+	switch policy {
+	case model.ConflictKeepOurs, model.ConflictKeepTheirs, model.ConflictCombine:
+	case model.ConflictAsk:
+		if req.Decide == nil && !req.DryRun {
+			return nil, fmt.Errorf("逐个询问需要在交互中进行")
+		}
+	default:
+		return nil, fmt.Errorf("不支持的冲突处理方式 '%s'，可用: keep-ours、keep-theirs、combine、ask", policy)
+	}
+	if err := config.ValidateDataFile(req.Path); err != nil {
+		return nil, fmt.Errorf("无法读取要合并的文件: %w", err)
+	}
+
+	ctx := context.Background()
+	theirs, err := dao.ReadDataFile(req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取要合并的文件失败: %w", err)
+	}
+
+	resp := &model.MergeFileResponse{Source: filepath.Base(req.Path)}
+	var changes []mergeChange
+	for _, section := range theirs {
+		resp.Sections++
+		resp.Words += len(section.Words)
+		if model.IsManagedSection(section.Name) {
+			resp.SkippedSections = append(resp.SkippedSections, section.Name)
+			continue
+		}
+
+		exists, err := s.sectionDAO.SectionExists(ctx, section.Name)
+		if err != nil {
+			return nil, fmt.Errorf("检查章节存在性失败: %w", err)
+		}
+		if !exists {
+			// 新章节同样按规范化的拼写去重，对方文件中重复的单词只添加一次
+			created := &model.SectionEntity{Name: section.Name, Archived: section.Archived}
+			mergeFileWords(created, section.Words, policy, req, resp)
+			resp.NewSections = append(resp.NewSections, section.Name)
+			changes = append(changes, mergeChange{section: created, create: true})
+			continue
+		}
+
+		ours, err := s.sectionDAO.GetSection(ctx, section.Name)
+		if err != nil {
+			return nil, fmt.Errorf("获取章节失败: %w", err)
+		}
+		if mergeFileWords(ours, section.Words, policy, req, resp) {
+			changes = append(changes, mergeChange{section: ours})
+		}
+	}
+	if req.DryRun || len(changes) == 0 {
+		return resp, nil
+	}
+
+	ctx = dao.WithHistoryBatch(ctx, fmt.Sprintf("合并数据文件 %s", resp.Source))
+	for _, change := range changes {
+		if change.create {
+			err = s.sectionDAO.CreateSection(ctx, change.section)
+		} else {
+			err = s.sectionDAO.UpdateSection(ctx, change.section.Name, change.section)
+		}
+		if err != nil {
+			return resp, fmt.Errorf("合并章节 %s 失败: %w", change.section.Name, err)
+		}
+	}
+	return resp, nil
+}
+
+// mergeFileWords 把另一个文件中同名章节的单词并入ours，返回章节是否有变化
+func mergeFileWords(ours *model.SectionEntity, incoming []model.WordEntity, policy model.ConflictPolicy, req *model.MergeFileRequest, resp *model.MergeFileResponse) bool {
+	index := make(map[string]int, len(ours.Words))
+	for i, word := range ours.Words {
+		if key := normalizeWordText(word.W); key != "" {
+			if _, exists := index[key]; !exists {
+				index[key] = i
+			}
+		}
+	}
+
+	changed := false
+	for _, word := range incoming {
+		key := normalizeWordText(word.W)
+		i, exists := index[key]
+		if !exists {
+			index[key] = len(ours.Words)
+			ours.Words = mergeWord(ours.Words, word, resp)
+			changed = true
+			continue
+		}
+
+		existing := &ours.Words[i]
+		if sameImportContent(existing, &word) || coversWord(existing, &word) {
+			resp.Identical++
+			continue
+		}
+
+		conflict := model.MergeConflict{Section: ours.Name, Ours: *existing, Theirs: word}
+		resolution := policy
+		if policy == model.ConflictAsk {
+			resolution = ""
+			if !req.DryRun {
+				resolution = req.Decide(&conflict)
+			}
+		}
+		switch resolution {
+		case model.ConflictKeepTheirs:
+			takeTheirs(existing, &word)
+			resp.TookTheirs++
+			changed = true
+		case model.ConflictCombine:
+			combineSenses(existing, &word)
+			resp.Combined++
+			changed = true
+		case "":
+		default:
+			resolution = model.ConflictKeepOurs
+			resp.KeptOurs++
+		}
+		conflict.Resolution = resolution
+		conflict.Result = *existing
+		resp.Conflicts = append(resp.Conflicts, conflict)
+	}
+	return changed
+}
+
+// coversWord 判断当前单词是否已经包含另一边的全部意思和例句，如之前合并过释义的单词
+func coversWord(ours, theirs *model.WordEntity) bool {
+	meanings := splitMeanings(ours.C)
+	for _, sense := range ours.Senses {
+		meanings = append(meanings, splitMeanings(sense.Meaning)...)
+	}
+	for _, meaning := range splitMeanings(theirs.C) {
+		if !containsString(meanings, meaning) {
+			return false
+		}
+	}
+	phrase := strings.TrimSpace(theirs.Phrase)
+	if phrase == "" || phrase == strings.TrimSpace(ours.Phrase) {
+		return true
+	}
+	for _, sense := range ours.Senses {
+		if containsString(sense.Examples, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeWord 添加另一个文件中的新单词，不带复习进度和错题记录，ID由DAO重新生成
+func mergeWord(words []model.WordEntity, word model.WordEntity, resp *model.MergeFileResponse) []model.WordEntity {
+	word.ID = ""
+	word.Review = nil
+	word.Mistake = nil
+	resp.Added++
+	return append(words, word)
+}
+
+// normalizeWordText 规范化单词拼写用于匹配：忽略大小写和多余的空格
+func normalizeWordText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// takeTheirs 使用另一个文件中单词的内容，保留当前单词的ID、创建时间和复习进度
+func takeTheirs(ours, theirs *model.WordEntity) {
+	ours.C = theirs.C
+	ours.Phrase = theirs.Phrase
+	ours.POS = theirs.POS
+	ours.Senses = theirs.Senses
+	ours.Tags = theirs.Tags
+	ours.Notes = theirs.Notes
+}
+
+// combineSenses 合并两边的释义：有相同意思的义项合并释义、例句取并集，对方的主义项没有相同意思时并入主义项，
+// 其他义项添加到最后；主释义与第一个义项的释义保持一致，标签取并集，当前单词没有例句、词性或备注时使用另一边的
+func combineSenses(ours, theirs *model.WordEntity) {
+	senses := cloneSenses(wordSenses(ours))
+	for i, sense := range wordSenses(theirs) {
+		match := matchSense(senses, sense.Meaning)
+		if match < 0 && i == 0 && len(senses) > 0 {
+			match = 0
+		}
+		if match < 0 {
+			senses = append(senses, model.Sense{Meaning: sense.Meaning, Examples: append([]string(nil), sense.Examples...)})
+			continue
+		}
+		senses[match].Meaning = combineMeanings(senses[match].Meaning, sense.Meaning)
+		for _, example := range sense.Examples {
+			if !containsString(senses[match].Examples, example) {
+				senses[match].Examples = append(senses[match].Examples, example)
+			}
+		}
+	}
+
+	ours.Senses = senses
+	if len(senses) > 0 {
+		ours.C = senses[0].Meaning
+	}
+	ours.Tags = appendTags(ours.Tags, theirs.Tags)
+	if ours.Phrase == "" {
+		ours.Phrase = theirs.Phrase
+	}
+	if ours.POS == "" {
+		ours.POS = theirs.POS
+	}
+	switch {
+	case ours.Notes == "":
+		ours.Notes = theirs.Notes
+	case theirs.Notes != "" && !strings.Contains(ours.Notes, theirs.Notes):
+		ours.Notes += "\n" + theirs.Notes
+	}
+}
+
+// matchSense 查找与释义有相同意思的义项，没有时返回-1
+func matchSense(senses []model.Sense, meaning string) int {
+	for i, sense := range senses {
+		existing := splitMeanings(sense.Meaning)
+		for _, part := range splitMeanings(meaning) {
+			if containsString(existing, part) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// wordSenses 单词的义项，没有义项时由主释义和例句组成
+func wordSenses(word *model.WordEntity) []model.Sense {
+	if len(word.Senses) > 0 || word.C == "" {
+		return word.Senses
+	}
+	sense := model.Sense{Meaning: word.C}
+	if word.Phrase != "" {
+		sense.Examples = []string{word.Phrase}
+	}
+	return []model.Sense{sense}
+}
+
+// cloneSenses 复制义项，避免修改冲突记录中原来的单词
+func cloneSenses(senses []model.Sense) []model.Sense {
+	result := make([]model.Sense, len(senses))
+	for i, sense := range senses {
+		result[i] = model.Sense{Meaning: sense.Meaning, Examples: append([]string(nil), sense.Examples...)}
+	}
+	return result
+}
+
+// combineMeanings 把两个释义中的意思合并，去掉重复的意思，使用释义中已有的分隔符连接
+func combineMeanings(ours, theirs string) string {
+	separator := "；"
+	for _, sep := range meaningSeparators {
+		if strings.ContainsRune(ours, sep) || strings.ContainsRune(theirs, sep) {
+			separator = string(sep)
+			break
+		}
+	}
+
+	var meanings []string
+	for _, text := range []string{ours, theirs} {
+		for _, meaning := range splitMeanings(text) {
+			if !containsString(meanings, meaning) {
+				meanings = append(meanings, meaning)
+			}
+		}
+	}
+	return strings.Join(meanings, separator)
+}
+
+// splitMeanings 按分隔符拆分释义中的各个意思
+func splitMeanings(text string) []string {
+	var meanings []string
+	for _, meaning := range strings.FieldsFunc(text, func(r rune) bool {
+		return strings.ContainsRune(meaningSeparators, r)
+	}) {
+		if meaning = strings.TrimSpace(meaning); meaning != "" {
+			meanings = append(meanings, meaning)
+		}
+	}
+	return meanings
+}
+
+// containsString 判断列表中是否有该字符串
+func containsString(list []string, text string) bool {
+	for _, item := range list {
+		if item == text {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package sections
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// errorRateBoost 错误率对抽样权重的放大倍数，错误率100%的单词权重为未出错单词的5倍
+const errorRateBoost = 4.0
+
+// sampleWords 从单词列表中不重复地抽取count个单词
+// weighted为true时按错误率加权（Efraimidis-Spirakis算法），易错单词更容易被抽中
+func sampleWords(words []model.WordEntity, count int, rng *rand.Rand, weighted bool) []model.WordEntity {
+	if count > len(words) {
+		count = len(words)
+	}
+
+	if !weighted {
+		// 随机排列后取前count个，保证不重复
+		indexes := rng.Perm(len(words))[:count]
+		result := make([]model.WordEntity, 0, count)
+		for _, i := range indexes {
+			result = append(result, words[i])
+		}
+		return result
+	}
+
+	type keyedWord struct {
+		key  float64
+		word model.WordEntity
+	}
+	keyed := make([]keyedWord, len(words))
+	for i, word := range words {
+		weight := 1 + errorRateBoost*word.Review.ErrorRate()
+		// key = u^(1/w)，取key最大的count个即为按权重不放回抽样
+		keyed[i] = keyedWord{
+			key:  math.Pow(rng.Float64(), 1/weight),
+			word: word,
+		}
+	}
+	sort.SliceStable(keyed, func(i, j int) bool {
+		return keyed[i].key > keyed[j].key
+	})
+
+	result := make([]model.WordEntity, 0, count)
+	for _, kw := range keyed[:count] {
+		result = append(result, kw.word)
+	}
+	return result
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"math/rand"
+	"time"
+	
 	"github.com/ct-zh/englishLearn/internal/dao"
 	"github.com/ct-zh/englishLearn/model"
 )
@@ -39,6 +42,10 @@ func (s *Service) AddWord(req *model.AddWordRequest) error {
 	if !exists {
 		return fmt.Errorf("章节 '%s' 不存在", req.Section)
 	}
+
+	if err := checkManagedSection(req.Section, "手动添加单词"); err != nil {
+		return err
+	}
 	
 	// 创建单词实体
 	word := model.WordEntity{
@@ -57,30 +64,181 @@ func (s *Service) AddWord(req *model.AddWordRequest) error {
 	return nil
 }
 
+// UpdateWord 编辑单词的拼写、释义或例句，未填写的字段保持不变
+func (s *Service) UpdateWord(req *model.UpdateWordRequest) error {
+	ctx := context.Background()
+
+	if req.NewWord == "" && req.Translation == "" && req.Phrase == "" {
+		return fmt.Errorf("请至少指定新的单词、释义或例句")
+	}
+	if err := checkManagedSection(req.Section, "手动编辑单词"); err != nil {
+		return err
+	}
+
+	section, err := s.sectionDAO.GetSection(ctx, req.Section)
+	if err != nil {
+		return fmt.Errorf("获取章节失败: %w", err)
+	}
+	var word *model.WordEntity
+	for i := range section.Words {
+		if section.Words[i].W == req.Word {
+			word = &section.Words[i]
+			break
+		}
+	}
+	if word == nil {
+		return fmt.Errorf("单词 '%s' 在章节 '%s' 中不存在", req.Word, req.Section)
+	}
+
+	applyWordEdit(word, req)
+	if err := s.sectionDAO.UpdateWord(ctx, req.Section, req.Word, *word); err != nil {
+		return fmt.Errorf("编辑单词失败: %w", err)
+	}
+
+	fmt.Printf("成功编辑单词: %s (%s)，章节: %s\n", word.W, word.C, req.Section)
+	return nil
+}
+
+// applyWordEdit 将编辑内容写入单词
+// C和Phrase是第一个义项的释义和第一个例句，编辑时总是一并更新第一个义项，即使两者原本已经不一致
+func applyWordEdit(word *model.WordEntity, req *model.UpdateWordRequest) {
+	if req.NewWord != "" {
+		word.W = req.NewWord
+	}
+	if req.Translation != "" {
+		word.C = req.Translation
+		if len(word.Senses) > 0 {
+			word.Senses[0].Meaning = req.Translation
+		}
+	}
+	if req.Phrase != "" {
+		word.Phrase = req.Phrase
+		if len(word.Senses) > 0 {
+			if examples := word.Senses[0].Examples; len(examples) > 0 {
+				examples[0] = req.Phrase
+			} else {
+				word.Senses[0].Examples = []string{req.Phrase}
+			}
+		}
+	}
+}
+
+// DeleteWord 从章节删除单词
+func (s *Service) DeleteWord(req *model.DeleteWordRequest) error {
+	ctx := context.Background()
+
+	if err := checkManagedSection(req.Section, "手动删除单词"); err != nil {
+		return err
+	}
+	if err := s.sectionDAO.RemoveWordFromSection(ctx, req.Section, req.Word); err != nil {
+		return fmt.Errorf("删除单词失败: %w", err)
+	}
+
+	fmt.Printf("成功从章节 %s 删除单词: %s\n", req.Section, req.Word)
+	return nil
+}
+
+// MoveWord 将单词移动到另一个章节，复习进度随单词一起移动
+func (s *Service) MoveWord(req *model.TransferWordRequest) error {
+	ctx := context.Background()
+
+	if err := checkManagedSection(req.Section, "手动移出单词"); err != nil {
+		return err
+	}
+	if err := checkManagedSection(req.Target, "手动移入单词"); err != nil {
+		return err
+	}
+	if err := s.sectionDAO.MoveWord(ctx, req.Section, req.Target, req.Word); err != nil {
+		return fmt.Errorf("移动单词失败: %w", err)
+	}
+
+	fmt.Printf("成功将单词 %s 从章节 %s 移动到章节 %s\n", req.Word, req.Section, req.Target)
+	return nil
+}
+
+// CopyWord 将单词复制到另一个章节，副本的复习进度从头开始；可以从错题本中复制出单词
+func (s *Service) CopyWord(req *model.TransferWordRequest) error {
+	ctx := context.Background()
+
+	if err := checkManagedSection(req.Target, "手动添加单词"); err != nil {
+		return err
+	}
+	if err := s.sectionDAO.CopyWord(ctx, req.Section, req.Target, req.Word); err != nil {
+		return fmt.Errorf("复制单词失败: %w", err)
+	}
+
+	fmt.Printf("成功将单词 %s 从章节 %s 复制到章节 %s\n", req.Word, req.Section, req.Target)
+	return nil
+}
+
 // ListWords 获取单词列表
 func (s *Service) ListWords(req *model.ListWordsRequest) (*model.ListWordsResponse, error) {
+	resp, err := s.pageWords(req)
+	if err != nil || len(resp.Words) == 0 {
+		return resp, err
+	}
+
+	offset := (resp.CurrentPage - 1) * req.Size
+	fmt.Printf("章节 %s 第%d页单词列表 (第%d页/共%d页):\n", req.Section, resp.CurrentPage, resp.CurrentPage, resp.TotalPages)
+	for i, word := range resp.Words {
+		if word.Phrase != "" {
+			fmt.Printf("%d. %s - %s\n   例句: %s\n", offset+i+1, word.W, word.C, word.Phrase)
+		} else {
+			fmt.Printf("%d. %s - %s\n", offset+i+1, word.W, word.C)
+		}
+		if word.Mistake != nil {
+			fmt.Printf("   答错 %d 次，最近一次: %s，来自章节: %s\n",
+				word.Mistake.WrongCount, word.Mistake.LastWrong.Format("2006-01-02 15:04"), word.Mistake.Source)
+		}
+	}
+	return resp, nil
+}
+
+// SectionWords 按ListWords的顺序分页读取章节中的全部单词，不输出列表，序号与ListWords显示的一致
+func (s *Service) SectionWords(section string) ([]model.WordEntity, error) {
+	var words []model.WordEntity
+	for page := 1; ; page++ {
+		resp, err := s.pageWords(&model.ListWordsRequest{Section: section, Page: page, Size: worksheetPageSize})
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, resp.Words...)
+		if !resp.HasNext {
+			return words, nil
+		}
+	}
+}
+
+// pageWords 查询章节中一页的单词，页码超出范围时返回最后一页
+func (s *Service) pageWords(req *model.ListWordsRequest) (*model.ListWordsResponse, error) {
 	ctx := context.Background()
 	
-	// 获取章节
-	section, err := s.sectionDAO.GetSection(ctx, req.Section)
+	// 只查询当前页的单词
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	query := &model.WordQuery{
+		Sections: []string{req.Section},
+		Offset:   (req.Page - 1) * req.Size,
+		Limit:    req.Size,
+	}
+	result, err := s.sectionDAO.QueryWords(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("获取章节失败: %w", err)
 	}
 	
-	// 计算分页
-	total := len(section.Words)
+	// 计算分页，页码超出范围时显示最后一页
+	total := result.Total
 	totalPages := int(math.Ceil(float64(total) / float64(req.Size)))
-	if req.Page > totalPages {
+	if totalPages > 0 && req.Page > totalPages {
 		req.Page = totalPages
-	}
-	if req.Page < 1 {
-		req.Page = 1
+		query.Offset = (req.Page - 1) * req.Size
+		if result, err = s.sectionDAO.QueryWords(ctx, query); err != nil {
+			return nil, fmt.Errorf("获取章节失败: %w", err)
+		}
 	}
 	
-	start := (req.Page - 1) * req.Size
-	end := start + req.Size
-	
-	if start >= total {
+	if len(result.Words) == 0 {
 		return &model.ListWordsResponse{
 			Words:       []model.WordEntity{},
 			Total:       total,
@@ -91,22 +249,8 @@ func (s *Service) ListWords(req *model.ListWordsRequest) (*model.ListWordsRespon
 		}, nil
 	}
 	
-	if end > total {
-		end = total
-	}
-	
-	words := section.Words[start:end]
-	fmt.Printf("章节 %s 第%d页单词列表 (第%d页/共%d页):\n", req.Section, req.Page, req.Page, totalPages)
-	for i, word := range words {
-		if word.Phrase != "" {
-			fmt.Printf("%d. %s - %s\n   例句: %s\n", start+i+1, word.W, word.C, word.Phrase)
-		} else {
-			fmt.Printf("%d. %s - %s\n", start+i+1, word.W, word.C)
-		}
-	}
-	
 	return &model.ListWordsResponse{
-		Words:       words,
+		Words:       matchedWords(result),
 		Total:       total,
 		CurrentPage: req.Page,
 		TotalPages:  totalPages,
@@ -115,6 +259,15 @@ func (s *Service) ListWords(req *model.ListWordsRequest) (*model.ListWordsRespon
 	}, nil
 }
 
+// matchedWords 取出查询结果中的单词
+func matchedWords(result *model.WordQueryResult) []model.WordEntity {
+	words := make([]model.WordEntity, 0, len(result.Words))
+	for _, match := range result.Words {
+		words = append(words, match.Word)
+	}
+	return words
+}
+
 // RandomWords 随机练习单词
 func (s *Service) RandomWords(req *model.RandomWordsRequest) (*model.RandomWordsResponse, error) {
 	ctx := context.Background()
@@ -132,16 +285,20 @@ func (s *Service) RandomWords(req *model.RandomWordsRequest) (*model.RandomWords
 		}, fmt.Errorf("章节 '%s' 中没有单词", req.Section)
 	}
 	
-	// 简单的随机选择（这里可以后续优化为更好的随机算法）
 	count := req.Count
 	if count > len(section.Words) {
 		count = len(section.Words)
 	}
 	
-	// 取前count个单词作为示例（实际应该随机选择）
-	randomWords := section.Words[:count]
+	// 不重复抽样，指定种子时结果可重现
+	seed := time.Now().UnixNano()
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	rng := rand.New(rand.NewSource(seed))
+	randomWords := sampleWords(section.Words, count, rng, req.Weighted)
 	
-	fmt.Printf("从章节 %s 随机选择 %d 个单词进行练习:\n", req.Section, count)
+	fmt.Printf("从章节 %s 随机选择 %d 个单词进行练习 (随机种子: %d):\n", req.Section, count, seed)
 	for i, word := range randomWords {
 		if word.Phrase != "" {
 			fmt.Printf("%d. %s - %s\n   例句: %s\n", i+1, word.W, word.C, word.Phrase)
@@ -153,42 +310,27 @@ func (s *Service) RandomWords(req *model.RandomWordsRequest) (*model.RandomWords
 	return &model.RandomWordsResponse{
 		Words: randomWords,
 		Count: count,
+		Seed:  seed,
 	}, nil
 }
 
-// SearchWord 搜索单词
+// SearchWord 搜索单词，单词或释义包含关键词即命中（不区分大小写）
 func (s *Service) SearchWord(req *model.SearchWordRequest) (*model.SearchWordResponse, error) {
 	ctx := context.Background()
 	
-	var searchWords []model.WordEntity
-	
+	query := &model.WordQuery{Keyword: req.Keyword}
 	if req.Section != "" {
 		// 在指定章节中搜索
-		section, err := s.sectionDAO.GetSection(ctx, req.Section)
-		if err != nil {
+		query.Sections = []string{req.Section}
+	}
+	result, err := s.sectionDAO.QueryWords(ctx, query)
+	if err != nil {
+		if req.Section != "" {
 			return nil, fmt.Errorf("获取章节失败: %w", err)
 		}
-		
-		for _, word := range section.Words {
-			if contains(word.W, req.Keyword) || contains(word.C, req.Keyword) {
-				searchWords = append(searchWords, word)
-			}
-		}
-	} else {
-		// 在所有章节中搜索
-		allSections, err := s.sectionDAO.ListSections(ctx)
-		if err != nil {
-			return nil, fmt.Errorf("获取所有章节失败: %w", err)
-		}
-		
-		for _, section := range allSections {
-			for _, word := range section.Words {
-				if contains(word.W, req.Keyword) || contains(word.C, req.Keyword) {
-					searchWords = append(searchWords, word)
-				}
-			}
-		}
+		return nil, fmt.Errorf("搜索单词失败: %w", err)
 	}
+	searchWords := matchedWords(result)
 	
 	fmt.Printf("搜索关键词 '%s' 找到 %d 个结果:\n", req.Keyword, len(searchWords))
 	for i, word := range searchWords {
@@ -205,51 +347,22 @@ func (s *Service) SearchWord(req *model.SearchWordRequest) (*model.SearchWordRes
 	}, nil
 }
 
-// contains 简单的字符串包含检查（不区分大小写）
-func contains(str, substr string) bool {
-	return len(str) >= len(substr) && 
-		   (str == substr || 
-		    len(substr) == 0 || 
-		    indexOfIgnoreCase(str, substr) >= 0)
-}
-
-// indexOfIgnoreCase 不区分大小写的字符串查找
-func indexOfIgnoreCase(str, substr string) int {
-	strLower := toLower(str)
-	substrLower := toLower(substr)
-	
-	for i := 0; i <= len(strLower)-len(substrLower); i++ {
-		if strLower[i:i+len(substrLower)] == substrLower {
-			return i
-		}
-	}
-	return -1
-}
-
-// toLower 简单的转小写函数
-func toLower(s string) string {
-	result := make([]byte, len(s))
-	for i, b := range []byte(s) {
-		if b >= 'A' && b <= 'Z' {
-			result[i] = b + 32
-		} else {
-			result[i] = b
-		}
-	}
-	return string(result)
-}
-
 // ListSections 分页获取章节列表
 func (s *Service) ListSections(req *model.ListSectionsRequest) (*model.ListSectionsResponse, error) {
 	ctx := context.Background()
 	
-	// 获取所有章节
-	allSections, err := s.sectionDAO.ListSections(ctx)
+	// 只查询当前页的章节
+	page := req.Page
+	if page < 1 {
+		page = 1
+	}
+	query := &model.SectionQuery{Archived: req.Archived, Sort: req.Sort, Offset: (page - 1) * req.Size, Limit: req.Size}
+	result, err := s.sectionDAO.QuerySections(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("获取章节列表失败: %w", err)
 	}
 	
-	total := len(allSections)
+	total := result.Total
 	if total == 0 {
 		return &model.ListSectionsResponse{
 			Sections:    []model.SectionEntity{},
@@ -261,27 +374,19 @@ func (s *Service) ListSections(req *model.ListSectionsRequest) (*model.ListSecti
 		}, nil
 	}
 	
-	// 计算分页
+	// 计算分页，页码超出范围时显示最后一页
 	totalPages := int(math.Ceil(float64(total) / float64(req.Size)))
+	req.Page = page
 	if req.Page > totalPages {
 		req.Page = totalPages
+		query.Offset = (req.Page - 1) * req.Size
+		if result, err = s.sectionDAO.QuerySections(ctx, query); err != nil {
+			return nil, fmt.Errorf("获取章节列表失败: %w", err)
+		}
 	}
-	if req.Page < 1 {
-		req.Page = 1
-	}
-	
-	// 计算起始和结束索引
-	start := (req.Page - 1) * req.Size
-	end := start + req.Size
-	if end > total {
-		end = total
-	}
-	
-	// 获取当前页的章节
-	pageSections := allSections[start:end]
 	
 	return &model.ListSectionsResponse{
-		Sections:    pageSections,
+		Sections:    result.Sections,
 		Total:       total,
 		CurrentPage: req.Page,
 		TotalPages:  totalPages,
@@ -330,6 +435,11 @@ func (s *Service) GetCurrentSection() string {
 	return s.currentSection
 }
 
+// CurrentSection 获取当前章节名称，未选择章节时返回空字符串
+func (s *Service) CurrentSection() string {
+	return s.currentSection
+}
+
 // SetCurrentSection 设置当前章节
 func (s *Service) SetCurrentSection(section string) error {
 	s.currentSection = section
@@ -345,6 +455,10 @@ func (s *Service) CreateSection(req *model.CreateSectionRequest) error {
 	if req.Name == "" {
 		return fmt.Errorf("章节名称不能为空")
 	}
+
+	if err := checkManagedSection(req.Name, "手动创建"); err != nil {
+		return err
+	}
 	
 	// 检查章节是否已存在
 	exists, err := s.sectionDAO.SectionExists(ctx, req.Name)
@@ -370,4 +484,12 @@ func (s *Service) CreateSection(req *model.CreateSectionRequest) error {
 	
 	fmt.Printf("✓ 成功创建章节: %s\n", req.Name)
 	return nil
-}
\ No newline at end of file
+}
+
+// checkManagedSection 检查章节是否由程序自动维护（如错题本），这类章节不允许手动创建、删除、重命名或添加单词
+func checkManagedSection(name, action string) error {
+	if model.IsManagedSection(name) {
+		return fmt.Errorf("章节 '%s' 由程序自动维护，不能%s", name, action)
+	}
+	return nil
+}
@@ -2,10 +2,13 @@ package sections
 
 import (
 	"context"
+	"math/rand"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/ct-zh/englishLearn/config"
 	"github.com/ct-zh/englishLearn/internal/dao"
 	"github.com/ct-zh/englishLearn/model"
 )
@@ -15,7 +18,7 @@ func TestSectionsService(t *testing.T) {
 	tempDir := t.TempDir()
 	
 	// 创建DAO工厂和service
-	daoFactory := dao.NewDAOFactory(tempDir)
+	daoFactory := dao.NewDAOFactory(filepath.Join(tempDir, "sections.json"))
 	sectionDAO := daoFactory.GetSectionDAO()
 	service := NewService(sectionDAO)
 
@@ -143,6 +146,19 @@ func TestSectionsService(t *testing.T) {
 		if len(resp.Words) == 0 {
 			t.Error("期望获取到单词")
 		}
+
+		// 页码超出范围时显示最后一页
+		resp, err = service.ListWords(&model.ListWordsRequest{Section: "2024-01-01", Page: 99, Size: 1})
+		if err != nil {
+			t.Fatalf("获取单词列表失败: %v", err)
+		}
+		if resp.CurrentPage != resp.TotalPages || len(resp.Words) != 1 || resp.HasNext {
+			t.Errorf("期望显示最后一页，实际为第%d页/共%d页", resp.CurrentPage, resp.TotalPages)
+		}
+
+		if _, err := service.ListWords(&model.ListWordsRequest{Section: "不存在", Page: 1, Size: 10}); err == nil {
+			t.Error("章节不存在时应返回错误")
+		}
 	})
 
 	t.Run("RandomWords", func(t *testing.T) {
@@ -161,6 +177,43 @@ func TestSectionsService(t *testing.T) {
 		}
 	})
 
+	t.Run("RandomWordsSeeded", func(t *testing.T) {
+		ctx := context.Background()
+		for _, w := range []string{"alpha", "beta", "gamma", "delta", "epsilon"} {
+			if err := sectionDAO.AddWordToSection(ctx, "2024-01-02", model.WordEntity{W: w, C: w}); err != nil {
+				t.Fatalf("添加测试单词失败: %v", err)
+			}
+		}
+		
+		// 0也是有效的种子，同样可以重现
+		for _, seed := range []int64{42, 0} {
+			seed := seed
+			req := &model.RandomWordsRequest{Section: "2024-01-02", Count: 4, Seed: &seed}
+			first, err := service.RandomWords(req)
+			if err != nil {
+				t.Fatalf("随机获取单词失败: %v", err)
+			}
+			second, err := service.RandomWords(req)
+			if err != nil {
+				t.Fatalf("随机获取单词失败: %v", err)
+			}
+
+			seen := make(map[string]bool)
+			for i, word := range first.Words {
+				if seen[word.W] {
+					t.Errorf("单词 %s 被重复抽取", word.W)
+				}
+				seen[word.W] = true
+				if second.Words[i].W != word.W {
+					t.Errorf("种子%d的抽样结果不一致: %s != %s", seed, second.Words[i].W, word.W)
+				}
+			}
+			if first.Seed != seed {
+				t.Errorf("期望返回种子%d，实际为%d", seed, first.Seed)
+			}
+		}
+	})
+
 	t.Run("SearchWord", func(t *testing.T) {
 		req := &model.SearchWordRequest{
 			Keyword: "test",
@@ -175,6 +228,227 @@ func TestSectionsService(t *testing.T) {
 		if len(resp.Words) == 0 {
 			t.Error("期望搜索到单词")
 		}
+
+		// 在全部章节中搜索，不区分大小写
+		resp, err = service.SearchWord(&model.SearchWordRequest{Keyword: "TEST"})
+		if err != nil {
+			t.Fatalf("搜索单词失败: %v", err)
+		}
+		if resp.Total == 0 {
+			t.Error("搜索应不区分大小写")
+		}
+	})
+
+	t.Run("EditWord", func(t *testing.T) {
+		ctx := context.Background()
+		word := model.WordEntity{
+			W:      "dam",
+			C:      "水坝",
+			Phrase: "build a dam",
+			Senses: []model.Sense{{Meaning: "水坝", Examples: []string{"build a dam"}}, {Meaning: "母兽"}},
+		}
+		if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: "edit-a", Words: []model.WordEntity{word}}); err != nil {
+			t.Fatalf("创建测试章节失败: %v", err)
+		}
+		if err := service.CreateSection(&model.CreateSectionRequest{Name: "edit-b"}); err != nil {
+			t.Fatalf("创建测试章节失败: %v", err)
+		}
+
+		// 只修改释义，第一个义项随之更新，例句和其他义项保持不变
+		err := service.UpdateWord(&model.UpdateWordRequest{Section: "edit-a", Word: "dam", Translation: "水坝;堤坝"})
+		if err != nil {
+			t.Fatalf("编辑单词失败: %v", err)
+		}
+		section, _ := sectionDAO.GetSection(ctx, "edit-a")
+		edited := section.Words[0]
+		if edited.C != "水坝;堤坝" || edited.Senses[0].Meaning != "水坝;堤坝" || edited.Phrase != "build a dam" || edited.Senses[1].Meaning != "母兽" {
+			t.Errorf("编辑后的单词不正确: %+v", edited)
+		}
+
+		// 第一个义项与C、Phrase不一致时同样以编辑的内容为准，编辑后两者重新对应
+		diverged := model.WordEntity{W: "bid", C: "出价", Phrase: "make a bid",
+			Senses: []model.Sense{{Meaning: "投标", Examples: []string{"win the bid", "a bid for power"}}}}
+		applyWordEdit(&diverged, &model.UpdateWordRequest{Translation: "出价；投标", Phrase: "a winning bid"})
+		if diverged.Senses[0].Meaning != diverged.C || diverged.Senses[0].Examples[0] != diverged.Phrase ||
+			diverged.Phrase != "a winning bid" || len(diverged.Senses[0].Examples) != 2 {
+			t.Errorf("编辑后第一个义项应与释义和例句一致: %+v", diverged)
+		}
+
+		if err := service.UpdateWord(&model.UpdateWordRequest{Section: "edit-a", Word: "dam"}); err == nil {
+			t.Error("没有指定任何修改时应返回错误")
+		}
+		if err := service.UpdateWord(&model.UpdateWordRequest{Section: "edit-a", Word: "nope", NewWord: "x"}); err == nil {
+			t.Error("编辑不存在的单词应返回错误")
+		}
+
+		if err := service.CopyWord(&model.TransferWordRequest{Section: "edit-a", Word: "dam", Target: "edit-b"}); err != nil {
+			t.Fatalf("复制单词失败: %v", err)
+		}
+		if err := service.MoveWord(&model.TransferWordRequest{Section: "edit-a", Word: "dam", Target: "edit-b"}); err == nil {
+			t.Error("目标章节已有该单词时移动应返回错误")
+		}
+		if err := service.DeleteWord(&model.DeleteWordRequest{Section: "edit-b", Word: "dam"}); err != nil {
+			t.Fatalf("删除单词失败: %v", err)
+		}
+		if err := service.MoveWord(&model.TransferWordRequest{Section: "edit-a", Word: "dam", Target: "edit-b"}); err != nil {
+			t.Fatalf("移动单词失败: %v", err)
+		}
+
+		from, _ := sectionDAO.GetSection(ctx, "edit-a")
+		to, _ := sectionDAO.GetSection(ctx, "edit-b")
+		if len(from.Words) != 0 || len(to.Words) != 1 || to.Words[0].ID != edited.ID {
+			t.Errorf("移动后的章节不正确: %+v, %+v", from.Words, to.Words)
+		}
+	})
+
+	t.Run("ManagedSection", func(t *testing.T) {
+		err := service.CreateSection(&model.CreateSectionRequest{Name: model.MistakeSectionName})
+		if err == nil {
+			t.Error("不应允许手动创建错题本")
+		}
+
+		ctx := context.Background()
+		if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: model.MistakeSectionName}); err != nil {
+			t.Fatalf("创建错题本失败: %v", err)
+		}
+		err = service.AddWord(&model.AddWordRequest{Word: "dam", Translation: "水坝", Section: model.MistakeSectionName})
+		if err == nil {
+			t.Error("不应允许向错题本手动添加单词")
+		}
+		err = service.MoveWord(&model.TransferWordRequest{Section: "edit-b", Word: "dam", Target: model.MistakeSectionName})
+		if err == nil {
+			t.Error("不应允许手动将单词移入错题本")
+		}
+	})
+}
+
+func TestManageSections(t *testing.T) {
+	ctx := context.Background()
+	sectionDAO := dao.NewDAOFactory(filepath.Join(t.TempDir(), "sections.json")).GetSectionDAO()
+	service := NewService(sectionDAO)
+
+	words := func(texts ...string) []model.WordEntity {
+		result := make([]model.WordEntity, len(texts))
+		for i, text := range texts {
+			result[i] = model.WordEntity{W: text, C: text + "-释义"}
+		}
+		return result
+	}
+	names := func(section string) []string {
+		entity, err := sectionDAO.GetSection(ctx, section)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		var result []string
+		for _, word := range entity.Words {
+			result = append(result, word.W+"="+word.C)
+		}
+		return result
+	}
+	for name, texts := range map[string][]string{"a": {"w1", "w2", "w3", "w4", "w5"}, "b": {"w2", "x"}} {
+		if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: name, Words: words(texts...)}); err != nil {
+			t.Fatalf("创建测试章节失败: %v", err)
+		}
+	}
+
+	t.Run("Rename", func(t *testing.T) {
+		service.currentSection = "b"
+		if err := service.RenameSection(&model.RenameSectionRequest{Name: "b", NewName: "a"}); err == nil {
+			t.Error("重命名为已存在的章节应返回错误")
+		}
+		if err := service.RenameSection(&model.RenameSectionRequest{Name: "b", NewName: model.MistakeSectionName}); err == nil {
+			t.Error("不应允许重命名为错题本")
+		}
+		if err := service.RenameSection(&model.RenameSectionRequest{Name: "b", NewName: "c"}); err != nil {
+			t.Fatalf("重命名章节失败: %v", err)
+		}
+		if service.GetCurrentSection() != "c" {
+			t.Errorf("当前章节应随之更新，实际为 %s", service.GetCurrentSection())
+		}
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		resp, err := service.SplitSection(&model.SplitSectionRequest{Name: "a", Size: 2})
+		if err != nil {
+			t.Fatalf("按数量拆分章节失败: %v", err)
+		}
+		if strings.Join(resp.Created, ",") != "a-2,a-3" || len(names("a")) != 2 || len(names("a-3")) != 1 {
+			t.Errorf("按数量拆分的结果不正确: %v, %v, %v", resp.Created, names("a"), names("a-3"))
+		}
+
+		if _, err := service.SplitSection(&model.SplitSectionRequest{Name: "a-2", Words: []string{"w3", "nope"}, NewName: "d"}); err == nil {
+			t.Error("选择不存在的单词应返回错误")
+		}
+		if _, err := service.SplitSection(&model.SplitSectionRequest{Name: "a-2", Words: []string{"w4"}, NewName: "c"}); err == nil {
+			t.Error("新章节已存在时应返回错误")
+		}
+		if _, err := service.SplitSection(&model.SplitSectionRequest{Name: "a-2", Words: []string{"w4"}, NewName: "d"}); err != nil {
+			t.Fatalf("按单词拆分章节失败: %v", err)
+		}
+		if strings.Join(names("a-2"), ",") != "w3=w3-释义" || strings.Join(names("d"), ",") != "w4=w4-释义" {
+			t.Errorf("按单词拆分的结果不正确: %v, %v", names("a-2"), names("d"))
+		}
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		// 同名单词w2：skip保留目标章节中的释义
+		source, _ := sectionDAO.GetSection(ctx, "c")
+		source.Words[0].C = "新释义"
+		if err := sectionDAO.UpdateSection(ctx, "c", source); err != nil {
+			t.Fatalf("更新章节失败: %v", err)
+		}
+		if _, err := service.MergeSections(&model.MergeSectionsRequest{Source: "c", Target: "a", Policy: "bad"}); err == nil {
+			t.Error("不支持的处理方式应返回错误")
+		}
+
+		resp, err := service.MergeSections(&model.MergeSectionsRequest{Source: "c", Target: "a"})
+		if err != nil {
+			t.Fatalf("合并章节失败: %v", err)
+		}
+		if resp.Added != 1 || resp.Skipped != 1 || strings.Join(names("a"), ",") != "w1=w1-释义,w2=w2-释义,x=x-释义" {
+			t.Errorf("合并结果不正确: %+v, %v", resp, names("a"))
+		}
+		if exists, _ := sectionDAO.SectionExists(ctx, "c"); exists {
+			t.Error("合并后应删除来源章节")
+		}
+		if service.GetCurrentSection() != "a" {
+			t.Errorf("当前章节应改为目标章节，实际为 %s", service.GetCurrentSection())
+		}
+
+		// d中的w4较新，newer策略下覆盖a-3中的同名单词
+		if err := sectionDAO.AddWordToSection(ctx, "a-3", model.WordEntity{W: "w4", C: "旧释义"}); err != nil {
+			t.Fatalf("添加单词失败: %v", err)
+		}
+		other, _ := sectionDAO.GetSection(ctx, "d")
+		other.Words[0].C = "较新的释义"
+		if err := sectionDAO.UpdateSection(ctx, "d", other); err != nil {
+			t.Fatalf("更新章节失败: %v", err)
+		}
+		resp, err = service.MergeSections(&model.MergeSectionsRequest{Source: "d", Target: "a-3", Policy: model.DuplicateNewer})
+		if err != nil || resp.Overwritten != 1 || strings.Join(names("a-3"), ",") != "w5=w5-释义,w4=较新的释义" {
+			t.Errorf("newer策略的合并结果不正确: %v, %+v, %v", err, resp, names("a-3"))
+		}
+	})
+
+	t.Run("ArchiveAndDelete", func(t *testing.T) {
+		if err := service.ArchiveSection(&model.ArchiveSectionRequest{Name: "a-2", Archived: true}); err != nil {
+			t.Fatalf("归档章节失败: %v", err)
+		}
+		active, _ := service.ListSections(&model.ListSectionsRequest{Page: 1, Size: 10})
+		archived, _ := service.ListSections(&model.ListSectionsRequest{Page: 1, Size: 10, Archived: true})
+		if active.Total != 2 || archived.Total != 1 || archived.Sections[0].Name != "a-2" {
+			t.Errorf("归档后的章节列表不正确: %+v, %+v", active.Sections, archived.Sections)
+		}
+
+		if err := service.DeleteSection(&model.DeleteSectionRequest{Name: "a"}); err != nil {
+			t.Fatalf("删除章节失败: %v", err)
+		}
+		if service.GetCurrentSection() != "未选择章节" {
+			t.Errorf("删除当前章节后应清除当前章节，实际为 %s", service.GetCurrentSection())
+		}
+		if err := service.DeleteSection(&model.DeleteSectionRequest{Name: model.MistakeSectionName}); err == nil {
+			t.Error("不应允许删除错题本")
+		}
 	})
 }
 
@@ -188,8 +462,16 @@ func TestSectionsServiceWithRealData(t *testing.T) {
 		return
 	}
 	
-	// 使用真实数据进行测试
-	daoFactory := dao.NewDAOFactory(filepath.Join(projectRoot, "data"))
+	// 复制到临时目录进行测试，避免加载时的格式迁移修改项目中的数据文件
+	raw, err := os.ReadFile(dataPath)
+	if err != nil {
+		t.Fatalf("读取数据文件失败: %v", err)
+	}
+	tempFile := filepath.Join(t.TempDir(), "sections.json")
+	if err := os.WriteFile(tempFile, raw, 0644); err != nil {
+		t.Fatalf("复制数据文件失败: %v", err)
+	}
+	daoFactory := dao.NewDAOFactory(tempFile)
 	sectionDAO := daoFactory.GetSectionDAO()
 	service := NewService(sectionDAO)
 
@@ -210,4 +492,547 @@ func TestSectionsServiceWithRealData(t *testing.T) {
 			t.Logf("章节 %d: %s (包含 %d 个单词)", i+1, section.Name, len(section.Words))
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestReloadDataFile(t *testing.T) {
+	tempDir := t.TempDir()
+	firstFile := filepath.Join(tempDir, "first.json")
+	secondFile := filepath.Join(tempDir, "mywords.json")
+	if err := os.WriteFile(firstFile, []byte(`{"first": [{"W": "dam", "C": "水坝", "Phrase": ""}]}`), 0644); err != nil {
+		t.Fatalf("写入数据文件失败: %v", err)
+	}
+	if err := os.WriteFile(secondFile, []byte(`{"second": []}`), 0644); err != nil {
+		t.Fatalf("写入数据文件失败: %v", err)
+	}
+
+	// Service只在创建时获取一次DAO
+	daoFactory := dao.NewDAOFactoryWithConfig(&config.Config{DataFilePath: firstFile})
+	service := NewService(daoFactory.GetSectionDAO())
+
+	sectionNames := func() []string {
+		t.Helper()
+		resp, err := service.ListSections(&model.ListSectionsRequest{Page: 1, Size: 10})
+		if err != nil {
+			t.Fatalf("获取章节列表失败: %v", err)
+		}
+		names := make([]string, 0, len(resp.Sections))
+		for _, section := range resp.Sections {
+			names = append(names, section.Name)
+		}
+		return names
+	}
+
+	if names := sectionNames(); len(names) != 1 || names[0] != "first" {
+		t.Fatalf("期望读取first.json，实际章节为%v", names)
+	}
+
+	// 切换文件后，已创建的Service应使用新文件，且读写的是指定的文件名
+	if err := daoFactory.ReloadDataFile(secondFile); err != nil {
+		t.Fatalf("切换数据文件失败: %v", err)
+	}
+	if names := sectionNames(); len(names) != 1 || names[0] != "second" {
+		t.Fatalf("切换后期望读取mywords.json，实际章节为%v", names)
+	}
+	if err := service.AddWord(&model.AddWordRequest{Word: "bid", Translation: "中标", Section: "second"}); err != nil {
+		t.Fatalf("添加单词失败: %v", err)
+	}
+	section, err := dao.NewSectionDAO(secondFile).GetSection(context.Background(), "second")
+	if err != nil || len(section.Words) != 1 {
+		t.Errorf("单词应写入mywords.json: %+v, %v", section, err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "sections.json")); !os.IsNotExist(err) {
+		t.Error("不应读写数据文件所在目录下的sections.json")
+	}
+
+	// 回滚后回到原来的文件
+	if err := daoFactory.RollbackDataFile(); err != nil {
+		t.Fatalf("回滚数据文件失败: %v", err)
+	}
+	if names := sectionNames(); len(names) != 1 || names[0] != "first" {
+		t.Fatalf("回滚后期望读取first.json，实际章节为%v", names)
+	}
+}
+
+func TestSampleWordsWeighted(t *testing.T) {
+	words := []model.WordEntity{
+		{W: "easy", Review: &model.ReviewState{Reviews: 10}},
+		{W: "hard", Review: &model.ReviewState{Reviews: 10, Lapses: 10}},
+		{W: "new"},
+	}
+	
+	// 多次单个抽样，错误率高的单词应明显更常被抽中
+	counts := make(map[string]int)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 3000; i++ {
+		picked := sampleWords(words, 1, rng, true)
+		counts[picked[0].W]++
+	}
+	
+	if counts["hard"] <= counts["easy"]*3 {
+		t.Errorf("期望易错单词被抽中的次数远多于其他单词: %v", counts)
+	}
+	
+	// 抽取全部单词时不应重复或遗漏
+	all := sampleWords(words, 10, rng, true)
+	if len(all) != len(words) {
+		t.Errorf("期望抽取%d个单词，实际%d个", len(words), len(all))
+	}
+}
+
+func TestImportExport(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	daoFactory := dao.NewDAOFactory(filepath.Join(tempDir, "sections.json"))
+	sectionDAO := daoFactory.GetSectionDAO()
+	service := NewService(sectionDAO)
+
+	if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: "a", Words: []model.WordEntity{
+		{W: "apple", C: "苹果"},
+		{W: "bear", C: "熊"},
+	}}); err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	// Excel保存的CSV：带BOM和中文表头，包含重复、冲突、缺少释义和新章节的行
+	csvFile := filepath.Join(tempDir, "words.csv")
+	content := utf8BOM + "单词,释义,例句,章节\n" +
+		"apple,苹果,,a\n" +
+		"bear,承受,bear the pain,a\n" +
+		"cat,猫,\"a cat, a dog\",a\n" +
+		"\n" +
+		"dog,,,b\n" +
+		"egg,鸡蛋,,b\n" +
+		"cat,小猫,,a\n"
+	if err := os.WriteFile(csvFile, []byte(content), 0644); err != nil {
+		t.Fatalf("写入导入文件失败: %v", err)
+	}
+
+	preview, err := service.ImportWords(&model.ImportWordsRequest{Path: csvFile, DryRun: true})
+	if err != nil {
+		t.Fatalf("预览导入失败: %v", err)
+	}
+	if preview.Total != 6 || len(preview.New) != 2 || len(preview.Duplicates) != 1 ||
+		len(preview.Conflicts) != 2 || len(preview.Invalid) != 1 || preview.Added != 0 {
+		t.Fatalf("预览结果不正确: %+v", preview)
+	}
+	if preview.Invalid[0].Line != 6 || strings.Join(preview.NewSections, ",") != "b" {
+		t.Errorf("无法导入的行或新章节不正确: %+v, %v", preview.Invalid, preview.NewSections)
+	}
+	if preview.New[0].Word.Phrase != "a cat, a dog" {
+		t.Errorf("带引号的单元格解析不正确: %q", preview.New[0].Word.Phrase)
+	}
+	if exists, _ := sectionDAO.SectionExists(ctx, "b"); exists {
+		t.Fatal("预览时不应写入")
+	}
+
+	resp, err := service.ImportWords(&model.ImportWordsRequest{Path: csvFile})
+	if err != nil || resp.Added != 2 {
+		t.Fatalf("导入失败: %+v, %v", resp, err)
+	}
+	section, err := sectionDAO.GetSection(ctx, "b")
+	if err != nil || len(section.Words) != 1 || section.Words[0].W != "egg" {
+		t.Fatalf("新章节中的单词不正确: %+v, %v", section, err)
+	}
+	historyDAO := daoFactory.GetHistoryDAO()
+	entries, err := historyDAO.ListHistory(ctx)
+	// 创建章节a和整个导入各一条修改记录
+	if err != nil || len(entries) != 2 || entries[0].Action != "从 words.csv 导入 2 个单词" {
+		t.Fatalf("一次导入应只保存一条修改记录: %+v, %v", entries, err)
+	}
+
+	// 一次撤销整个导入，撤销“撤销”后恢复导入的单词
+	if _, err := historyDAO.RestoreHistory(ctx, entries[0].ID); err != nil {
+		t.Fatalf("撤销导入失败: %v", err)
+	}
+	if exists, _ := sectionDAO.SectionExists(ctx, "b"); exists {
+		t.Error("撤销导入后应删除导入时新建的章节")
+	}
+	entries, _ = historyDAO.ListHistory(ctx)
+	if _, err := historyDAO.RestoreHistory(ctx, entries[0].ID); err != nil {
+		t.Fatalf("撤销“撤销导入”失败: %v", err)
+	}
+
+	// 导出为TSV后按同样的列重新导入，所有单词都应识别为重复
+	tsvFile := filepath.Join(tempDir, "export.tsv")
+	exported, err := service.ExportWords(&model.ExportWordsRequest{
+		Path:    tsvFile,
+		Columns: []model.ColumnField{model.ColumnSection, model.ColumnWord, model.ColumnMeaning, model.ColumnPhrase},
+	})
+	if err != nil || exported.Sections != 2 || exported.Words != 4 {
+		t.Fatalf("导出失败: %+v, %v", exported, err)
+	}
+	data, _ := os.ReadFile(tsvFile)
+	if !strings.HasPrefix(string(data), "section\tword\tmeaning\tphrase\n") {
+		t.Errorf("导出的表头不正确: %q", data)
+	}
+	again, err := service.ImportWords(&model.ImportWordsRequest{Path: tsvFile, DryRun: true})
+	if err != nil || len(again.Duplicates) != 4 || len(again.New)+len(again.Conflicts)+len(again.Invalid) != 0 {
+		t.Errorf("重新导入导出的文件应全部为重复: %+v, %v", again, err)
+	}
+
+	// 没有表头时通过列映射指定各列，section参数作为默认章节
+	plainFile := filepath.Join(tempDir, "plain.tsv")
+	os.WriteFile(plainFile, []byte("苹果\tapple\tx\n鱼\tfish\ty\n"), 0644)
+	columns, err := ParseColumns("meaning,word,-")
+	if err != nil {
+		t.Fatalf("解析列映射失败: %v", err)
+	}
+	mapped, err := service.ImportWords(&model.ImportWordsRequest{Path: plainFile, Columns: columns, Section: "a", DryRun: true})
+	if err != nil || len(mapped.Duplicates) != 1 || len(mapped.New) != 1 || mapped.New[0].Word.C != "鱼" {
+		t.Errorf("按列映射导入不正确: %+v, %v", mapped, err)
+	}
+	if _, err := ParseColumns("word,unknown"); err == nil {
+		t.Error("未知的列应返回错误")
+	}
+}
+
+func TestAnkiNotes(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	sectionDAO := dao.NewDAOFactory(filepath.Join(tempDir, "sections.json")).GetSectionDAO()
+	service := NewService(sectionDAO)
+
+	if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: "R&D <1>", Words: []model.WordEntity{
+		{W: "ampersand", C: "&符号", Phrase: "Use <b>&</b> in \"names\".\nSecond line", Tags: []string{"symbol", "hard word"}},
+		{W: "bear", C: "熊"},
+	}}); err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	ankiFile := filepath.Join(tempDir, "notes.txt")
+	if _, err := service.ExportWords(&model.ExportWordsRequest{Path: ankiFile, Format: model.FormatAnki}); err != nil {
+		t.Fatalf("导出Anki笔记失败: %v", err)
+	}
+	data, _ := os.ReadFile(ankiFile)
+	lines := strings.Split(string(data), "\n")
+	if lines[0] != "#separator:tab" || lines[1] != "#html:true" || !strings.Contains(string(data), "#deck column:4\n") {
+		t.Fatalf("缺少Anki文件头: %q", data)
+	}
+	want := "ampersand\t&amp;符号\tUse &lt;b&gt;&amp;&lt;/b&gt; in &#34;names&#34;.<br>Second line\tR&D <1>\tsymbol hard_word"
+	if lines[5] != want {
+		t.Errorf("导出的笔记不正确:\n%s\n期望:\n%s", lines[5], want)
+	}
+
+	if strings.Contains(string(data), "#tags:") {
+		t.Errorf("不应写入 #tags 文件头: %q", data)
+	}
+
+	// 导出的文件不指定格式也能识别，牌组原样读回，重新导入时全部为重复
+	again, err := service.ImportWords(&model.ImportWordsRequest{Path: ankiFile, DryRun: true})
+	if err != nil || len(again.Duplicates) != 2 || again.Total != 2 {
+		t.Fatalf("重新导入导出的笔记应全部为重复: %+v, %v", again, err)
+	}
+
+	// Anki导出的笔记：#deck 指定牌组，没有 #columns 时依次为单词、释义，最后一列为标签
+	plain := "#separator:Semicolon\n#html:true\n#deck:Anki::Daily\n#tags:imported\n#tags column:3\n" +
+		"river;河流<br/>江;nature water\n" +
+		"\"semi;colon\";分号;\n"
+	plainFile := filepath.Join(tempDir, "anki.txt")
+	os.WriteFile(plainFile, []byte(plain), 0644)
+	resp, err := service.ImportWords(&model.ImportWordsRequest{Path: plainFile})
+	if err != nil || resp.Added != 2 {
+		t.Fatalf("导入Anki笔记失败: %+v, %v", resp, err)
+	}
+	section, err := sectionDAO.GetSection(ctx, "Anki::Daily")
+	if err != nil || len(section.Words) != 2 {
+		t.Fatalf("牌组应导入为章节: %+v, %v", section, err)
+	}
+	river := section.Words[0]
+	if river.W != "river" || river.C != "河流\n江" || strings.Join(river.Tags, ",") != "nature,water,imported" {
+		t.Errorf("解析的单词不正确: %+v", river)
+	}
+	if section.Words[1].W != "semi;colon" || strings.Join(section.Words[1].Tags, ",") != "imported" {
+		t.Errorf("带引号的字段解析不正确: %+v", section.Words[1])
+	}
+	if resp.Invalid != nil || resp.New[0].Line != 6 {
+		t.Errorf("行号应包含文件头: %+v", resp)
+	}
+}
+
+func TestExportWorksheet(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	sectionDAO := dao.NewDAOFactory(filepath.Join(tempDir, "sections.json")).GetSectionDAO()
+	service := NewService(sectionDAO)
+
+	words := []model.WordEntity{{W: "a<b>", C: "甲", Phrase: "x & y"}, {W: "bb", C: "乙"}, {W: "cc", C: "丙"}, {W: "dd", C: "丁|戊"}}
+	if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: "quiz", Words: words}); err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+	export := func(req *model.ExportWordsRequest) string {
+		req.Path = filepath.Join(tempDir, "out."+string(req.Format))
+		resp, err := service.ExportWords(req)
+		if err != nil || resp.Words != len(words) {
+			t.Fatalf("导出失败: %+v, %v", resp, err)
+		}
+		data, _ := os.ReadFile(req.Path)
+		return string(data)
+	}
+
+	// 隐藏释义时第一页只有单词，答案在单独的一页
+	page := export(&model.ExportWordsRequest{Format: model.FormatHTML, HideMeanings: true})
+	sheet, answers, found := strings.Cut(page, "quiz 答案")
+	if !found || strings.Contains(sheet, "甲") || !strings.Contains(answers, "甲") {
+		t.Errorf("隐藏释义的练习纸不正确:\n%s", page)
+	}
+	if !strings.Contains(page, "a&lt;b&gt;") || !strings.Contains(page, "x &amp; y") || !strings.Contains(page, "@page") {
+		t.Errorf("HTML没有转义或缺少打印样式:\n%s", page)
+	}
+
+	// 单词卡片的背面每行顺序相反，最后一行用空卡片补齐
+	cards := export(&model.ExportWordsRequest{Format: model.FormatMD, Layout: model.LayoutCards})
+	front, back, _ := strings.Cut(cards, "（背面）")
+	if !strings.Contains(front, "| **a&lt;b&gt;** | **bb** | **cc** |") {
+		t.Errorf("单词卡片正面不正确:\n%s", cards)
+	}
+	if !strings.Contains(back, "| 丙 | 乙 | 甲<br>*x & y* |\n| | | 丁\\|戊 |") {
+		t.Errorf("单词卡片背面不正确:\n%s", cards)
+	}
+
+	if _, err := service.ExportWords(&model.ExportWordsRequest{Format: model.FormatMD, Layout: "poster"}); err == nil {
+		t.Error("不支持的排版应返回错误")
+	}
+	if _, err := service.ImportWords(&model.ImportWordsRequest{Path: filepath.Join(tempDir, "out.md")}); err == nil {
+		t.Error("不应支持从Markdown导入")
+	}
+}
+
+func TestParseWordList(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		layout string
+		want   []string // 单词|释义|例句，无法导入的行为 !原因
+	}{
+		{
+			name:   "分隔符",
+			text:   "palatable - 可口的 - The girl found this dish palatable.\n\n水坝 - dam\n1. bleak - 荒凉的\nlonely 孤独的",
+			layout: `以 " - " 分隔`,
+			want: []string{
+				"palatable|可口的|The girl found this dish palatable.",
+				"dam|水坝|",
+				"bleak|荒凉的|",
+				"lonely|孤独的|",
+			},
+		},
+		{
+			name:   "中英文分界",
+			text:   "# 第一课\ndam 水坝;堤坝\npalatable adj. 可口的 The girl found this dish palatable.\ncleared out 清理（房间） \n- oops\n水坝 dam",
+			layout: "按中英文分界",
+			want: []string{
+				"dam|水坝;堤坝|",
+				"palatable|可口的|The girl found this dish palatable.|adj.",
+				"cleared out|清理（房间）|",
+				"!缺少释义",
+				"dam|水坝|",
+			},
+		},
+		{
+			name:   "制表符",
+			text:   "dam\t水坝\nbleak\t荒凉的\t a bleak landscape",
+			layout: `以 "\t" 分隔`,
+			want:   []string{"dam|水坝|", "bleak|荒凉的|a bleak landscape"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := ParseWordList(tt.text)
+			if list.Layout != tt.layout {
+				t.Errorf("识别的格式为%s，期望%s", list.Layout, tt.layout)
+			}
+			var got []string
+			for _, item := range list.Words {
+				if item.Issue != "" {
+					got = append(got, "!"+item.Issue)
+					continue
+				}
+				line := item.Word.W + "|" + item.Word.C + "|" + item.Word.Phrase
+				if item.Word.POS != "" {
+					line += "|" + item.Word.POS
+				}
+				got = append(got, line)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("解析结果:\n%s\n期望:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	// 修改有问题的行后导入到新章节，.txt文件自动按单词列表导入
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	sectionDAO := dao.NewDAOFactory(filepath.Join(tempDir, "sections.json")).GetSectionDAO()
+	service := NewService(sectionDAO)
+
+	list := ParseWordList("dam 水坝\noops")
+	list.Words[1].Word.C = "哎呀"
+	resp, err := service.ImportParsedWords(&model.ImportParsedWordsRequest{Section: "pasted", Words: list.Words})
+	if err != nil || resp.Added != 2 || len(resp.NewSections) != 1 {
+		t.Fatalf("导入修改后的单词失败: %+v, %v", resp, err)
+	}
+	textFile := filepath.Join(tempDir, "list.txt")
+	os.WriteFile(textFile, []byte("dam - 水坝\nbleak - 荒凉的\n"), 0644)
+	if format, err := DetectFileFormat(textFile); err != nil || format != model.FormatText {
+		t.Errorf("应识别为单词列表: %s, %v", format, err)
+	}
+	resp, err = service.ImportWords(&model.ImportWordsRequest{Path: textFile, Section: "pasted"})
+	if err != nil || resp.Added != 1 || len(resp.Duplicates) != 1 {
+		t.Fatalf("导入单词列表文件失败: %+v, %v", resp, err)
+	}
+	if section, _ := sectionDAO.GetSection(ctx, "pasted"); len(section.Words) != 3 {
+		t.Errorf("章节中应有3个单词: %+v", section)
+	}
+
+	// 个别行缺少制表符的.txt表格仍按表格导入，缺少制表符的行报告为无法导入
+	tableFile := filepath.Join(tempDir, "table.txt")
+	os.WriteFile(tableFile, []byte("cue\t提示\nkiwi\t猕猴桃\nbroken row\nbid\t出价\n"), 0644)
+	if format, err := DetectFileFormat(tableFile); err != nil || format != model.FormatTSV {
+		t.Errorf("多数行有制表符时应识别为表格: %s, %v", format, err)
+	}
+	resp, err = service.ImportWords(&model.ImportWordsRequest{Path: tableFile, Section: "table", DryRun: true})
+	if err != nil || len(resp.New) != 3 || len(resp.Invalid) != 1 || resp.Invalid[0].Line != 3 {
+		t.Errorf("导入表格结果不正确: %+v, %v", resp, err)
+	}
+	// 指定了列时不再按单词列表解析
+	resp, err = service.ImportWords(&model.ImportWordsRequest{Path: textFile, Section: "table", DryRun: true,
+		Columns: []model.ColumnField{model.ColumnWord, model.ColumnMeaning}})
+	if err != nil || len(resp.New) != 0 || len(resp.Invalid) != 2 {
+		t.Errorf("指定列时应按表格导入: %+v, %v", resp, err)
+	}
+}
+
+func TestMergeDataFile(t *testing.T) {
+	ctx := context.Background()
+	tempDir := t.TempDir()
+	daoFactory := dao.NewDAOFactory(filepath.Join(tempDir, "ours.json"))
+	sectionDAO := daoFactory.GetSectionDAO()
+	service := NewService(sectionDAO)
+
+	review := &model.ReviewState{Repetitions: 3}
+	if err := sectionDAO.CreateSection(ctx, &model.SectionEntity{Name: "a", Words: []model.WordEntity{
+		{W: "dam", C: "水坝", Review: review},
+		{W: "bleak", C: "荒凉的", Phrase: "a bleak landscape"},
+		{W: "same", C: "相同"},
+	}}); err != nil {
+		t.Fatalf("创建测试章节失败: %v", err)
+	}
+
+	theirsFile := filepath.Join(tempDir, "theirs.json")
+	theirsDAO := dao.NewSectionDAO(theirsFile)
+	for _, section := range []*model.SectionEntity{
+		{Name: "a", Words: []model.WordEntity{
+			{W: "Dam ", C: "堤坝", Phrase: "build a dam"},
+			{W: "bleak", C: "荒凉的", Phrase: "bleak prospects"},
+			{W: "same", C: "相同"},
+			{W: "new", C: "新的", Review: review},
+		}},
+		{Name: "b", Words: []model.WordEntity{{W: "kiwi", C: "猕猴桃"}, {W: "Kiwi ", C: "猕猴桃"}}},
+		{Name: model.MistakeSectionName, Words: []model.WordEntity{{W: "oops", C: "哎呀"}}},
+	} {
+		if err := theirsDAO.CreateSection(ctx, section); err != nil {
+			t.Fatalf("创建对方章节失败: %v", err)
+		}
+	}
+
+	preview, err := service.MergeDataFile(&model.MergeFileRequest{Path: theirsFile, Policy: model.ConflictAsk, DryRun: true})
+	if err != nil {
+		t.Fatalf("预览合并失败: %v", err)
+	}
+	// 新章节b中重复的kiwi只添加一次
+	if preview.Sections != 3 || preview.Added != 2 || preview.Identical != 2 || len(preview.Conflicts) != 2 ||
+		strings.Join(preview.NewSections, ",") != "b" || strings.Join(preview.SkippedSections, ",") != model.MistakeSectionName {
+		t.Fatalf("预览结果不正确: %+v", preview)
+	}
+	if exists, _ := sectionDAO.SectionExists(ctx, "b"); exists {
+		t.Fatal("预览时不应写入")
+	}
+	if _, err := service.MergeDataFile(&model.MergeFileRequest{Path: theirsFile, Policy: model.ConflictAsk}); err == nil {
+		t.Error("逐个询问时没有Decide应返回错误")
+	}
+
+	// 逐个询问：dam合并释义，bleak使用对方的例句
+	resp, err := service.MergeDataFile(&model.MergeFileRequest{
+		Path:   theirsFile,
+		Policy: model.ConflictAsk,
+		Decide: func(conflict *model.MergeConflict) model.ConflictPolicy {
+			if conflict.Ours.W == "dam" {
+				return model.ConflictCombine
+			}
+			return model.ConflictKeepTheirs
+		},
+	})
+	if err != nil || resp.Combined != 1 || resp.TookTheirs != 1 {
+		t.Fatalf("合并失败: %+v, %v", resp, err)
+	}
+
+	section, err := sectionDAO.GetSection(ctx, "a")
+	if err != nil || len(section.Words) != 4 {
+		t.Fatalf("合并后的章节不正确: %+v, %v", section, err)
+	}
+	dam, bleak, added := section.Words[0], section.Words[1], section.Words[3]
+	if dam.C != "水坝；堤坝" || dam.Phrase != "build a dam" || len(dam.Senses) != 1 || dam.Senses[0].Meaning != dam.C || dam.Review == nil || dam.Review.Repetitions != 3 {
+		t.Errorf("合并释义不正确: %+v", dam)
+	}
+	if bleak.Phrase != "bleak prospects" {
+		t.Errorf("应使用对方的例句: %+v", bleak)
+	}
+	if added.W != "new" || added.Review != nil {
+		t.Errorf("新单词不应带有对方的复习进度: %+v", added)
+	}
+	if created, err := sectionDAO.GetSection(ctx, "b"); err != nil || len(created.Words) != 1 {
+		t.Errorf("应新建只有一个单词的章节b: %+v, %v", created, err)
+	}
+	entries, err := daoFactory.GetHistoryDAO().ListHistory(ctx)
+	if err != nil || len(entries) != 2 || entries[0].Action != "合并数据文件 theirs.json" {
+		t.Errorf("一次合并应只保存一条修改记录: %+v, %v", entries, err)
+	}
+
+	// 再次合并时已经合并过释义的单词也视为相同
+	again, err := service.MergeDataFile(&model.MergeFileRequest{Path: theirsFile})
+	if err != nil || again.Added != 0 || len(again.Conflicts) != 0 || again.Identical != 6 {
+		t.Errorf("再次合并结果不正确: %+v, %v", again, err)
+	}
+	if combined := combineMeanings("水坝;堤坝", "堤坝、拦河坝"); combined != "水坝;堤坝;拦河坝" {
+		t.Errorf("合并释义为%s", combined)
+	}
+
+	// 按拆分后的意思匹配义项，不会因为释义写法不同而重复添加义项
+	ours := &model.WordEntity{W: "block", C: "阻塞；堵住", Senses: []model.Sense{
+		{Meaning: "阻塞；堵住", Examples: []string{"block the road"}},
+		{Meaning: "街区"},
+	}}
+	combineSenses(ours, &model.WordEntity{W: "block", C: "街区、街段", Senses: []model.Sense{
+		{Meaning: "街区、街段", Examples: []string{"walk two blocks"}},
+		{Meaning: "堵住"},
+		{Meaning: "积木"},
+	}})
+	if len(ours.Senses) != 3 || ours.C != ours.Senses[0].Meaning || ours.Senses[0].Meaning != "阻塞；堵住" ||
+		ours.Senses[1].Meaning != "街区、街段" || strings.Join(ours.Senses[1].Examples, ",") != "walk two blocks" ||
+		ours.Senses[2].Meaning != "积木" {
+		t.Errorf("合并义项不正确: %+v", ours)
+	}
+
+	// 旧格式的文件只在内存中迁移，合并不会修改对方的文件，也不会在旁边留下锁文件或备份
+	legacyDir := filepath.Join(tempDir, "legacy")
+	legacyFile := filepath.Join(legacyDir, "legacy.json")
+	legacy := []byte(`{"c": [{"W": "cue", "C": "提示", "Phrase": ""}]}`)
+	if err := os.Mkdir(legacyDir, 0755); err != nil {
+		t.Fatalf("创建目录失败: %v", err)
+	}
+	if err := os.WriteFile(legacyFile, legacy, 0644); err != nil {
+		t.Fatalf("写入旧格式文件失败: %v", err)
+	}
+	for _, dryRun := range []bool{true, false} {
+		resp, err := service.MergeDataFile(&model.MergeFileRequest{Path: legacyFile, DryRun: dryRun})
+		if err != nil || resp.Sections != 1 || strings.Join(resp.NewSections, ",") != "c" {
+			t.Fatalf("合并旧格式文件失败: %+v, %v", resp, err)
+		}
+	}
+	if raw, _ := os.ReadFile(legacyFile); string(raw) != string(legacy) {
+		t.Errorf("合并不应修改对方的文件: %s", raw)
+	}
+	if files, _ := os.ReadDir(legacyDir); len(files) != 1 {
+		t.Errorf("合并不应在对方文件旁创建文件，实际有%d个文件", len(files))
+	}
+}
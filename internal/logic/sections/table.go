@@ -0,0 +1,270 @@
+package sections
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// utf8BOM UTF-8 BOM，Excel保存的CSV文件通常以它开头
+const utf8BOM = "\ufeff"
+
+// defaultImportColumns 没有表头也没有指定列映射时各列对应的字段
+var defaultImportColumns = []model.ColumnField{
+	model.ColumnWord, model.ColumnMeaning, model.ColumnPhrase, model.ColumnSection, model.ColumnTags,
+}
+
+// defaultExportColumns 默认导出的列，导出多个章节时再加上章节列
+var defaultExportColumns = []model.ColumnField{model.ColumnWord, model.ColumnMeaning, model.ColumnPhrase}
+
+// columnAliases 列映射和表头中可以识别的列名（不区分大小写）
+var columnAliases = map[string]model.ColumnField{
+	"word": model.ColumnWord, "w": model.ColumnWord, "english": model.ColumnWord, "单词": model.ColumnWord, "英文": model.ColumnWord,
+	"meaning": model.ColumnMeaning, "c": model.ColumnMeaning, "translation": model.ColumnMeaning, "chinese": model.ColumnMeaning,
+	"释义": model.ColumnMeaning, "中文": model.ColumnMeaning, "意思": model.ColumnMeaning,
+	"phrase": model.ColumnPhrase, "example": model.ColumnPhrase, "sentence": model.ColumnPhrase, "例句": model.ColumnPhrase, "短语": model.ColumnPhrase,
+	"section": model.ColumnSection, "chapter": model.ColumnSection, "章节": model.ColumnSection,
+	"tags": model.ColumnTags, "tag": model.ColumnTags, "标签": model.ColumnTags,
+	"front": model.ColumnWord, "back": model.ColumnMeaning, "deck": model.ColumnSection, "牌组": model.ColumnSection,
+	"-": model.ColumnIgnore,
+}
+
+// tableRow 表格中的一行
+type tableRow struct {
+	line  int // 行号，从1开始
+	cells []string
+}
+
+// ParseColumns 解析以逗号分隔的列映射，如 "word,meaning,-,section"，"-"表示忽略该列
+func ParseColumns(spec string) ([]model.ColumnField, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var columns []model.ColumnField
+	for _, name := range strings.Split(spec, ",") {
+		column, ok := columnAliases[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("未知的列: %s（可用: word, meaning, phrase, section, tags, -）", name)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// DetectFormat 确定文件格式：未指定时按扩展名判断，.tsv、.tab、.txt为制表符分隔，.html、.md为练习纸，其余为逗号分隔
+func DetectFormat(format model.ExchangeFormat, path string) (model.ExchangeFormat, error) {
+	switch format {
+	case model.FormatCSV, model.FormatTSV, model.FormatAnki, model.FormatText, model.FormatHTML, model.FormatMD:
+		return format, nil
+	case "markdown":
+		return model.FormatMD, nil
+	case "":
+	default:
+		return "", fmt.Errorf("不支持的格式: %s（可用: csv, tsv, anki, text, html, md）", format)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv", ".tab", ".txt":
+		return model.FormatTSV, nil
+	case ".html", ".htm":
+		return model.FormatHTML, nil
+	case ".md", ".markdown":
+		return model.FormatMD, nil
+	default:
+		return model.FormatCSV, nil
+	}
+}
+
+// separator 格式对应的分隔符
+func separator(format model.ExchangeFormat) rune {
+	if format == model.FormatTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// readTable 读取CSV/TSV表格，去掉开头的UTF-8 BOM，跳过空行
+func readTable(r io.Reader, format model.ExchangeFormat) ([]tableRow, error) {
+	reader := bufio.NewReader(r)
+	if head, err := reader.Peek(len(utf8BOM)); err == nil && string(head) == utf8BOM {
+		reader.Discard(len(utf8BOM))
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = separator(format)
+	csvReader.FieldsPerRecord = -1 // 允许各行列数不同
+	csvReader.LazyQuotes = true
+
+	var rows []tableRow
+	for {
+		cells, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析表格失败: %w", err)
+		}
+		line, _ := csvReader.FieldPos(0)
+		if blankRow(cells) {
+			continue
+		}
+		rows = append(rows, tableRow{line: line, cells: cells})
+	}
+	return rows, nil
+}
+
+// blankRow 判断一行是否全部为空
+func blankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// headerColumns 第一行全部由列名组成且包含单词列时，返回对应的列映射
+func headerColumns(cells []string) ([]model.ColumnField, bool) {
+	columns := make([]model.ColumnField, len(cells))
+	hasWord := false
+	for i, cell := range cells {
+		name := strings.ToLower(strings.TrimSpace(cell))
+		if name == "" {
+			columns[i] = model.ColumnIgnore
+			continue
+		}
+		column, ok := columnAliases[name]
+		if !ok {
+			return nil, false
+		}
+		columns[i] = column
+		hasWord = hasWord || column == model.ColumnWord
+	}
+	return columns, hasWord
+}
+
+// tableWords 按列映射把表格转换为要导入的单词
+// columns为空时使用表头，没有表头时使用默认的列顺序；section为章节列为空时使用的章节
+func tableWords(rows []tableRow, columns []model.ColumnField, header bool, section string) ([]model.ImportedWord, []model.ImportIssue, error) {
+	if len(rows) > 0 {
+		detected, isHeader := headerColumns(rows[0].cells)
+		if header && !isHeader && len(columns) == 0 {
+			return nil, nil, fmt.Errorf("无法识别表头，请使用 --columns 指定各列的含义")
+		}
+		if header || isHeader {
+			if len(columns) == 0 {
+				columns = detected
+			}
+			rows = rows[1:]
+		}
+	}
+	if len(columns) == 0 {
+		columns = defaultImportColumns
+	}
+	words, issues := rowWords(rows, columns, section)
+	return words, issues, nil
+}
+
+// rowWords 按列映射把每一行转换为要导入的单词，缺少单词或释义的行作为无法导入的行返回
+func rowWords(rows []tableRow, columns []model.ColumnField, section string) ([]model.ImportedWord, []model.ImportIssue) {
+	var words []model.ImportedWord
+	var issues []model.ImportIssue
+	for _, row := range rows {
+		item := model.ImportedWord{Line: row.line, Section: section}
+		for i, cell := range row.cells {
+			if i >= len(columns) {
+				break
+			}
+			cell = strings.TrimSpace(cell)
+			switch columns[i] {
+			case model.ColumnWord:
+				item.Word.W = cell
+			case model.ColumnMeaning:
+				item.Word.C = cell
+			case model.ColumnPhrase:
+				item.Word.Phrase = cell
+			case model.ColumnSection:
+				if cell != "" {
+					item.Section = cell
+				}
+			case model.ColumnTags:
+				item.Word.Tags = splitTags(cell)
+			}
+		}
+
+		switch {
+		case item.Word.W == "":
+			issues = append(issues, model.ImportIssue{Line: row.line, Reason: "缺少单词"})
+		case item.Word.C == "":
+			issues = append(issues, model.ImportIssue{Line: row.line, Reason: fmt.Sprintf("单词 %s 缺少释义", item.Word.W)})
+		default:
+			words = append(words, item)
+		}
+	}
+	return words, issues
+}
+
+// splitTags 拆分以分号或逗号分隔的标签
+func splitTags(cell string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(cell, func(r rune) bool {
+		return r == ';' || r == ',' || r == '；' || r == '，'
+	}) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// writeTable 按列映射把章节中的单词写为CSV/TSV表格
+func writeTable(w io.Writer, format model.ExchangeFormat, columns []model.ColumnField, sections []model.SectionEntity, header, bom bool) error {
+	var buf bytes.Buffer
+	if bom {
+		buf.WriteString(utf8BOM)
+	}
+	writer := csv.NewWriter(&buf)
+	writer.Comma = separator(format)
+
+	if header {
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = string(column)
+		}
+		writer.Write(names)
+	}
+	for _, section := range sections {
+		for _, word := range section.Words {
+			cells := make([]string, len(columns))
+			for i, column := range columns {
+				switch column {
+				case model.ColumnWord:
+					cells[i] = word.W
+				case model.ColumnMeaning:
+					cells[i] = word.C
+				case model.ColumnPhrase:
+					cells[i] = word.Phrase
+				case model.ColumnSection:
+					cells[i] = section.Name
+				case model.ColumnTags:
+					cells[i] = strings.Join(word.Tags, ";")
+				}
+			}
+			writer.Write(cells)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("生成表格失败: %w", err)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,189 @@
+package sections
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+// wordListDelimiters 单词列表中可以识别的分隔符，使用的行数相同时靠前的优先
+var wordListDelimiters = []string{"\t", " - ", " – ", " — ", " -- ", "——", " | ", "|", "：", ":", " = ", "="}
+
+// partsOfSpeech 单词后面常见的词性标注，解析时作为单词的词性
+var partsOfSpeech = map[string]bool{
+	"n.": true, "v.": true, "vt.": true, "vi.": true, "adj.": true, "a.": true, "adv.": true, "ad.": true,
+	"prep.": true, "conj.": true, "pron.": true, "int.": true, "interj.": true, "art.": true, "num.": true,
+	"aux.": true, "abbr.": true, "phr.": true,
+}
+
+// listMarker 行首的列表序号或项目符号，如 "1. "、"2) "、"- "
+var listMarker = regexp.MustCompile(`^(?:\d+[.)、．]\s*|[-*•·]\s+)`)
+
+// ParseWordList 解析自由格式的单词列表，每行一个单词，空行和以#开始的行会被跳过
+// 大多数行使用同一种分隔符（如 "palatable - 可口的 - 例句"、"dam\t水坝"）时按分隔符拆分，依次为单词、释义和例句；
+// 否则按中英文分界拆分（如 "dam 水坝"），释义之后以英文开始的部分作为例句
+func ParseWordList(text string) *model.ParsedWordList {
+	var parsed []model.ParsedWord
+	var lines []string
+	for i, raw := range strings.Split(strings.TrimPrefix(text, utf8BOM), "\n") {
+		line := strings.TrimSpace(listMarker.ReplaceAllString(strings.TrimSpace(raw), ""))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parsed = append(parsed, model.ParsedWord{Line: i + 1, Text: line})
+		lines = append(lines, line)
+	}
+
+	delimiter := detectDelimiter(lines)
+	list := &model.ParsedWordList{Layout: "按中英文分界", Words: parsed}
+	if delimiter != "" {
+		list.Layout = fmt.Sprintf("以 %q 分隔", delimiter)
+	}
+	for i := range list.Words {
+		list.Words[i].Word = parseWordLine(list.Words[i].Text, delimiter)
+		CheckParsedWord(&list.Words[i])
+	}
+	return list
+}
+
+// ReadWordList 读取并解析单词列表文件
+func ReadWordList(path string) (*model.ParsedWordList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取单词列表失败: %w", err)
+	}
+	return ParseWordList(string(data)), nil
+}
+
+// CheckParsedWord 检查解析或修改后的单词，把无法导入的原因记录在Issue中
+func CheckParsedWord(parsed *model.ParsedWord) {
+	word := &parsed.Word
+	word.W = strings.TrimSpace(word.W)
+	word.C = strings.TrimSpace(word.C)
+	word.Phrase = strings.TrimSpace(word.Phrase)
+
+	switch {
+	case word.W == "":
+		parsed.Issue = "缺少单词"
+	case hasHan(word.W):
+		parsed.Issue = "单词中包含中文，请检查分隔是否正确"
+	case word.C == "":
+		parsed.Issue = "缺少释义"
+	default:
+		parsed.Issue = ""
+	}
+}
+
+// detectDelimiter 找出大多数行使用的分隔符，不到一半的行使用同一种分隔符时返回空字符串
+func detectDelimiter(lines []string) string {
+	best, bestCount := "", 0
+	for _, delimiter := range wordListDelimiters {
+		count := 0
+		for _, line := range lines {
+			parts := strings.SplitN(line, delimiter, 3)
+			if len(parts) >= 2 && wordPair(parts[0], parts[1]) {
+				count++
+			}
+		}
+		if count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	if bestCount == 0 || bestCount*2 < len(lines) {
+		return ""
+	}
+	return best
+}
+
+// wordPair 判断两段文本是否像单词和释义：一段为英文，另一段包含中文
+func wordPair(first, second string) bool {
+	return (isEnglish(first) && hasHan(second)) || (hasHan(first) && isEnglish(second))
+}
+
+// parseWordLine 把一行拆分为单词、释义和例句，不包含分隔符的行按中英文分界拆分
+func parseWordLine(line, delimiter string) model.WordEntity {
+	if delimiter == "" || !strings.Contains(line, delimiter) {
+		return splitByScript(line)
+	}
+
+	parts := strings.SplitN(line, delimiter, 3)
+	word := model.WordEntity{W: strings.TrimSpace(parts[0]), C: strings.TrimSpace(parts[1])}
+	if len(parts) == 3 {
+		word.Phrase = strings.TrimSpace(parts[2])
+	}
+	if hasHan(word.W) && !hasHan(word.C) {
+		// 中文在前，如 "水坝 - dam"
+		word.W, word.C = word.C, word.W
+	}
+	return splitPartOfSpeech(word)
+}
+
+// splitByScript 按中英文分界拆分一行：第一个中文字符之前为单词，到最后一个中文字符为释义，之后的英文为例句
+func splitByScript(line string) model.WordEntity {
+	start := strings.IndexFunc(line, isHan)
+	if start < 0 {
+		return model.WordEntity{W: line}
+	}
+	word := model.WordEntity{W: strings.TrimRight(line[:start], " \t:：-–—=|,，")}
+
+	rest := line[start:]
+	end := 0
+	for i, r := range rest {
+		if isHan(r) || isCJKPunct(r) {
+			end = i + len(string(r))
+		}
+	}
+	word.C = strings.TrimSpace(rest[:end])
+	if phrase := strings.TrimSpace(rest[end:]); strings.IndexFunc(phrase, isLatin) >= 0 {
+		word.Phrase = strings.TrimLeft(phrase, "-–—|:=,. ")
+	} else {
+		word.C = strings.TrimSpace(rest)
+	}
+	if word.W == "" && word.Phrase != "" {
+		// 中文在前，如 "水坝 dam"
+		word.W, word.Phrase = word.Phrase, ""
+	}
+	return splitPartOfSpeech(word)
+}
+
+// splitPartOfSpeech 把单词后面的词性标注作为单词的词性，释义保持不变，如 "palatable adj." -> 单词 "palatable"、词性 "adj."
+func splitPartOfSpeech(word model.WordEntity) model.WordEntity {
+	fields := strings.Fields(word.W)
+	if len(fields) < 2 || !partsOfSpeech[strings.ToLower(fields[len(fields)-1])] {
+		return word
+	}
+	word.W = strings.Join(fields[:len(fields)-1], " ")
+	if word.POS == "" {
+		word.POS = fields[len(fields)-1]
+	}
+	return word
+}
+
+// isEnglish 判断文本是否包含英文字母且不包含中文
+func isEnglish(text string) bool {
+	return strings.IndexFunc(text, isLatin) >= 0 && !hasHan(text)
+}
+
+// hasHan 判断文本是否包含中文
+func hasHan(text string) bool {
+	return strings.IndexFunc(text, isHan) >= 0
+}
+
+// isHan 判断是否为汉字
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+// isLatin 判断是否为拉丁字母
+func isLatin(r rune) bool {
+	return unicode.Is(unicode.Latin, r)
+}
+
+// isCJKPunct 判断是否为中文标点或全角字符，如 "；"、"（"、"）"
+func isCJKPunct(r rune) bool {
+	return (r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
@@ -0,0 +1,288 @@
+package sections
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/ct-zh/englishLearn/model"
+)
+
+const (
+	// worksheetPageSize 读取章节单词时每次查询的数量
+	worksheetPageSize = 200
+	// cardColumns、cardRows 每页单词卡片的列数和行数，A4纸每页12张
+	cardColumns = 3
+	cardRows    = 4
+)
+
+// worksheet 导出为练习纸或单词卡片的内容
+type worksheet struct {
+	Title        string
+	Sections     []worksheetSection
+	HideMeanings bool
+	Cards        bool
+}
+
+// worksheetSection 一个章节的单词，以及排成单词卡片后的各页
+type worksheetSection struct {
+	Name   string
+	Words  []model.WordEntity
+	Sheets []cardSheet
+}
+
+// cardSheet 一张双面打印的单词卡片：正面和背面各一页，每页按行排列
+// 背面每行的顺序与正面相反，沿长边翻转双面打印后正反面对齐
+type cardSheet struct {
+	Front [][]*model.WordEntity
+	Back  [][]*model.WordEntity
+}
+
+// exportWorksheet 将章节导出为可打印的HTML或Markdown练习纸、单词卡片
+func (s *Service) exportWorksheet(format model.ExchangeFormat, req *model.ExportWordsRequest) (*model.ExportWordsResponse, error) {
+	switch req.Layout {
+	case "", model.LayoutWorksheet, model.LayoutCards:
+	default:
+		return nil, fmt.Errorf("不支持的排版: %s（可用: worksheet, cards）", req.Layout)
+	}
+	names, err := s.exportSectionNames(req.Sections)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &worksheet{HideMeanings: req.HideMeanings, Cards: req.Layout == model.LayoutCards}
+	resp := &model.ExportWordsResponse{Sections: len(names)}
+	for _, name := range names {
+		words, err := s.SectionWords(name)
+		if err != nil {
+			return nil, err
+		}
+		section := worksheetSection{Name: name, Words: words}
+		if sheet.Cards {
+			section.Sheets = cardSheets(words)
+		}
+		sheet.Sections = append(sheet.Sections, section)
+		resp.Words += len(words)
+	}
+
+	switch {
+	case len(names) == 1:
+		sheet.Title = names[0]
+	case sheet.Cards:
+		sheet.Title = "单词卡片"
+	default:
+		sheet.Title = "单词练习"
+	}
+
+	var buf bytes.Buffer
+	if format == model.FormatHTML {
+		if err := worksheetTemplate.Execute(&buf, sheet); err != nil {
+			return nil, fmt.Errorf("生成练习纸失败: %w", err)
+		}
+	} else {
+		buf.WriteString(markdownWorksheet(sheet))
+	}
+	err = writeExport(req.Path, func(w io.Writer) error {
+		_, err := w.Write(buf.Bytes())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// exportSectionNames 获取要导出的章节名称，未指定时为全部章节（不含错题本等自动维护的章节）
+func (s *Service) exportSectionNames(names []string) ([]string, error) {
+	if len(names) > 0 {
+		return names, nil
+	}
+	sections, err := s.exportSections(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range sections {
+		names = append(names, section.Name)
+	}
+	return names, nil
+}
+
+// cardSheets 把单词排成单词卡片，最后一页不足时用空卡片补齐，保证背面的位置与正面对应
+func cardSheets(words []model.WordEntity) []cardSheet {
+	var sheets []cardSheet
+	perSheet := cardColumns * cardRows
+	for start := 0; start < len(words); start += perSheet {
+		var sheet cardSheet
+		for row := 0; row < cardRows && start+row*cardColumns < len(words); row++ {
+			front := make([]*model.WordEntity, cardColumns)
+			back := make([]*model.WordEntity, cardColumns)
+			for col := 0; col < cardColumns; col++ {
+				if i := start + row*cardColumns + col; i < len(words) {
+					front[col] = &words[i]
+					back[cardColumns-1-col] = &words[i]
+				}
+			}
+			sheet.Front = append(sheet.Front, front)
+			sheet.Back = append(sheet.Back, back)
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets
+}
+
+// markdownWorksheet 生成Markdown格式的练习纸或单词卡片，分页处使用HTML的分页符
+func markdownWorksheet(sheet *worksheet) string {
+	const pageBreak = "\n<div style=\"page-break-after: always\"></div>\n\n"
+	var b strings.Builder
+	for i, section := range sheet.Sections {
+		if i > 0 {
+			b.WriteString(pageBreak)
+		}
+		if sheet.Cards {
+			for j, card := range section.Sheets {
+				if j > 0 {
+					b.WriteString(pageBreak)
+				}
+				fmt.Fprintf(&b, "# %s 单词卡片 %d（正面）\n\n", section.Name, j+1)
+				markdownCards(&b, card.Front, func(word *model.WordEntity) string {
+					return "**" + markdownCell(word.W) + "**"
+				})
+				b.WriteString(pageBreak)
+				fmt.Fprintf(&b, "# %s 单词卡片 %d（背面）\n\n", section.Name, j+1)
+				markdownCards(&b, card.Back, func(word *model.WordEntity) string {
+					if word.Phrase == "" {
+						return markdownCell(word.C)
+					}
+					return markdownCell(word.C) + "<br>*" + markdownCell(word.Phrase) + "*"
+				})
+			}
+			continue
+		}
+
+		fmt.Fprintf(&b, "# %s\n\n", section.Name)
+		b.WriteString("姓名：__________　日期：__________　得分：__________\n\n")
+		b.WriteString("| # | 单词 | 释义 | 例句 |\n|---:|---|---|---|\n")
+		for j, word := range section.Words {
+			meaning := markdownCell(word.C)
+			if sheet.HideMeanings {
+				meaning = " "
+			}
+			fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", j+1, markdownCell(word.W), meaning, markdownCell(word.Phrase))
+		}
+		if sheet.HideMeanings {
+			b.WriteString(pageBreak)
+			fmt.Fprintf(&b, "# %s 答案\n\n", section.Name)
+			b.WriteString("| # | 单词 | 释义 |\n|---:|---|---|\n")
+			for j, word := range section.Words {
+				fmt.Fprintf(&b, "| %d | %s | %s |\n", j+1, markdownCell(word.W), markdownCell(word.C))
+			}
+		}
+	}
+	return b.String()
+}
+
+// markdownCards 把一页单词卡片写为Markdown表格
+func markdownCards(b *strings.Builder, rows [][]*model.WordEntity, cell func(word *model.WordEntity) string) {
+	b.WriteString("|" + strings.Repeat("   |", cardColumns) + "\n|" + strings.Repeat("---|", cardColumns) + "\n")
+	for _, row := range rows {
+		b.WriteString("|")
+		for _, word := range row {
+			if word != nil {
+				b.WriteString(" " + cell(word))
+			}
+			b.WriteString(" |")
+		}
+		b.WriteString("\n")
+	}
+}
+
+// markdownCell 转义Markdown表格单元格中的竖线、尖括号和换行
+func markdownCell(text string) string {
+	text = strings.NewReplacer("|", "\\|", "<", "&lt;", ">", "&gt;").Replace(text)
+	return strings.ReplaceAll(strings.TrimSpace(text), "\n", "<br>")
+}
+
+// worksheetTemplate HTML练习纸和单词卡片的模板，打印样式按A4纸排版
+// 单词卡片的每张卡片大小固定、以虚线为裁剪线，正面和背面分别占一页，双面打印时选择沿长边翻转
+var worksheetTemplate = template.Must(template.New("worksheet").Funcs(template.FuncMap{
+	"inc":   func(i int) int { return i + 1 },
+	"lines": func(text string) []string { return strings.Split(strings.TrimSpace(text), "\n") },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { size: A4; margin: 12mm; }
+body { font-family: "Helvetica Neue", Arial, "PingFang SC", "Microsoft YaHei", sans-serif; font-size: 11pt; color: #000; margin: 0; }
+h1 { font-size: 16pt; margin: 0 0 4mm; }
+.info { margin-bottom: 5mm; }
+.info span { display: inline-block; margin-right: 12mm; }
+table.words { width: 100%; border-collapse: collapse; }
+table.words th, table.words td { border: 1px solid #999; padding: 2mm 3mm; text-align: left; vertical-align: top; }
+table.words tr { break-inside: avoid; page-break-inside: avoid; }
+table.words .no { width: 8mm; text-align: right; }
+table.words .blank { width: 40%; }
+.phrase { font-size: 9pt; color: #444; }
+.page { break-after: page; page-break-after: always; }
+.page:last-child { break-after: auto; page-break-after: auto; }
+.cards { display: grid; grid-template-columns: repeat(3, 62mm); grid-auto-rows: 68mm; }
+.card { border: 1px dashed #999; box-sizing: border-box; padding: 5mm; overflow: hidden;
+  display: flex; flex-direction: column; justify-content: center; align-items: center; text-align: center; }
+.card .word { font-size: 20pt; font-weight: bold; overflow-wrap: anywhere; }
+.card .meaning { font-size: 14pt; }
+.card .phrase { margin-top: 3mm; font-style: italic; }
+@media screen {
+  body { max-width: 210mm; margin: 0 auto; padding: 10mm; }
+  .page { border-bottom: 2px solid #ccc; padding-bottom: 10mm; margin-bottom: 10mm; }
+}
+</style>
+</head>
+<body>
+{{- $hide := .HideMeanings}}
+{{- if .Cards}}
+{{- range .Sections}}
+{{- range .Sheets}}
+<div class="page cards">
+{{- range .Front}}{{range .}}
+<div class="card">{{if .}}<div class="word">{{.W}}</div>{{end}}</div>
+{{- end}}{{end}}
+</div>
+<div class="page cards">
+{{- range .Back}}{{range .}}
+<div class="card">{{if .}}<div class="meaning">{{range $j, $line := lines .C}}{{if $j}}<br>{{end}}{{$line}}{{end}}</div>{{if .Phrase}}<div class="phrase">{{.Phrase}}</div>{{end}}{{end}}</div>
+{{- end}}{{end}}
+</div>
+{{- end}}
+{{- end}}
+{{- else}}
+{{- range .Sections}}
+<div class="page">
+<h1>{{.Name}}</h1>
+<div class="info"><span>姓名：__________</span><span>日期：__________</span><span>得分：__________</span></div>
+<table class="words">
+<tr><th class="no">#</th><th>单词</th><th>释义</th></tr>
+{{- range $i, $word := .Words}}
+<tr><td class="no">{{inc $i}}</td><td>{{$word.W}}{{if $word.Phrase}}<div class="phrase">{{$word.Phrase}}</div>{{end}}</td>
+{{- if $hide}}<td class="blank"></td>{{else}}<td>{{range $j, $line := lines $word.C}}{{if $j}}<br>{{end}}{{$line}}{{end}}</td>{{end}}</tr>
+{{- end}}
+</table>
+</div>
+{{- if $hide}}
+<div class="page">
+<h1>{{.Name}} 答案</h1>
+<table class="words">
+<tr><th class="no">#</th><th>单词</th><th>释义</th></tr>
+{{- range $i, $word := .Words}}
+<tr><td class="no">{{inc $i}}</td><td>{{$word.W}}</td><td>{{range $j, $line := lines $word.C}}{{if $j}}<br>{{end}}{{$line}}{{end}}</td></tr>
+{{- end}}
+</table>
+</div>
+{{- end}}
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,119 @@
+package model
+
+// ===== 导入导出 =====
+
+// ExchangeFormat 导入导出的文件格式
+type ExchangeFormat string
+
+const (
+	FormatCSV  ExchangeFormat = "csv"  // 逗号分隔
+	FormatTSV  ExchangeFormat = "tsv"  // 制表符分隔
+	FormatAnki ExchangeFormat = "anki" // Anki笔记文本，以 #separator、#html 等文件头开始，每个章节对应一个牌组
+	FormatText ExchangeFormat = "text" // 自由格式的单词列表，如 "dam 水坝"、"palatable - 可口的 - 例句"，只能导入
+	FormatHTML ExchangeFormat = "html" // 可打印的HTML练习纸或单词卡片，只能导出
+	FormatMD   ExchangeFormat = "md"   // Markdown练习纸或单词卡片，只能导出
+)
+
+// WorksheetLayout 导出为HTML/Markdown时的排版
+type WorksheetLayout string
+
+const (
+	LayoutWorksheet WorksheetLayout = "worksheet" // 练习纸：单词列表，可以隐藏释义并在另一页附上答案
+	LayoutCards     WorksheetLayout = "cards"     // 单词卡片：正面为单词、背面为释义，双面打印后裁剪
+)
+
+// ColumnField 表格中一列对应的单词字段
+type ColumnField string
+
+const (
+	ColumnWord    ColumnField = "word"    // 单词
+	ColumnMeaning ColumnField = "meaning" // 中文释义
+	ColumnPhrase  ColumnField = "phrase"  // 例句
+	ColumnSection ColumnField = "section" // 所在章节
+	ColumnTags    ColumnField = "tags"    // 标签，以分号或逗号分隔
+	ColumnIgnore  ColumnField = "-"       // 忽略该列
+)
+
+// ===== CLI层请求/响应结构体 =====
+
+// ImportWordsRequest 从文件导入单词请求
+type ImportWordsRequest struct {
+	Path    string         `json:"path"`
+	Format  ExchangeFormat `json:"format,omitempty"`  // 为空时按文件头和扩展名判断
+	Columns []ColumnField  `json:"columns,omitempty"` // 各列对应的字段，为空时使用表头，没有表头时为 word,meaning,phrase,section,tags
+	Header  bool           `json:"header,omitempty"`  // 第一行是否为表头；为false时由列名组成的第一行也会被识别为表头
+	Section string         `json:"section,omitempty"` // 没有章节列或章节列为空时导入到的章节
+	DryRun  bool           `json:"dry_run,omitempty"` // 只预览，不写入
+}
+
+// ImportedWord 导入文件中的一个单词
+type ImportedWord struct {
+	Line    int        `json:"line"` // 所在行号，从1开始
+	Section string     `json:"section"`
+	Word    WordEntity `json:"word"`
+}
+
+// ImportConflict 与已有单词拼写相同但释义或例句不同的单词，导入时跳过
+type ImportConflict struct {
+	ImportedWord
+	Existing WordEntity `json:"existing"`
+}
+
+// ImportIssue 无法导入的行
+type ImportIssue struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportWordsResponse 导入单词响应，预览时New为将要添加的单词
+type ImportWordsResponse struct {
+	Total       int              `json:"total"`        // 读取到的单词行数（不含表头和空行）
+	New         []ImportedWord   `json:"new"`          // 新单词
+	Duplicates  []ImportedWord   `json:"duplicates"`   // 已存在且内容相同，跳过
+	Conflicts   []ImportConflict `json:"conflicts"`    // 已存在但释义或例句不同，跳过
+	Invalid     []ImportIssue    `json:"invalid"`      // 无法导入的行
+	NewSections []string         `json:"new_sections"` // 需要新建的章节
+	Added       int              `json:"added"`        // 实际添加的单词数，预览时为0
+}
+
+// ParsedWord 从单词列表文本中解析出的一行
+type ParsedWord struct {
+	Line  int        `json:"line"` // 所在行号，从1开始
+	Text  string     `json:"text"` // 原始文本
+	Word  WordEntity `json:"word"`
+	Issue string     `json:"issue,omitempty"` // 无法导入的原因，修改后重新检查
+}
+
+// ParsedWordList 解析后的单词列表，导入前可以逐行修改
+type ParsedWordList struct {
+	Layout string       `json:"layout"` // 识别出的格式，如 以 " - " 分隔
+	Words  []ParsedWord `json:"words"`
+}
+
+// ImportParsedWordsRequest 导入解析后的单词列表请求
+type ImportParsedWordsRequest struct {
+	Section string       `json:"section"` // 导入到的章节，不存在时新建
+	Source  string       `json:"source"`  // 修改历史中显示的来源
+	Words   []ParsedWord `json:"words"`
+	DryRun  bool         `json:"dry_run,omitempty"`
+}
+
+// ExportWordsRequest 导出单词到文件请求
+type ExportWordsRequest struct {
+	Path     string         `json:"path,omitempty"`     // 为空时输出到标准输出
+	Format   ExchangeFormat `json:"format,omitempty"`   // 为空时按扩展名判断，默认为csv
+	Sections []string       `json:"sections,omitempty"` // 为空时导出全部章节（不含错题本）
+	Columns  []ColumnField  `json:"columns,omitempty"`  // 为空时为 word,meaning,phrase，导出多个章节时加上section；Anki格式不使用
+	NoHeader bool           `json:"no_header,omitempty"`
+	BOM      bool           `json:"bom,omitempty"` // 是否写入UTF-8 BOM，Excel打开含中文的CSV时需要
+
+	// 以下只用于HTML/Markdown格式
+	Layout       WorksheetLayout `json:"layout,omitempty"`        // 为空时为练习纸
+	HideMeanings bool            `json:"hide_meanings,omitempty"` // 练习纸中不显示释义，答案放在单独的一页
+}
+
+// ExportWordsResponse 导出单词响应
+type ExportWordsResponse struct {
+	Sections int `json:"sections"`
+	Words    int `json:"words"`
+}
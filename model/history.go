@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// ===== 修改历史 =====
+
+// HistoryEntry 一次修改的记录，保存了这次修改改变的章节和单词，可以单独撤销
+type HistoryEntry struct {
+	ID     string    `json:"id"`     // 记录编号，按时间递增
+	Time   time.Time `json:"time"`   // 修改时间
+	Action string    `json:"action"` // 修改的描述，如“删除章节 day1（20 个单词）”
+}
+
+// ===== CLI层请求/响应结构体 =====
+
+// ListHistoryRequest 查看修改历史请求
+type ListHistoryRequest struct {
+	Limit int `json:"limit"` // 最多列出多少条，0表示全部
+}
+
+// ListHistoryResponse 查看修改历史响应，最近的修改在前
+type ListHistoryResponse struct {
+	Entries []HistoryEntry `json:"entries"`
+	Total   int            `json:"total"` // 保存的修改记录总数
+}
+
+// UndoRequest 撤销修改请求
+type UndoRequest struct {
+	Index int  `json:"index"` // 撤销第几条修改（1为最近一次），0表示1
+	All   bool `json:"all"`   // 是否同时撤销之后的全部修改，恢复到这次修改之前的状态
+}
+
+// UndoResponse 撤销修改响应
+type UndoResponse struct {
+	Entry    HistoryEntry `json:"entry"`    // 撤销的修改，All为true时恢复到这次修改之前的状态
+	Reverted int          `json:"reverted"` // 一共撤销了多少次修改
+}
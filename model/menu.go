@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"sort"
 )
 
 // MenuNode 菜单节点接口
@@ -9,11 +10,13 @@ type MenuNode interface {
 	GetID() string
 	GetName() string
 	GetCommand() string
+	GetOrder() int // 在上级菜单中的显示顺序
 	GetChildren() map[string]MenuNode
 	Menu(child MenuNode) MenuNode // 挂载子节点
 	Execute(ctx *MenuContext) error
 	Display() string
 	IsLeaf() bool
+	IsHidden() bool // 是否只能通过命令行调用
 }
 
 // MenuContext 菜单执行上下文
@@ -29,8 +32,10 @@ type BaseMenuNode struct {
 	ID       string
 	Name     string
 	Command  string
+	Order    int // 显示顺序，数字小的在前，相同时按命令排序
 	Children map[string]MenuNode
 	Handler  func(ctx *MenuContext) error
+	Hidden   bool // 为true时只能通过命令行调用，不在交互菜单中显示
 }
 
 // GetID 获取节点ID
@@ -48,6 +53,11 @@ func (b *BaseMenuNode) GetCommand() string {
 	return b.Command
 }
 
+// GetOrder 获取节点的显示顺序
+func (b *BaseMenuNode) GetOrder() int {
+	return b.Order
+}
+
 // GetChildren 获取子节点
 func (b *BaseMenuNode) GetChildren() map[string]MenuNode {
 	if b.Children == nil {
@@ -83,6 +93,26 @@ func (b *BaseMenuNode) IsLeaf() bool {
 	return len(b.Children) == 0
 }
 
+// IsHidden 判断节点是否只能通过命令行调用
+func (b *BaseMenuNode) IsHidden() bool {
+	return b.Hidden
+}
+
+// SortedChildren 按显示顺序返回子节点，保证每次显示的菜单顺序相同
+func SortedChildren(node MenuNode) []MenuNode {
+	children := make([]MenuNode, 0, len(node.GetChildren()))
+	for _, child := range node.GetChildren() {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].GetOrder() != children[j].GetOrder() {
+			return children[i].GetOrder() < children[j].GetOrder()
+		}
+		return children[i].GetCommand() < children[j].GetCommand()
+	})
+	return children
+}
+
 // 错误定义
 var (
 	ErrExit = fmt.Errorf("退出程序")
@@ -0,0 +1,71 @@
+package model
+
+// ===== DAO层查询结构体 =====
+
+// WordSortField 单词查询的排序字段
+type WordSortField string
+
+const (
+	SortByWord      WordSortField = "word"       // 按单词（不区分大小写）
+	SortBySection   WordSortField = "section"    // 按章节名称
+	SortByCreatedAt WordSortField = "created_at" // 按创建时间
+	SortByUpdatedAt WordSortField = "updated_at" // 按修改时间
+	SortByErrorRate WordSortField = "error_rate" // 按复习错误率
+)
+
+// WordSortKey 单词查询的排序键
+type WordSortKey struct {
+	Field WordSortField `json:"field"`
+	Desc  bool          `json:"desc,omitempty"` // 是否降序
+}
+
+// WordQuery 单词查询条件
+// 各条件之间为“且”的关系，为空的条件不生效；文本条件均为不区分大小写的包含匹配
+type WordQuery struct {
+	Sections []string `json:"sections,omitempty"` // 限定章节，为空表示全部章节
+	Word     string   `json:"word,omitempty"`     // 单词包含该文本
+	Meaning  string   `json:"meaning,omitempty"`  // 主释义或任一义项的释义包含该文本
+	Phrase   string   `json:"phrase,omitempty"`   // 主例句或任一义项的例句包含该文本
+	Tag      string   `json:"tag,omitempty"`      // 带有该标签（不区分大小写，完全匹配）
+	Keyword  string   `json:"keyword,omitempty"`  // 单词或主释义包含该文本，用于搜索
+
+	Sort   []WordSortKey `json:"sort,omitempty"`   // 排序键，依次比较；为空时按章节顺序和章节内的顺序
+	Offset int           `json:"offset,omitempty"` // 跳过的结果数
+	Limit  int           `json:"limit,omitempty"`  // 最多返回的结果数，0表示不限制
+}
+
+// WordMatch 查询到的单词及其所在章节
+type WordMatch struct {
+	Section string     `json:"section"`
+	Word    WordEntity `json:"word"`
+}
+
+// WordQueryResult 单词查询结果
+type WordQueryResult struct {
+	Words []WordMatch `json:"words"`
+	Total int         `json:"total"` // 分页前满足条件的单词总数
+}
+
+// SectionSortMode 章节的排序方式
+type SectionSortMode string
+
+const (
+	SectionSortStored  SectionSortMode = ""        // 按保存的顺序，即创建章节的先后（默认）
+	SectionSortCreated SectionSortMode = "created" // 按创建时间
+	SectionSortName    SectionSortMode = "name"    // 按名称，不区分大小写，名称中的数字按数值比较（day2在day10之前）
+	SectionSortDate    SectionSortMode = "date"    // 按名称中的日期，如 "day3 2025 Feb. 22"、"2025-03-25"；没有日期的章节排在最后
+)
+
+// SectionQuery 章节查询条件
+type SectionQuery struct {
+	Archived bool            `json:"archived,omitempty"` // true时只返回已归档的章节，否则只返回未归档的章节
+	Sort     SectionSortMode `json:"sort,omitempty"`     // 排序方式，排序键相同时保持保存的顺序
+	Offset   int             `json:"offset,omitempty"`   // 跳过的章节数
+	Limit    int             `json:"limit,omitempty"`    // 最多返回的章节数，0表示不限制
+}
+
+// SectionQueryResult 章节查询结果
+type SectionQueryResult struct {
+	Sections []SectionEntity `json:"sections"`
+	Total    int             `json:"total"` // 分页前满足条件的章节总数
+}
@@ -0,0 +1,144 @@
+package model
+
+import "time"
+
+// ===== 复习状态 =====
+
+// ReviewState 单词复习状态（SM-2算法）
+type ReviewState struct {
+	EaseFactor  float64   `json:"ease_factor"`  // 难度系数，最小1.3
+	Interval    int       `json:"interval"`     // 当前复习间隔（天）
+	Repetitions int       `json:"repetitions"`  // 连续答对次数
+	Due         time.Time `json:"due"`          // 下次复习日期
+	FirstReview time.Time `json:"first_review"` // 首次复习时间
+	LastReview  time.Time `json:"last_review"`  // 最近一次复习时间
+	Reviews     int       `json:"reviews"`      // 累计作答次数
+	Lapses      int       `json:"lapses"`       // 累计遗忘次数（答错）
+}
+
+// IsNew 判断单词是否从未复习过
+func (r *ReviewState) IsNew() bool {
+	return r == nil || r.Reviews == 0
+}
+
+// ErrorRate 计算错误率，未复习过的单词返回0
+func (r *ReviewState) ErrorRate() float64 {
+	if r.IsNew() {
+		return 0
+	}
+	return float64(r.Lapses) / float64(r.Reviews)
+}
+
+// MistakeRecord 错题记录
+type MistakeRecord struct {
+	Source        string    `json:"source"`         // 最初答错时所在的章节
+	WrongCount    int       `json:"wrong_count"`    // 累计答错次数
+	LastWrong     time.Time `json:"last_wrong"`     // 最近一次答错时间
+	CorrectStreak int       `json:"correct_streak"` // 最近一次答错后连续答对的次数
+}
+
+// ===== CLI层请求/响应结构体 =====
+
+// RecordAnswerRequest 记录作答结果请求
+type RecordAnswerRequest struct {
+	Section string `json:"section"`
+	Word    string `json:"word"`
+	Quality int    `json:"quality"` // SM-2评分，0-5
+}
+
+// ReviewQueueRequest 构建复习队列请求
+type ReviewQueueRequest struct {
+	NewLimit int `json:"new_limit"` // 每日新词上限，0表示不学新词
+}
+
+// ReviewQueueItem 复习队列中的一项
+type ReviewQueueItem struct {
+	Section     string     `json:"section"`
+	Word        WordEntity `json:"word"`
+	IsNew       bool       `json:"is_new"`       // 是否为新词
+	OverdueDays int        `json:"overdue_days"` // 逾期天数，当天到期为0
+}
+
+// ReviewQueueResponse 构建复习队列响应
+type ReviewQueueResponse struct {
+	Items    []ReviewQueueItem `json:"items"`
+	DueCount int               `json:"due_count"` // 到期复习的单词数
+	NewCount int               `json:"new_count"` // 今日加入的新词数
+}
+
+// ===== 练习会话 =====
+
+// 练习模式
+const (
+	SessionModeReview    = "review"    // 跨章节的今日复习
+	SessionModeFlashcard = "flashcard" // 章节闪卡自评
+	SessionModeDictation = "dictation" // 看释义拼写单词
+	SessionModeCloze     = "cloze"     // 例句完形填空
+	SessionModeChoice    = "choice"    // 四选一选择题
+)
+
+// SessionEntity 一次练习会话记录
+// 作答记录随练习逐次写入，同一会话的多条记录ID相同，读取时合并为一条；旧记录没有ID
+type SessionEntity struct {
+	ID        string         `json:"id,omitempty"`
+	StartedAt time.Time      `json:"started_at"`
+	EndedAt   time.Time      `json:"ended_at"`
+	Section   string         `json:"section,omitempty"` // 练习的章节，跨章节复习为空
+	Mode      string         `json:"mode"`
+	Answers   []AnswerRecord `json:"answers"`
+}
+
+// AnswerRecord 单次作答记录
+type AnswerRecord struct {
+	Section    string    `json:"section"`
+	Word       string    `json:"word"`
+	Quality    int       `json:"quality"`
+	Correct    bool      `json:"correct"`
+	ResponseMS int64     `json:"response_ms,omitempty"` // 作答用时（毫秒），旧记录没有该字段
+	AnsweredAt time.Time `json:"answered_at"`
+}
+
+// ===== 学习统计 =====
+
+// StatsRequest 学习统计请求
+type StatsRequest struct {
+	Days int `json:"days"` // 按天统计最近多少天
+	Top  int `json:"top"`  // 列出最难的多少个单词
+}
+
+// SectionStats 章节正确率统计
+type SectionStats struct {
+	Section  string  `json:"section"`
+	Answers  int     `json:"answers"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"` // 正确率，0-1
+}
+
+// WordStats 单词作答统计
+type WordStats struct {
+	Section     string  `json:"section"`
+	Word        string  `json:"word"`
+	Answers     int     `json:"answers"`
+	Wrong       int     `json:"wrong"`
+	ErrorRate   float64 `json:"error_rate"`      // 错误率，0-1
+	AvgResponse int64   `json:"avg_response_ms"` // 平均作答用时（毫秒），没有用时记录为0
+}
+
+// DailyStats 每日学习统计
+type DailyStats struct {
+	Date    string `json:"date"`    // 日期，格式为2006-01-02
+	Words   int    `json:"words"`   // 当天练习的不同单词数
+	Answers int    `json:"answers"` // 当天作答次数
+	Correct int    `json:"correct"`
+}
+
+// StatsResponse 学习统计响应
+type StatsResponse struct {
+	Sessions     int            `json:"sessions"` // 累计练习次数
+	Answers      int            `json:"answers"`  // 累计作答次数
+	Correct      int            `json:"correct"`
+	Sections     []SectionStats `json:"sections"`      // 按章节名称排序
+	HardestWords []WordStats    `json:"hardest_words"` // 按错误率从高到低排序
+	Daily        []DailyStats   `json:"daily"`         // 从早到晚排序，包含没有练习的日期
+	Streak       int            `json:"streak"`        // 连续学习天数
+}
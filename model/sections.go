@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 // ===== CLI层请求/响应结构体 =====
 
 // AddWordRequest 添加单词请求
@@ -10,6 +12,28 @@ type AddWordRequest struct {
 	Section     string `json:"section"`
 }
 
+// UpdateWordRequest 编辑单词请求，为空的字段保持不变
+type UpdateWordRequest struct {
+	Section     string `json:"section"`
+	Word        string `json:"word"`                  // 要编辑的单词
+	NewWord     string `json:"new_word,omitempty"`    // 新的拼写
+	Translation string `json:"translation,omitempty"` // 新的中文释义
+	Phrase      string `json:"phrase,omitempty"`      // 新的例句
+}
+
+// DeleteWordRequest 删除单词请求
+type DeleteWordRequest struct {
+	Section string `json:"section"`
+	Word    string `json:"word"`
+}
+
+// TransferWordRequest 移动或复制单词请求
+type TransferWordRequest struct {
+	Section string `json:"section"` // 单词所在的章节
+	Word    string `json:"word"`
+	Target  string `json:"target"` // 目标章节
+}
+
 // ListWordsRequest 列出单词请求
 type ListWordsRequest struct {
 	Section string `json:"section"`
@@ -19,8 +43,10 @@ type ListWordsRequest struct {
 
 // RandomWordsRequest 随机单词请求
 type RandomWordsRequest struct {
-	Section string `json:"section"`
-	Count   int    `json:"count"`
+	Section  string `json:"section"`
+	Count    int    `json:"count"`
+	Seed     *int64 `json:"seed,omitempty"`     // 随机种子，为nil时每次随机，0也是有效的种子
+	Weighted bool   `json:"weighted,omitempty"` // 是否按错误率加权，优先抽取易错单词
 }
 
 // SearchWordRequest 搜索单词请求
@@ -43,6 +69,7 @@ type ListWordsResponse struct {
 type RandomWordsResponse struct {
 	Words []WordEntity `json:"words"`
 	Count int          `json:"count"`
+	Seed  int64        `json:"seed"` // 实际使用的随机种子，可用于重现本次练习
 }
 
 // SearchWordResponse 搜索单词响应
@@ -65,8 +92,10 @@ type UpdateSectionRequest struct {
 
 // ListSectionsRequest 列出章节请求
 type ListSectionsRequest struct {
-	Page int `json:"page"` // 页码，从1开始
-	Size int `json:"size"` // 每页大小
+	Page     int  `json:"page"`               // 页码，从1开始
+	Size     int  `json:"size"`               // 每页大小
+	Archived bool            `json:"archived,omitempty"` // true时列出已归档的章节，否则列出未归档的章节
+	Sort     SectionSortMode `json:"sort,omitempty"`     // 排序方式，为空时按保存的顺序
 }
 
 // ListSectionsResponse 列出章节响应
@@ -79,6 +108,105 @@ type ListSectionsResponse struct {
 	HasPrev     bool            `json:"has_prev"`
 }
 
+// RenameSectionRequest 重命名章节请求
+type RenameSectionRequest struct {
+	Name    string `json:"name"`
+	NewName string `json:"new_name"`
+}
+
+// DeleteSectionRequest 删除章节请求
+type DeleteSectionRequest struct {
+	Name string `json:"name"`
+}
+
+// DuplicatePolicy 合并时遇到同名单词的处理方式
+type DuplicatePolicy string
+
+const (
+	DuplicateSkip      DuplicatePolicy = "skip"      // 保留目标中已有的单词（默认）
+	DuplicateOverwrite DuplicatePolicy = "overwrite" // 用合并进来的单词覆盖
+	DuplicateNewer     DuplicatePolicy = "newer"     // 保留修改时间较新的单词
+)
+
+// MergeSectionsRequest 合并章节请求，来源章节的单词并入目标章节后删除来源章节
+type MergeSectionsRequest struct {
+	Source string          `json:"source"`
+	Target string          `json:"target"`
+	Policy DuplicatePolicy `json:"policy,omitempty"` // 为空时使用DuplicateSkip
+}
+
+// MergeSectionsResponse 合并章节响应
+type MergeSectionsResponse struct {
+	Added       int `json:"added"`       // 新加入目标章节的单词数
+	Overwritten int `json:"overwritten"` // 覆盖了目标章节中同名单词的数量
+	Skipped     int `json:"skipped"`     // 因目标章节已有同名单词而丢弃的数量
+}
+
+// ConflictPolicy 合并数据文件时，同名单词释义或例句不同的处理方式
+type ConflictPolicy string
+
+const (
+	ConflictKeepOurs   ConflictPolicy = "keep-ours"   // 保留当前词库中的内容（默认）
+	ConflictKeepTheirs ConflictPolicy = "keep-theirs" // 使用另一个文件中的内容，复习进度保持不变
+	ConflictCombine    ConflictPolicy = "combine"     // 合并两边的释义和义项
+	ConflictAsk        ConflictPolicy = "ask"         // 逐个询问
+)
+
+// MergeConflict 合并数据文件时释义或例句不同的同名单词
+type MergeConflict struct {
+	Section    string         `json:"section"`
+	Ours       WordEntity     `json:"ours"`
+	Theirs     WordEntity     `json:"theirs"`
+	Resolution ConflictPolicy `json:"resolution,omitempty"` // 实际的处理方式，预览且逐个询问时为空
+	Result     WordEntity     `json:"result"`               // 处理后的单词
+}
+
+// MergeFileRequest 合并数据文件请求，另一个文件中的章节按名称、单词按规范化的拼写与当前词库匹配
+type MergeFileRequest struct {
+	Path   string         `json:"path"`             // JSON数据文件、词库目录或日志存储文件
+	Policy ConflictPolicy `json:"policy,omitempty"` // 为空时使用ConflictKeepOurs
+	DryRun bool           `json:"dry_run,omitempty"`
+
+	// Decide Policy为ConflictAsk时逐个决定冲突的处理方式，返回其他三种处理方式之一
+	Decide func(conflict *MergeConflict) ConflictPolicy `json:"-"`
+}
+
+// MergeFileResponse 合并数据文件的结果
+type MergeFileResponse struct {
+	Source          string          `json:"source"`
+	Sections        int             `json:"sections"`         // 另一个文件中的章节数
+	Words           int             `json:"words"`            // 另一个文件中的单词数
+	NewSections     []string        `json:"new_sections"`     // 新建的章节
+	SkippedSections []string        `json:"skipped_sections"` // 跳过的错题本等自动维护的章节
+	Added           int             `json:"added"`            // 新增的单词数（含新建章节中的单词）
+	Identical       int             `json:"identical"`        // 内容相同或已包含对方全部内容的单词数
+	Conflicts       []MergeConflict `json:"conflicts"`
+	KeptOurs        int             `json:"kept_ours"`
+	TookTheirs      int             `json:"took_theirs"`
+	Combined        int             `json:"combined"`
+}
+
+// SplitSectionRequest 拆分章节请求
+// 指定Words时将这些单词拆分到名为NewName的新章节；否则按Size每若干个单词拆分，
+// 原章节保留前Size个单词，其余依次放入 "名称-2"、"名称-3"……，名称默认为原章节名称，也可由NewName指定
+type SplitSectionRequest struct {
+	Name    string   `json:"name"`
+	Size    int      `json:"size,omitempty"`
+	Words   []string `json:"words,omitempty"`
+	NewName string   `json:"new_name,omitempty"`
+}
+
+// SplitSectionResponse 拆分章节响应
+type SplitSectionResponse struct {
+	Created []string `json:"created"` // 新建的章节名称
+}
+
+// ArchiveSectionRequest 归档或取消归档章节请求
+type ArchiveSectionRequest struct {
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
 // SelectSectionRequest 选择章节请求
 type SelectSectionRequest struct {
 	SectionName string `json:"section_name"`
@@ -95,28 +223,98 @@ type SelectSectionResponse struct {
 
 // WordEntity 单词实体
 type WordEntity struct {
+	ID     string `json:"id"`     // 稳定的唯一标识，创建时生成
 	W      string `json:"W"`      // 原始单词
-	C      string `json:"C"`      // 中文释义
-	Phrase string `json:"Phrase"` // 对应短语
+	C      string `json:"C"`      // 中文释义（主释义，练习时使用）
+	Phrase string `json:"Phrase"` // 对应短语（主例句）
+
+	POS    string   `json:"pos,omitempty"`    // 词性，如 n. v. adj.
+	Senses []Sense  `json:"senses,omitempty"` // 全部义项，第一个义项通常与C、Phrase一致
+	Tags   []string `json:"tags,omitempty"`   // 标签
+	Notes  string   `json:"notes,omitempty"`  // 备注
+
+	CreatedAt time.Time `json:"created_at"` // 创建时间
+	UpdatedAt time.Time `json:"updated_at"` // 内容最近一次修改的时间，复习不会更新该时间
+
+	Review  *ReviewState   `json:"review,omitempty"`  // 复习状态，未复习过为nil
+	Mistake *MistakeRecord `json:"mistake,omitempty"` // 错题记录，仅错题本中的单词有
+}
+
+// Sense 单词的一个义项
+type Sense struct {
+	Meaning  string   `json:"meaning"`            // 释义
+	Examples []string `json:"examples,omitempty"` // 该义项的例句
+}
+
+// MistakeSectionName 错题本章节名称，练习中答错的单词会自动加入
+const MistakeSectionName = "错题本"
+
+// IsManagedSection 判断章节是否由程序自动维护，这类章节不能手动创建、删除或重命名
+func IsManagedSection(name string) bool {
+	return name == MistakeSectionName
 }
 
 // SectionEntity 章节实体
 type SectionEntity struct {
 	Name  string       `json:"name"`  // 章节名称
 	Words []WordEntity `json:"words"` // 章节中的单词
+
+	// CreatedAt 创建时间，由DAO设置；CreateSection时为零值表示使用当前时间，UpdateSection不会修改
+	CreatedAt time.Time `json:"created_at"`
+
+	// Archived 是否已归档，归档的章节不显示在章节列表中；CreateSection、UpdateSection按该值保存
+	Archived bool `json:"archived,omitempty"`
+
+	// Revision 读取时章节内容的版本，由DAO设置；更新时用于检测章节是否已被其他进程修改，为空表示不检查
+	Revision string `json:"-"`
 }
 
 // ===== DAO层数据结构体 =====
 
-// WordsDataDAO JSON文件的数据结构
+// CurrentSchemaVersion 当前数据文件格式版本
+// 版本1为章节名到单词列表的映射，版本2起为带版本号的对象
+const CurrentSchemaVersion = 2
+
+// WordsFileDAO 版本化的JSON数据文件结构
+type WordsFileDAO struct {
+	Version  int          `json:"version"`
+	Sections []SectionDAO `json:"sections"`
+}
+
+// WordsDataDAO 版本1的JSON文件数据结构（章节名到单词列表的映射），加载时会自动迁移
 type WordsDataDAO map[string][]WordEntity
 
 // SectionDAO 章节DAO结构体
 type SectionDAO struct {
-	Name  string       `json:"name"`
-	Words []WordEntity `json:"words"`
+	Name      string       `json:"name"`
+	CreatedAt time.Time    `json:"created_at"`
+	Words     []WordEntity `json:"words"`
+	Archived  bool         `json:"archived,omitempty"`
 }
 
+// LibraryIndexFileName 目录词库的索引文件名
+const LibraryIndexFileName = "index.json"
+
+// LibraryIndexDAO 目录词库的索引文件结构，记录章节顺序和元数据，每个章节的单词保存在单独的文件中
+// 索引只记录很少变化的信息（不含单词数、修改时间），修改单词时只有对应的章节文件发生变化
+type LibraryIndexDAO struct {
+	Version  int                  `json:"version"`
+	Sections []LibrarySectionMeta `json:"sections"`
+}
+
+// LibrarySectionMeta 目录词库中单个章节的元数据
+type LibrarySectionMeta struct {
+	Name      string    `json:"name"`
+	File      string    `json:"file"`       // 章节文件名，相对于词库目录
+	CreatedAt time.Time `json:"created_at"` // 章节创建时间
+}
+
+// LogStoreExt 日志存储的数据文件扩展名，这种文件由内存索引加追加写入的日志组成，适合很大的词库
+const LogStoreExt = ".vlog"
+
+// LogStoreFormat 日志存储文件头中的格式标识
+const LogStoreFormat = "englishLearn-log"
+
 // ===== 兼容性别名 (向后兼容) =====
 
 // Word 单词结构体 (已废弃，使用WordEntity)
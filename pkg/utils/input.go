@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// stdinReader 共享的标准输入读取器，避免多个读取器各自缓冲导致输入丢失
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// ReadLine 读取一整行输入并去除前后空白，支持包含空格的内容
+func ReadLine() (string, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Prompt 打印提示信息并读取一整行输入
+func Prompt(prompt string) (string, error) {
+	fmt.Print(prompt)
+	return ReadLine()
+}